@@ -181,3 +181,107 @@ export const fetchContexts = async (
     if (!res.ok) throw new Error(`FetchContexts failed: ${res.status}`);
     return res.json();
 };
+
+// --- Events ---
+
+export type EventType =
+    | 'seed.created'
+    | 'seed.updated'
+    | 'seed.merged'
+    | 'seed.deleted'
+    | 'context.created'
+    | 'goal.completed';
+
+export interface BrainEvent {
+    id: number;
+    type: EventType;
+    seedId?: number;
+    contextId?: string;
+    appId?: string;
+    externalUserId?: string;
+    payload: Record<string, unknown>;
+    createdAt: string;
+}
+
+/**
+ * GET /events – Änderungen als Server-Sent Events abonnieren.
+ * EventSource verbindet sich selbst neu und holt Verpasstes über Last-Event-ID nach.
+ * Gibt eine Funktion zum Beenden des Abos zurück.
+ */
+export const subscribeEvents = (
+    types: EventType[],
+    onEvent: (event: BrainEvent) => void
+): (() => void) => {
+    const params = new URLSearchParams();
+    if (types.length > 0) params.set('types', types.join(','));
+    const qs = params.toString();
+    const source = new EventSource(`${API_BASE}/events${qs ? '?' + qs : ''}`);
+    const handle = (e: MessageEvent) => onEvent(JSON.parse(e.data) as BrainEvent);
+    const names: EventType[] = types.length > 0
+        ? types
+        : ['seed.created', 'seed.updated', 'seed.merged', 'seed.deleted', 'context.created', 'goal.completed'];
+    names.forEach((name) => source.addEventListener(name, handle));
+    return () => source.close();
+};
+
+// --- Metrics ---
+
+export interface MetricSeriesPoint {
+    t: string;
+    v: number;
+    n: number;
+}
+
+export interface MetricSeries {
+    name: string;
+    from: string;
+    to: string;
+    step: string;
+    agg: string;
+    points: MetricSeriesPoint[];
+}
+
+/**
+ * GET /metrics/series – Zeitreihe in Buckets (z. B. seeds_total für Wachstums-Charts).
+ */
+export const fetchMetricSeries = async (
+    name: string,
+    options: { from?: string; to?: string; step?: string; agg?: string; appId?: string; externalUserId?: string } = {}
+): Promise<MetricSeries> => {
+    const params = new URLSearchParams({ name });
+    for (const [key, value] of Object.entries(options)) {
+        if (value) params.set(key, value);
+    }
+    const res = await fetch(`${API_BASE}/metrics/series?${params}`);
+    if (!res.ok) throw new Error(`MetricSeries failed: ${res.status}`);
+    return res.json();
+};
+
+// --- Clusters ---
+
+export interface Cluster {
+    id: number;
+    label: number;
+    size: number;
+    keywords: string[];
+    representatives: SearchResult[];
+    computedAt: string;
+}
+
+/**
+ * GET /clusters – Themen-Cluster mit Größe, Keywords und repräsentativen Seeds.
+ */
+export const fetchClusters = async (): Promise<Cluster[]> => {
+    const res = await fetch(`${API_BASE}/clusters`);
+    if (!res.ok) throw new Error(`FetchClusters failed: ${res.status}`);
+    return res.json();
+};
+
+/**
+ * GET /clusters/{id}/seeds – Mitglieder eines Clusters, zentralste zuerst.
+ */
+export const fetchClusterSeeds = async (id: number, limit = 100): Promise<SearchResult[]> => {
+    const res = await fetch(`${API_BASE}/clusters/${id}/seeds?limit=${limit}`);
+    if (!res.ok) throw new Error(`FetchClusterSeeds failed: ${res.status}`);
+    return res.json();
+};
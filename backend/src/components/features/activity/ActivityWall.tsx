@@ -1,6 +1,6 @@
 import React, { useState, useEffect } from 'react';
 import type { AgentContext } from '../../../api';
-import { fetchContexts } from '../../../api';
+import { fetchContexts, subscribeEvents } from '../../../api';
 import { formatDate } from '../../../utils/formatDate';
 
 const MEMORY_TYPE_COLORS: Record<string, string> = {
@@ -35,8 +35,9 @@ export const ActivityWall: React.FC = () => {
 
     useEffect(() => {
         load();
-        const interval = setInterval(load, 8000);
-        return () => clearInterval(interval);
+        return subscribeEvents(['context.created'], (event) => {
+            if (!filter || event.payload.memoryType === filter) load();
+        });
     }, [filter]);
 
     const payloadSummary = (payload: Record<string, unknown>): string => {
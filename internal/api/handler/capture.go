@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/capture"
+	"github.com/cabroe/neural-brain/internal/model"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// captureDupSimilarity skips candidates this close to an existing seed of the same tenant.
+const captureDupSimilarity = 0.9
+
+// HandleCapture handles POST /capture: extract memory statements from a conversation turn and store the new ones.
+func HandleCapture(s *store.Store, ex capture.Extractor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.CaptureRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		var turn []capture.Message
+		for _, m := range req.Messages {
+			if strings.TrimSpace(m.Text) != "" {
+				turn = append(turn, capture.Message{Role: m.Role, Text: m.Text})
+			}
+		}
+		if len(turn) == 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "messages required")
+			return
+		}
+		if req.AppID == "" {
+			req.AppID = r.URL.Query().Get("appId")
+		}
+		if req.ExternalUserID == "" {
+			req.ExternalUserID = r.URL.Query().Get("externalUserId")
+		}
+
+		candidates, err := ex.Extract(r.Context(), turn)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		resp := apilib.CaptureResponse{Stored: []apilib.CapturedSeed{}, Skipped: []apilib.CapturedSeed{}}
+		type survivor struct {
+			candidate capture.Candidate
+			embedding []float32
+		}
+		var survivors []survivor
+		for _, c := range candidates {
+			emb, err := model.Embed(c.Content)
+			if err != nil {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			nearest, err := s.Search(r.Context(), emb, 1, nil, req.AppID, req.ExternalUserID)
+			if err != nil {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if len(nearest) > 0 && nearest[0].Score >= captureDupSimilarity {
+				resp.Skipped = append(resp.Skipped, apilib.CapturedSeed{Content: c.Content, Reason: "duplicate", DuplicateOf: nearest[0].ID})
+				continue
+			}
+			survivors = append(survivors, survivor{c, emb})
+		}
+		if len(survivors) == 0 {
+			apilib.RespondJSON(w, http.StatusOK, resp)
+			return
+		}
+
+		// Keep the raw turn as an episodic context so every captured seed can point back to it.
+		agentID := strings.TrimSpace(req.AgentID)
+		if agentID == "" {
+			agentID = req.AppID
+		}
+		if agentID == "" {
+			agentID = "auto_capture"
+		}
+		turnPayload, _ := json.Marshal(map[string]interface{}{"messages": turn, "turnId": req.TurnID, "source": "auto_capture"})
+		ctxID, err := s.InsertContext(r.Context(), agentID, "episodic", turnPayload, req.AppID, req.ExternalUserID)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		resp.TurnContextID = ctxID
+
+		capturedAt := time.Now().UTC().Format(time.RFC3339)
+		for _, sv := range survivors {
+			meta := map[string]interface{}{
+				"source":        "auto_capture",
+				"tags":          []string{"auto_capture"},
+				"turnContextId": ctxID,
+				"capturedAt":    capturedAt,
+			}
+			if sv.candidate.Role != "" {
+				meta["role"] = sv.candidate.Role
+			}
+			if req.TurnID != "" {
+				meta["turnId"] = req.TurnID
+			}
+			metadata, _ := json.Marshal(meta)
+			id, err := s.Insert(r.Context(), sv.candidate.Content, metadata, sv.embedding, req.AppID, req.ExternalUserID)
+			if err != nil {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			resp.Stored = append(resp.Stored, apilib.CapturedSeed{ID: id, Content: sv.candidate.Content})
+		}
+		apilib.RespondJSON(w, http.StatusCreated, resp)
+	}
+}
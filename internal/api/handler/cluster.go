@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/cluster"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+const (
+	defaultClusterKeywords = 5
+	clusterRepresentatives = 3
+	clusterSeed            = 1 // fixed so repeated runs over the same seeds agree
+	maxClusterKeywords     = 20
+	maxClusterIterations   = 100
+)
+
+// HandleComputeClusters handles POST /clusters/compute: k-means over the tenant's seed embeddings.
+// The result replaces the tenant's previous clusters; seeds stored later join the nearest centroid.
+func HandleComputeClusters(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.ComputeClustersRequest
+		if r.ContentLength != 0 {
+			if err := apilib.DecodeJSON(r, &req); err != nil {
+				apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+				return
+			}
+		}
+		if req.K < 0 || req.K > cluster.MaxK {
+			apilib.RespondError(w, http.StatusBadRequest, "k must be between 1 and "+strconv.Itoa(cluster.MaxK))
+			return
+		}
+		if req.MaxIterations < 0 || req.MaxIterations > maxClusterIterations {
+			apilib.RespondError(w, http.StatusBadRequest, "maxIterations must be at most "+strconv.Itoa(maxClusterIterations))
+			return
+		}
+		nKeywords := req.Keywords
+		if nKeywords <= 0 {
+			nKeywords = defaultClusterKeywords
+		}
+		if nKeywords > maxClusterKeywords {
+			nKeywords = maxClusterKeywords
+		}
+		appID, externalUserID := r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId")
+
+		seeds, err := s.ClusterCandidates(r.Context(), appID, externalUserID)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if len(seeds) < 2 {
+			apilib.RespondError(w, http.StatusBadRequest, "at least 2 seeds required")
+			return
+		}
+		k := req.K
+		if k == 0 {
+			k = cluster.AutoK(len(seeds))
+		}
+
+		vectors := make([][]float32, len(seeds))
+		texts := make([]string, len(seeds))
+		for i, se := range seeds {
+			vectors[i], texts[i] = se.Embedding, se.Content
+		}
+		assign, centroids := cluster.KMeans(vectors, k, req.MaxIterations, clusterSeed)
+		keywords := cluster.Keywords(texts, assign, len(centroids), nKeywords)
+
+		clusters := make([]store.NewCluster, len(centroids))
+		for c := range clusters {
+			clusters[c] = store.NewCluster{Centroid: centroids[c], Keywords: keywords[c]}
+		}
+		for i, se := range seeds {
+			c := &clusters[assign[i]]
+			c.SeedIDs = append(c.SeedIDs, se.ID)
+			c.Similarities = append(c.Similarities, store.Cosine(se.Embedding, c.Centroid))
+		}
+		nonEmpty := clusters[:0]
+		for _, c := range clusters {
+			if len(c.SeedIDs) == 0 {
+				continue
+			}
+			order := make([]int, len(c.SeedIDs))
+			for i := range order {
+				order[i] = i
+			}
+			sort.Slice(order, func(a, b int) bool { return c.Similarities[order[a]] > c.Similarities[order[b]] })
+			for i := 0; i < len(order) && i < clusterRepresentatives; i++ {
+				c.RepresentativeIDs = append(c.RepresentativeIDs, c.SeedIDs[order[i]])
+			}
+			nonEmpty = append(nonEmpty, c)
+		}
+
+		if err := s.ReplaceClusters(r.Context(), appID, externalUserID, nonEmpty); err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		list, err := s.ListClusters(r.Context(), appID, externalUserID)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{"seeds": len(seeds), "k": len(list), "clusters": list})
+	}
+}
+
+// HandleListClusters handles GET /clusters: the tenant's clusters with sizes, keywords and representative seeds.
+func HandleListClusters(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		list, err := s.ListClusters(r.Context(), r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if list == nil {
+			list = []store.Cluster{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, list)
+	}
+}
+
+// HandleClusterSeeds handles GET /clusters/{id}/seeds?limit=: members of a cluster, most central first.
+func HandleClusterSeeds(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		exists, err := s.ClusterExists(r.Context(), id)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !exists {
+			apilib.RespondError(w, http.StatusNotFound, "not found")
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		seeds, err := s.ClusterSeeds(r.Context(), id, limit)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if seeds == nil {
+			seeds = []store.Seed{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, seeds)
+	}
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/chunk"
+	"github.com/cabroe/neural-brain/internal/model"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+const (
+	maxDocumentBytes = 10 << 20
+	// maxDocumentChunks bounds the synchronous embedding run; documentWriteTimeout replaces the server's
+	// 10 s write timeout for this route so that a document at the limit is answered before the client is cut
+	// off (and retries a write that has already been committed).
+	maxDocumentChunks    = 1000
+	documentWriteTimeout = 5 * time.Minute
+)
+
+var documentFormats = map[string]string{"text": "text/plain", "markdown": "text/markdown"}
+
+// HandleCreateDocument handles POST /documents: JSON body or multipart "file" upload.
+// The document is split into overlapping chunks, each stored and embedded as a seed.
+func HandleCreateDocument(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxDocumentBytes+1<<20)
+
+		var req apilib.CreateDocumentRequest
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			if err := r.ParseMultipartForm(maxDocumentBytes); err != nil {
+				if isBodyTooLarge(err) {
+					respondDocumentTooLarge(w)
+					return
+				}
+				apilib.RespondError(w, http.StatusBadRequest, "invalid multipart form")
+				return
+			}
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				apilib.RespondError(w, http.StatusBadRequest, "file required")
+				return
+			}
+			defer file.Close()
+			data, err := io.ReadAll(io.LimitReader(file, maxDocumentBytes+1))
+			if err != nil {
+				apilib.RespondError(w, http.StatusBadRequest, "could not read file")
+				return
+			}
+			if len(data) > maxDocumentBytes {
+				respondDocumentTooLarge(w)
+				return
+			}
+			req.Content = string(data)
+			req.Title = r.FormValue("title")
+			if req.Title == "" {
+				req.Title = header.Filename
+			}
+			req.Format = r.FormValue("format")
+			if req.Format == "" {
+				switch strings.ToLower(filepath.Ext(header.Filename)) {
+				case ".md", ".markdown":
+					req.Format = "markdown"
+				}
+			}
+			req.ChunkTokens, _ = strconv.Atoi(r.FormValue("chunkTokens"))
+			if v := r.FormValue("overlapTokens"); v != "" {
+				if n, err := strconv.Atoi(v); err == nil {
+					req.OverlapTokens = &n
+				}
+			}
+		} else if err := apilib.DecodeJSON(r, &req); err != nil {
+			if isBodyTooLarge(err) {
+				respondDocumentTooLarge(w)
+				return
+			}
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		if len(req.Content) > maxDocumentBytes {
+			respondDocumentTooLarge(w)
+			return
+		}
+
+		if strings.TrimSpace(req.Content) == "" {
+			apilib.RespondError(w, http.StatusBadRequest, "content required")
+			return
+		}
+		format := strings.ToLower(strings.TrimSpace(req.Format))
+		if format == "" {
+			format = "text"
+		}
+		mimeType, ok := documentFormats[format]
+		if !ok {
+			apilib.RespondError(w, http.StatusBadRequest, "format must be one of: text, markdown")
+			return
+		}
+		maxTokens := req.ChunkTokens
+		if maxTokens <= 0 || maxTokens > chunk.DefaultMaxTokens {
+			maxTokens = chunk.DefaultMaxTokens
+		}
+		overlap := chunk.DefaultOverlapTokens
+		if req.OverlapTokens != nil {
+			overlap = *req.OverlapTokens
+		}
+		if overlap < 0 || overlap >= maxTokens {
+			apilib.RespondError(w, http.StatusBadRequest, "overlapTokens must be between 0 and chunkTokens")
+			return
+		}
+
+		chunks := chunk.Split(req.Content, maxTokens, overlap)
+		if len(chunks) > maxDocumentChunks {
+			apilib.RespondError(w, http.StatusRequestEntityTooLarge,
+				"document splits into "+strconv.Itoa(len(chunks))+" chunks, limit "+strconv.Itoa(maxDocumentChunks)+"; upload it in parts")
+			return
+		}
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(documentWriteTimeout)); err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		texts := make([]string, len(chunks))
+		for i, c := range chunks {
+			texts[i] = c.Text
+		}
+		embeddings, err := model.EmbedBatch(texts)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		metadata := req.Metadata
+		if metadata == nil {
+			metadata = []byte("{}")
+		}
+		doc := store.Document{
+			Title:          strings.TrimSpace(req.Title),
+			Content:        req.Content,
+			MimeType:       mimeType,
+			Metadata:       metadata,
+			AppID:          r.URL.Query().Get("appId"),
+			ExternalUserID: r.URL.Query().Get("externalUserId"),
+		}
+		id, seedIDs, err := s.InsertDocument(r.Context(), doc, chunks, embeddings)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		apilib.RespondJSON(w, http.StatusCreated, map[string]interface{}{"id": id, "chunks": len(chunks), "seedIds": seedIDs})
+	}
+}
+
+// HandleListDocuments handles GET /documents?limit=...
+func HandleListDocuments(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		list, err := s.ListDocuments(r.Context(), limit, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if list == nil {
+			list = []store.Document{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, list)
+	}
+}
+
+// HandleGetDocument handles GET /documents/{id}.
+func HandleGetDocument(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		doc, err := s.GetDocument(r.Context(), id)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if doc == nil {
+			apilib.RespondError(w, http.StatusNotFound, "not found")
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, doc)
+	}
+}
+
+// isBodyTooLarge reports whether err comes from the http.MaxBytesReader around the request body.
+func isBodyTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+func respondDocumentTooLarge(w http.ResponseWriter) {
+	apilib.RespondError(w, http.StatusRequestEntityTooLarge, "document exceeds "+strconv.Itoa(maxDocumentBytes>>20)+" MiB")
+}
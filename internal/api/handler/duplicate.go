@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+const (
+	defaultDuplicateThreshold = 0.95
+	defaultDuplicateGroups    = 50
+	maxDuplicateGroups        = 500
+)
+
+// HandleFindDuplicates handles GET /duplicates?threshold=0.95&limit=50: groups of near-duplicate seeds per tenant.
+func HandleFindDuplicates(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		threshold := defaultDuplicateThreshold
+		if v := r.URL.Query().Get("threshold"); v != "" {
+			t, err := strconv.ParseFloat(v, 64)
+			if err != nil || t <= 0 || t > 1 {
+				apilib.RespondError(w, http.StatusBadRequest, "threshold must be between 0 and 1")
+				return
+			}
+			threshold = t
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit <= 0 {
+			limit = defaultDuplicateGroups
+		}
+		if limit > maxDuplicateGroups {
+			limit = maxDuplicateGroups
+		}
+
+		groups, err := s.FindDuplicates(r.Context(), threshold, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"), limit)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if groups == nil {
+			groups = []store.DuplicateGroup{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{
+			"threshold": threshold,
+			"groups":    groups,
+		})
+	}
+}
+
+// HandleMergeDuplicates handles POST /duplicates/merge: fold seedIds into survivorId and delete them.
+// Without survivorId the lowest (oldest) ID survives.
+func HandleMergeDuplicates(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.MergeDuplicatesRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		survivor := req.SurvivorID
+		seen := map[int64]bool{}
+		var others []int64
+		for _, id := range req.SeedIDs {
+			if id <= 0 {
+				apilib.RespondError(w, http.StatusBadRequest, "invalid seed id")
+				return
+			}
+			if req.SurvivorID == 0 && (survivor == 0 || id < survivor) {
+				survivor = id
+			}
+		}
+		for _, id := range req.SeedIDs {
+			if id != survivor && !seen[id] {
+				seen[id] = true
+				others = append(others, id)
+			}
+		}
+		if survivor <= 0 || len(others) == 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "at least two distinct seeds required")
+			return
+		}
+
+		seed, err := s.MergeSeeds(r.Context(), survivor, others)
+		if errors.Is(err, store.ErrSeedNotFound) {
+			apilib.RespondError(w, http.StatusNotFound, "not found")
+			return
+		}
+		if errors.Is(err, store.ErrTenantMismatch) {
+			apilib.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{
+			"seed":      seed,
+			"mergedIds": others,
+		})
+	}
+}
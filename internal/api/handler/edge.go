@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// HandleCreateEdge handles POST /seeds/{id}/edges?appId=&externalUserId=: a typed edge from seed {id} to targetId.
+// Both seeds must belong to the tenant. Posting an existing (source, target, type) edge again updates its weight.
+func HandleCreateEdge(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		var req apilib.CreateEdgeRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		if req.TargetID <= 0 || req.TargetID == id {
+			apilib.RespondError(w, http.StatusBadRequest, "targetId must be another seed")
+			return
+		}
+		if !store.ValidEdgeType(req.Type) {
+			apilib.RespondError(w, http.StatusBadRequest, "type must be derived_from, contradicts, supports, parent_of or same_entity")
+			return
+		}
+		weight := 1.0
+		if req.Weight != nil {
+			weight = *req.Weight
+		}
+		q := r.URL.Query()
+		edgeID, err := s.InsertEdge(r.Context(), store.Edge{SourceID: id, TargetID: req.TargetID, Type: req.Type, Weight: weight},
+			q.Get("appId"), q.Get("externalUserId"))
+		if err != nil {
+			if err == store.ErrSeedNotFound {
+				apilib.RespondError(w, http.StatusNotFound, err.Error())
+			} else {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		apilib.RespondJSON(w, http.StatusCreated, map[string]int64{"id": edgeID})
+	}
+}
+
+// HandleListEdges handles GET /seeds/{id}/edges?appId=&externalUserId=: all edges of the tenant touching the seed.
+func HandleListEdges(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		edges, err := s.ListEdges(r.Context(), id, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if edges == nil {
+			edges = []store.Edge{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, edges)
+	}
+}
+
+// HandleDeleteEdges handles DELETE /seeds/{id}/edges?targetId=...&type=...: removes the edge(s) from seed {id}
+// to targetId, of one type or of all types when type is omitted.
+func HandleDeleteEdges(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		targetID, err := strconv.ParseInt(r.URL.Query().Get("targetId"), 10, 64)
+		if err != nil || targetID <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "targetId required")
+			return
+		}
+		edgeType := r.URL.Query().Get("type")
+		if edgeType != "" && !store.ValidEdgeType(edgeType) {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid type")
+			return
+		}
+		n, err := s.DeleteEdges(r.Context(), id, targetID, edgeType, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if n == 0 {
+			apilib.RespondError(w, http.StatusNotFound, "not found")
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]int64{"deleted": n})
+	}
+}
+
+// HandleNeighbors handles GET /seeds/{id}/neighbors?depth=1&types=supports,contradicts: seeds reachable
+// through edges in either direction, nearest first, each with the edge it was reached by.
+func HandleNeighbors(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		depth := 1
+		if v := r.URL.Query().Get("depth"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > store.MaxNeighborDepth {
+				apilib.RespondError(w, http.StatusBadRequest, "depth must be between 1 and "+strconv.Itoa(store.MaxNeighborDepth))
+				return
+			}
+			depth = n
+		}
+		var types []string
+		if v := r.URL.Query().Get("types"); v != "" {
+			for _, t := range strings.Split(v, ",") {
+				t = strings.TrimSpace(t)
+				if !store.ValidEdgeType(t) {
+					apilib.RespondError(w, http.StatusBadRequest, "invalid type "+t)
+					return
+				}
+				types = append(types, t)
+			}
+		}
+
+		seed, err := s.GetSeed(r.Context(), id)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if seed == nil {
+			apilib.RespondError(w, http.StatusNotFound, "not found")
+			return
+		}
+		seeds, err := s.Neighbors(r.Context(), []int64{id}, depth, types, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if seeds == nil {
+			seeds = []store.Seed{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, seeds)
+	}
+}
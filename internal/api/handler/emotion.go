@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+const defaultEmotionHistoryWindow = 7 * 24 * time.Hour
+
+// HandleGetEmotion handles GET /agents/{id}/emotion: current state with decay toward the baseline applied.
+func HandleGetEmotion(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		agentID := strings.TrimSpace(r.PathValue("id"))
+		if agentID == "" {
+			apilib.RespondError(w, http.StatusBadRequest, "id required")
+			return
+		}
+		e, err := s.GetEmotion(r.Context(), agentID, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, e)
+	}
+}
+
+// HandleSetEmotion handles PUT /agents/{id}/emotion: set an absolute state and optionally the decay settings.
+func HandleSetEmotion(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		agentID := strings.TrimSpace(r.PathValue("id"))
+		if agentID == "" {
+			apilib.RespondError(w, http.StatusBadRequest, "id required")
+			return
+		}
+		var req apilib.SetEmotionRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		if req.Valence == nil || req.Arousal == nil || req.Dominance == nil {
+			apilib.RespondError(w, http.StatusBadRequest, "valence, arousal and dominance required")
+			return
+		}
+		state := store.VAD{Valence: *req.Valence, Arousal: *req.Arousal, Dominance: *req.Dominance}
+		if !state.InRange() {
+			apilib.RespondError(w, http.StatusBadRequest, "valence, arousal and dominance must be between 0 and 10")
+			return
+		}
+		var settings store.EmotionSettings
+		if req.Baseline != nil {
+			baseline := store.VAD(*req.Baseline)
+			if !baseline.InRange() {
+				apilib.RespondError(w, http.StatusBadRequest, "baseline values must be between 0 and 10")
+				return
+			}
+			settings.Baseline = &baseline
+		}
+		if req.DecayHalfLifeHours != nil {
+			if *req.DecayHalfLifeHours < 0 || math.IsNaN(*req.DecayHalfLifeHours) {
+				apilib.RespondError(w, http.StatusBadRequest, "decayHalfLifeHours must not be negative")
+				return
+			}
+			d := time.Duration(*req.DecayHalfLifeHours * float64(time.Hour))
+			settings.HalfLife = &d
+		}
+
+		e, err := s.SetEmotion(r.Context(), agentID, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"), state, settings, req.Reason)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, e)
+	}
+}
+
+// HandleEmotionEvent handles POST /agents/{id}/emotion/events: apply deltas with a reason.
+func HandleEmotionEvent(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		agentID := strings.TrimSpace(r.PathValue("id"))
+		if agentID == "" {
+			apilib.RespondError(w, http.StatusBadRequest, "id required")
+			return
+		}
+		var req apilib.EmotionEventRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		for _, d := range []float64{req.Valence, req.Arousal, req.Dominance} {
+			if math.Abs(d) > store.VADMax-store.VADMin || math.IsNaN(d) {
+				apilib.RespondError(w, http.StatusBadRequest, "deltas must be between -10 and 10")
+				return
+			}
+		}
+		delta := store.VAD{Valence: req.Valence, Arousal: req.Arousal, Dominance: req.Dominance}
+		e, err := s.ApplyEmotionEvent(r.Context(), agentID, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"), delta, req.Reason)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, e)
+	}
+}
+
+// HandleEmotionHistory handles GET /agents/{id}/emotion/history?from=...&to=...&limit=... (RFC 3339 times).
+func HandleEmotionHistory(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		agentID := strings.TrimSpace(r.PathValue("id"))
+		if agentID == "" {
+			apilib.RespondError(w, http.StatusBadRequest, "id required")
+			return
+		}
+		from, to, ok := parseTimeRange(w, r, defaultEmotionHistoryWindow)
+		if !ok {
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		events, err := s.EmotionHistory(r.Context(), agentID, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"), from, to, limit)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if events == nil {
+			events = []store.EmotionEvent{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, events)
+	}
+}
+
+// parseTimeRange reads RFC 3339 from/to query params; to defaults to now and from to window before to.
+func parseTimeRange(w http.ResponseWriter, r *http.Request, window time.Duration) (time.Time, time.Time, bool) {
+	to := time.Now()
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "to must be RFC 3339")
+			return time.Time{}, time.Time{}, false
+		}
+		to = t
+	}
+	from := to.Add(-window)
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "from must be RFC 3339")
+			return time.Time{}, time.Time{}, false
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		apilib.RespondError(w, http.StatusBadRequest, "from must be before to")
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// HandleCreateEntity handles POST /entities: register a name with aliases (e.g. a person's nicknames).
+// Existing seeds mentioning any of the names are linked immediately; new seeds are linked on insert.
+func HandleCreateEntity(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.CreateEntityRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" {
+			apilib.RespondError(w, http.StatusBadRequest, "name required")
+			return
+		}
+		aliases := make([]string, 0, len(req.Aliases))
+		for _, a := range req.Aliases {
+			if a = strings.TrimSpace(a); a != "" {
+				aliases = append(aliases, a)
+			}
+		}
+		en, err := s.CreateEntity(r.Context(), req.Name, strings.TrimSpace(req.Kind), aliases,
+			r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		apilib.RespondJSON(w, http.StatusCreated, en)
+	}
+}
+
+// HandleListEntities handles GET /entities?q=&kind=&limit=: entities ordered by number of mentioning seeds.
+func HandleListEntities(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		q := r.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		list, err := s.ListEntities(r.Context(), strings.TrimSpace(q.Get("q")), q.Get("kind"), limit, q.Get("appId"), q.Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if list == nil {
+			list = []store.Entity{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, list)
+	}
+}
+
+// HandleGetEntity handles GET /entities/{id}.
+func HandleGetEntity(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		en, err := s.GetEntity(r.Context(), id)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if en == nil {
+			apilib.RespondError(w, http.StatusNotFound, "not found")
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, en)
+	}
+}
+
+// HandleEntitySeeds handles GET /entities/{id}/seeds?limit=: seeds mentioning the entity, newest first.
+func HandleEntitySeeds(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		en, err := s.GetEntity(r.Context(), id)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if en == nil {
+			apilib.RespondError(w, http.StatusNotFound, "not found")
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		seeds, err := s.EntitySeeds(r.Context(), id, limit)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if seeds == nil {
+			seeds = []store.Seed{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, seeds)
+	}
+}
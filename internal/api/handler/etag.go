@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// versionETag formats a seed version as a strong ETag.
+func versionETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatchVersions reads the If-Match header for a version-checked write: the versions of the comma-separated
+// entity tags, or nil (unconditional) without the header or with "*". Entries that cannot equal any of our
+// ETags, such as weak or foreign tags, are skipped; if none is left it writes 412 and returns false.
+func ifMatchVersions(w http.ResponseWriter, r *http.Request) ([]int64, bool) {
+	h := strings.TrimSpace(r.Header.Get("If-Match"))
+	if h == "" || h == "*" {
+		return nil, true
+	}
+	var versions []int64
+	for _, tag := range strings.Split(h, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) >= 2 && tag[0] == '"' && tag[len(tag)-1] == '"' {
+			if v, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64); err == nil && v > 0 {
+				versions = append(versions, v)
+			}
+		}
+	}
+	if len(versions) == 0 {
+		apilib.RespondError(w, http.StatusPreconditionFailed, "If-Match does not match the current version")
+		return nil, false
+	}
+	return versions, true
+}
+
+// respondWriteError maps errors of version-checked seed writes to 404, 412 or 500. A missing seed is 412
+// when the request carried If-Match, even "*": the precondition is that the seed exists.
+func respondWriteError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case pgx.ErrNoRows:
+		if r.Header.Get("If-Match") != "" {
+			apilib.RespondError(w, http.StatusPreconditionFailed, "seed does not exist")
+			return
+		}
+		apilib.RespondError(w, http.StatusNotFound, "not found")
+	case store.ErrVersionConflict:
+		apilib.RespondError(w, http.StatusPreconditionFailed, "seed was modified; fetch it again and retry with the new ETag")
+	default:
+		apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
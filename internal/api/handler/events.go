@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/events"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+const (
+	eventReplayBatch = 500
+	eventHeartbeat   = 25 * time.Second
+	eventRetryMillis = 3000
+)
+
+// HandleEvents handles GET /events?types=seed.created,context.created&appId=&externalUserId=: a Server-Sent
+// Events stream of seed and context changes. A Last-Event-ID header (or lastEventId query parameter) replays
+// everything recorded after that ID before switching to live events.
+func HandleEvents(s *store.Store, hub *events.Hub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var types []string
+		if v := r.URL.Query().Get("types"); v != "" {
+			for _, t := range strings.Split(v, ",") {
+				t = strings.TrimSpace(t)
+				if t == "" {
+					continue
+				}
+				if !store.ValidEventType(t) {
+					apilib.RespondError(w, http.StatusBadRequest, "unknown event type: "+t)
+					return
+				}
+				types = append(types, t)
+			}
+		}
+		var resumeID int64
+		resume := r.Header.Get("Last-Event-ID")
+		if resume == "" {
+			resume = r.URL.Query().Get("lastEventId")
+		}
+		if resume != "" {
+			id, err := strconv.ParseInt(resume, 10, 64)
+			if err != nil || id < 0 {
+				apilib.RespondError(w, http.StatusBadRequest, "invalid Last-Event-ID")
+				return
+			}
+			resumeID = id
+		}
+		appID := r.URL.Query().Get("appId")
+		externalUserID := r.URL.Query().Get("externalUserId")
+
+		rc := http.NewResponseController(w)
+		// The stream outlives the server's write timeout.
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, "streaming not supported")
+			return
+		}
+
+		// Subscribe before replaying so nothing recorded in between is lost; duplicates are skipped by cursor.
+		sub := hub.Subscribe(types, appID, externalUserID)
+		defer hub.Unsubscribe(sub)
+		var cursor store.EventCursor
+		if resume != "" {
+			c, err := s.EventCursorAt(r.Context(), resumeID)
+			if err != nil {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			cursor = c
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", eventRetryMillis)
+
+		if resume != "" {
+			for {
+				batch, err := s.EventsAfter(r.Context(), cursor, types, appID, externalUserID, eventReplayBatch)
+				if err != nil {
+					fmt.Fprintf(w, ": replay failed: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+					rc.Flush()
+					return
+				}
+				for i := range batch {
+					if writeEvent(w, &batch[i]) != nil {
+						return
+					}
+					cursor = batch[i].Cursor()
+				}
+				if len(batch) < eventReplayBatch {
+					break
+				}
+			}
+		}
+		if rc.Flush() != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(eventHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case e, ok := <-sub.C:
+				if !ok {
+					// Dropped as a slow consumer or shutting down; the client reconnects and resumes.
+					return
+				}
+				if !cursor.Before(e.Cursor()) {
+					continue
+				}
+				if writeEvent(w, &e) != nil || rc.Flush() != nil {
+					return
+				}
+				cursor = e.Cursor()
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil || rc.Flush() != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e *store.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
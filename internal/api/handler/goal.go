@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/model"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// goalText is what a goal's embedding is computed from.
+func goalText(title, description string) string {
+	if description == "" {
+		return title
+	}
+	return title + "\n" + description
+}
+
+// HandleCreateGoal handles POST /goals.
+func HandleCreateGoal(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.CreateGoalRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		req.Title = strings.TrimSpace(req.Title)
+		if req.Title == "" {
+			apilib.RespondError(w, http.StatusBadRequest, "title required")
+			return
+		}
+		var deadline *time.Time
+		if req.Deadline != "" {
+			t, err := time.Parse(time.RFC3339, req.Deadline)
+			if err != nil {
+				apilib.RespondError(w, http.StatusBadRequest, "deadline must be RFC 3339")
+				return
+			}
+			deadline = &t
+		}
+
+		emb, err := model.Embed(goalText(req.Title, req.Description))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		g := store.Goal{
+			ParentID:       req.ParentID,
+			Title:          req.Title,
+			Description:    strings.TrimSpace(req.Description),
+			Priority:       req.Priority,
+			AppID:          r.URL.Query().Get("appId"),
+			ExternalUserID: r.URL.Query().Get("externalUserId"),
+		}
+		id, err := s.InsertGoal(r.Context(), g, deadline, emb)
+		if err != nil {
+			if err == store.ErrParentNotFound {
+				apilib.RespondError(w, http.StatusBadRequest, err.Error())
+			} else {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		apilib.RespondJSON(w, http.StatusCreated, map[string]int64{"id": id})
+	}
+}
+
+// HandleListGoals handles GET /goals?status=...
+func HandleListGoals(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		status, ok := goalStatusFilter(w, r)
+		if !ok {
+			return
+		}
+		list, err := s.ListGoals(r.Context(), status, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if list == nil {
+			list = []store.Goal{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, list)
+	}
+}
+
+// HandleGoalTree handles GET /goals/tree?status=...: root goals with nested children.
+func HandleGoalTree(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		status, ok := goalStatusFilter(w, r)
+		if !ok {
+			return
+		}
+		tree, err := s.GoalTree(r.Context(), status, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, tree)
+	}
+}
+
+// HandleGetGoal handles GET /goals/{id}.
+func HandleGetGoal(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		g, err := s.GetGoal(r.Context(), id)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if g == nil {
+			apilib.RespondError(w, http.StatusNotFound, "not found")
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, g)
+	}
+}
+
+// HandleUpdateGoal handles PATCH /goals/{id}. Setting status "completed" cascades to descendants.
+func HandleUpdateGoal(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		var req apilib.UpdateGoalRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+
+		u := store.GoalUpdate{Title: req.Title, Description: req.Description, Priority: req.Priority}
+		if req.Title != nil {
+			t := strings.TrimSpace(*req.Title)
+			if t == "" {
+				apilib.RespondError(w, http.StatusBadRequest, "title must not be empty")
+				return
+			}
+			u.Title = &t
+		}
+		if req.Description != nil {
+			d := strings.TrimSpace(*req.Description)
+			u.Description = &d
+		}
+		if req.Status != nil {
+			st := strings.ToLower(strings.TrimSpace(*req.Status))
+			if !store.ValidGoalStatus(st) {
+				apilib.RespondError(w, http.StatusBadRequest, "status must be one of: active, paused, completed, abandoned")
+				return
+			}
+			u.Status = &st
+		}
+		if req.Deadline != nil {
+			if *req.Deadline == "" {
+				u.ClearDeadline = true
+			} else {
+				t, err := time.Parse(time.RFC3339, *req.Deadline)
+				if err != nil {
+					apilib.RespondError(w, http.StatusBadRequest, "deadline must be RFC 3339")
+					return
+				}
+				u.Deadline = &t
+			}
+		}
+
+		current, err := s.GetGoal(r.Context(), id)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if current == nil {
+			apilib.RespondError(w, http.StatusNotFound, "not found")
+			return
+		}
+		if u.Title != nil || u.Description != nil {
+			title, description := current.Title, current.Description
+			if u.Title != nil {
+				title = *u.Title
+			}
+			if u.Description != nil {
+				description = *u.Description
+			}
+			if u.Embedding, err = model.Embed(goalText(title, description)); err != nil {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+
+		completed, err := s.UpdateGoal(r.Context(), id, u)
+		if err != nil {
+			switch err {
+			case pgx.ErrNoRows:
+				apilib.RespondError(w, http.StatusNotFound, "not found")
+			case store.ErrInvalidTransition:
+				apilib.RespondError(w, http.StatusConflict, "cannot change status from "+current.Status+" to "+*u.Status)
+			default:
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		if completed == nil {
+			completed = []int64{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "completed": completed})
+	}
+}
+
+// HandleEvaluateGoals handles POST /goals/evaluate: similarity of an action to every active goal.
+func HandleEvaluateGoals(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.EvaluateGoalsRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		if strings.TrimSpace(req.Action) == "" {
+			apilib.RespondError(w, http.StatusBadRequest, "action required")
+			return
+		}
+		emb, err := model.Embed(req.Action)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		scores, err := s.EvaluateGoals(r.Context(), emb, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if scores == nil {
+			scores = []store.GoalScore{}
+		}
+		best := 0.0
+		if len(scores) > 0 {
+			best = scores[0].Similarity
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{"score": best, "goals": scores})
+	}
+}
+
+func goalStatusFilter(w http.ResponseWriter, r *http.Request) (string, bool) {
+	status := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status")))
+	if status == "all" {
+		status = ""
+	}
+	if status != "" && !store.ValidGoalStatus(status) {
+		apilib.RespondError(w, http.StatusBadRequest, "status must be one of: all, active, paused, completed, abandoned")
+		return "", false
+	}
+	return status, true
+}
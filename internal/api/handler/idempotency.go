@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's key for a retry-safe write.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request with the same key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// MaxSeedBodyBytes and MaxContextBodyBytes bound the bodies of keyed writes, which are read into memory
+	// for the fingerprint.
+	MaxSeedBodyBytes    = 10 << 20
+	MaxContextBodyBytes = 1 << 20
+
+	maxIdempotencyKeyLen = 255
+	// idempotencyStale is how long an unfinished claim blocks its key before it counts as abandoned.
+	idempotencyStale = 5 * time.Minute
+)
+
+// Idempotent wraps a write handler with Idempotency-Key support. The first request with a key runs next and
+// its response (anything below 500) is stored for window; repeats with the same query and body get that
+// response again, marked with Idempotent-Replayed. Reusing a key for a different request is 422, a repeat
+// while the first request still runs is 409. A keyed body over maxBytes is 413. Requests without the header
+// are passed through unchanged.
+func Idempotent(s *store.Store, window time.Duration, maxBytes int64, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if !validIdempotencyKey(key) {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid Idempotency-Key (1-255 printable ASCII characters)")
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+		if isBodyTooLarge(err) {
+			apilib.RespondError(w, http.StatusRequestEntityTooLarge, "body exceeds "+strconv.FormatInt(maxBytes>>20, 10)+" MiB")
+			return
+		}
+		if err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "could not read body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		k := store.IdempotencyKey{
+			Key:            key,
+			Route:          r.Method + " " + r.URL.Path,
+			AppID:          r.URL.Query().Get("appId"),
+			ExternalUserID: r.URL.Query().Get("externalUserId"),
+			Fingerprint:    requestFingerprint(r, body),
+		}
+		stored, err := s.ClaimIdempotencyKey(r.Context(), k, window, idempotencyStale)
+		switch err {
+		case nil:
+		case store.ErrIdempotencyMismatch:
+			apilib.RespondError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		case store.ErrIdempotencyInProgress:
+			apilib.RespondError(w, http.StatusConflict, err.Error())
+			return
+		default:
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if stored != nil {
+			if stored.ContentType != "" {
+				w.Header().Set("Content-Type", stored.ContentType)
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(stored.StatusCode)
+			w.Write(stored.Body)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+
+		// Record the outcome even if the client has gone away; the retry is what needs it.
+		ctx := context.WithoutCancel(r.Context())
+		if rec.status >= 500 {
+			// Server errors are not replayed, so a retry runs the request again.
+			err = s.ReleaseIdempotencyKey(ctx, k)
+		} else {
+			err = s.CompleteIdempotencyKey(ctx, k, store.StoredResponse{
+				StatusCode:  rec.status,
+				ContentType: rec.Header().Get("Content-Type"),
+				Body:        rec.body.Bytes(),
+			})
+		}
+		if err != nil {
+			log.Printf("idempotency: key %q on %s: %v", key, k.Route, err)
+		}
+	}
+}
+
+// validIdempotencyKey accepts 1 to 255 printable ASCII characters.
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLen {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFingerprint hashes what makes two keyed requests the same: query parameters, media type and body.
+// Multipart bodies are hashed part by part without the boundary, which clients pick anew for every request.
+func requestFingerprint(r *http.Request, body []byte) string {
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	h := sha256.New()
+	io.WriteString(h, r.URL.Query().Encode())
+	h.Write([]byte{0})
+	io.WriteString(h, mediaType)
+	h.Write([]byte{0})
+	if !strings.HasPrefix(mediaType, "multipart/") || !hashMultipart(h, body, params["boundary"]) {
+		h.Write(body)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashMultipart writes a digest of the name, file name, content type and content of every part to h. It
+// reports false, without writing to h, if the body is not valid multipart.
+func hashMultipart(h hash.Hash, body []byte, boundary string) bool {
+	if boundary == "" {
+		return false
+	}
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+	parts := sha256.New()
+	for {
+		p, err := mr.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false
+		}
+		content, err := io.ReadAll(p)
+		if err != nil {
+			return false
+		}
+		for _, field := range []string{p.FormName(), p.FileName(), p.Header.Get("Content-Type")} {
+			io.WriteString(parts, field)
+			parts.Write([]byte{0})
+		}
+		io.WriteString(parts, strconv.Itoa(len(content)))
+		parts.Write([]byte{0})
+		parts.Write(content)
+	}
+	h.Write(parts.Sum(nil))
+	return true
+}
+
+// responseRecorder passes a response through and keeps a copy of status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.status = code
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/jobs"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// HandleListJobs handles GET /jobs: registered jobs with schedule, next activation and last run.
+func HandleListJobs(sc *jobs.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		statuses, err := sc.Statuses(r.Context())
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, statuses)
+	}
+}
+
+// HandleRunJob handles POST /jobs/{name}/run: start the job now. The run continues in the background;
+// poll GET /jobs/{name}/runs for the result.
+func HandleRunJob(sc *jobs.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		name := r.PathValue("name")
+		id, err := sc.Trigger(r.Context(), name, store.JobTriggerManual)
+		switch {
+		case errors.Is(err, jobs.ErrUnknownJob):
+			apilib.RespondError(w, http.StatusNotFound, "job not found")
+			return
+		case errors.Is(err, jobs.ErrJobRunning):
+			apilib.RespondError(w, http.StatusConflict, err.Error())
+			return
+		case err != nil:
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		apilib.RespondJSON(w, http.StatusAccepted, map[string]interface{}{"id": id, "name": name, "status": store.JobRunning})
+	}
+}
+
+// HandleListJobRuns handles GET /jobs/{name}/runs?limit=50: run history, newest first.
+func HandleListJobRuns(s *store.Store, sc *jobs.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		name := r.PathValue("name")
+		if !sc.Has(name) {
+			apilib.RespondError(w, http.StatusNotFound, "job not found")
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		runs, err := s.ListJobRuns(r.Context(), name, limit)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if runs == nil {
+			runs = []store.JobRun{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, runs)
+	}
+}
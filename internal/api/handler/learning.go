@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/learning"
+	"github.com/cabroe/neural-brain/internal/model"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// learningRuleFromRequest validates the request and embeds the prototype phrase of semantic rules.
+// On failure it writes the error response and returns false.
+func learningRuleFromRequest(w http.ResponseWriter, r *http.Request, req apilib.LearningRuleRequest) (store.LearningRule, bool) {
+	rule := store.LearningRule{
+		Name:           req.Name,
+		Kind:           req.Kind,
+		Pattern:        req.Pattern,
+		Threshold:      req.Threshold,
+		WindowHours:    req.WindowHours,
+		MinCount:       req.MinCount,
+		Action:         req.Action,
+		ActionParams:   req.ActionParams,
+		AppID:          r.URL.Query().Get("appId"),
+		ExternalUserID: r.URL.Query().Get("externalUserId"),
+		Enabled:        req.Enabled == nil || *req.Enabled,
+	}
+	if err := learning.Normalize(&rule); err != nil {
+		apilib.RespondError(w, http.StatusBadRequest, err.Error())
+		return rule, false
+	}
+	if rule.Kind == store.RuleSemantic {
+		emb, err := model.Embed(rule.Pattern)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return rule, false
+		}
+		rule.Embedding = emb
+	}
+	return rule, true
+}
+
+// HandleCreateLearningRule handles POST /learning/rules.
+func HandleCreateLearningRule(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.LearningRuleRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		rule, ok := learningRuleFromRequest(w, r, req)
+		if !ok {
+			return
+		}
+		id, err := s.InsertLearningRule(r.Context(), rule)
+		if err != nil {
+			if err == store.ErrRuleNameTaken {
+				apilib.RespondError(w, http.StatusConflict, err.Error())
+			} else {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		apilib.RespondJSON(w, http.StatusCreated, map[string]int64{"id": id})
+	}
+}
+
+// HandleListLearningRules handles GET /learning/rules?appId=&externalUserId=.
+func HandleListLearningRules(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		q := r.URL.Query()
+		rules, err := s.ListLearningRules(r.Context(), false, q.Get("appId"), q.Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if rules == nil {
+			rules = []store.LearningRule{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, rules)
+	}
+}
+
+// HandleGetLearningRule handles GET /learning/rules/{id}.
+func HandleGetLearningRule(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		rule, err := s.GetLearningRule(r.Context(), id)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if rule == nil {
+			apilib.RespondError(w, http.StatusNotFound, "not found")
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, rule)
+	}
+}
+
+// HandleUpdateLearningRule handles PUT /learning/rules/{id}: replace the rule definition.
+func HandleUpdateLearningRule(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		var req apilib.LearningRuleRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		rule, ok := learningRuleFromRequest(w, r, req)
+		if !ok {
+			return
+		}
+		rule.ID = id
+		if err := s.UpdateLearningRule(r.Context(), rule); err != nil {
+			switch err {
+			case pgx.ErrNoRows:
+				apilib.RespondError(w, http.StatusNotFound, "not found")
+			case store.ErrRuleNameTaken:
+				apilib.RespondError(w, http.StatusConflict, err.Error())
+			default:
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+// HandleDeleteLearningRule handles DELETE /learning/rules/{id}.
+func HandleDeleteLearningRule(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		if err := s.DeleteLearningRule(r.Context(), id); err != nil {
+			if err == pgx.ErrNoRows {
+				apilib.RespondError(w, http.StatusNotFound, "not found")
+			} else {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+// HandleEvaluateLearning handles POST /learning/evaluate: evaluate one or all enabled rules now.
+func HandleEvaluateLearning(s *store.Store, engine *learning.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.EvaluateLearningRequest
+		if r.ContentLength != 0 {
+			if err := apilib.DecodeJSON(r, &req); err != nil {
+				apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+				return
+			}
+		}
+		if req.RuleID == 0 {
+			results, err := engine.EvaluateAll(r.Context(), req.DryRun)
+			if err != nil {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			apilib.RespondJSON(w, http.StatusOK, results)
+			return
+		}
+		rule, err := s.GetLearningRule(r.Context(), req.RuleID)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if rule == nil {
+			apilib.RespondError(w, http.StatusNotFound, "rule not found")
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, []learning.Result{engine.Evaluate(r.Context(), *rule, req.DryRun)})
+	}
+}
+
+// HandleListLearningEvaluations handles GET /learning/evaluations?ruleId=&limit=50.
+func HandleListLearningEvaluations(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var ruleID int64
+		if v := r.URL.Query().Get("ruleId"); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || id <= 0 {
+				apilib.RespondError(w, http.StatusBadRequest, "invalid ruleId")
+				return
+			}
+			ruleID = id
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		evals, err := s.ListLearningEvaluations(r.Context(), ruleID, limit)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if evals == nil {
+			evals = []store.LearningEvaluation{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, evals)
+	}
+}
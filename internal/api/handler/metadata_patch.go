@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/jsonpatch"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// acceptPatch lists the media types PATCH /seeds/{id}/metadata understands.
+var acceptPatch = strings.Join([]string{"application/json", jsonpatch.MergePatchType, jsonpatch.JSONPatchType}, ", ")
+
+// metadataPatcher returns the function that turns the current metadata into the patched one, chosen by the
+// request's Content-Type. application/json (or none) keeps the historical shallow merge; merge-patch+json
+// merges nested objects and removes keys set to null; json-patch+json applies RFC 6902 operations.
+// It writes 400 or 415 and returns false if the body does not fit.
+func metadataPatcher(w http.ResponseWriter, r *http.Request) (func(json.RawMessage) (json.RawMessage, error), bool) {
+	mediaType := "application/json"
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mt, _, err := mime.ParseMediaType(ct)
+		if err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid Content-Type")
+			return nil, false
+		}
+		mediaType = mt
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		apilib.RespondError(w, http.StatusBadRequest, "could not read body")
+		return nil, false
+	}
+
+	switch mediaType {
+	case "application/json":
+		var patch map[string]json.RawMessage
+		if err := json.Unmarshal(body, &patch); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return nil, false
+		}
+		return func(current json.RawMessage) (json.RawMessage, error) {
+			var doc map[string]json.RawMessage
+			if err := json.Unmarshal(current, &doc); err != nil || doc == nil {
+				doc = map[string]json.RawMessage{}
+			}
+			for k, v := range patch {
+				doc[k] = v
+			}
+			return json.Marshal(doc)
+		}, true
+	case jsonpatch.MergePatchType:
+		if !json.Valid(body) {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return nil, false
+		}
+		return func(current json.RawMessage) (json.RawMessage, error) {
+			return jsonpatch.MergePatch(current, body)
+		}, true
+	case jsonpatch.JSONPatchType:
+		ops, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, err.Error())
+			return nil, false
+		}
+		return func(current json.RawMessage) (json.RawMessage, error) {
+			return jsonpatch.Apply(current, ops)
+		}, true
+	}
+	w.Header().Set("Accept-Patch", acceptPatch)
+	apilib.RespondError(w, http.StatusUnsupportedMediaType, "unsupported Content-Type; use one of "+acceptPatch)
+	return nil, false
+}
+
+// respondPatchError maps errors of metadata patches: a failed test operation is 409, a patch that does not
+// fit the document or leaves no JSON object behind is 422; everything else goes through respondWriteError.
+func respondPatchError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, jsonpatch.ErrTestFailed):
+		apilib.RespondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, jsonpatch.ErrCannotApply), errors.Is(err, jsonpatch.ErrInvalidPatch), errors.Is(err, store.ErrMetadataNotObject):
+		apilib.RespondError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		respondWriteError(w, r, err)
+	}
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+const (
+	defaultMetricWindow  = 7 * 24 * time.Hour
+	maxMetricBuckets     = 1000
+	defaultMetricBuckets = 200
+	maxMetricPoints      = 1000 // per POST
+)
+
+// HandleInsertMetricPoints handles POST /metrics/points: record one point or a batch for the tenant in the query.
+func HandleInsertMetricPoints(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.MetricPointsRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		inputs := req.Points
+		if len(inputs) == 0 {
+			inputs = []apilib.MetricPointInput{req.MetricPointInput}
+		}
+		if len(inputs) > maxMetricPoints {
+			apilib.RespondError(w, http.StatusBadRequest, "at most "+strconv.Itoa(maxMetricPoints)+" points per request")
+			return
+		}
+		appID := r.URL.Query().Get("appId")
+		externalUserID := r.URL.Query().Get("externalUserId")
+		points := make([]store.MetricPoint, 0, len(inputs))
+		for i, in := range inputs {
+			name := strings.TrimSpace(in.Name)
+			if name == "" || in.Value == nil {
+				apilib.RespondError(w, http.StatusBadRequest, "points["+strconv.Itoa(i)+"]: name and value required")
+				return
+			}
+			p := store.MetricPoint{Name: name, Value: *in.Value, Labels: in.Labels, AppID: appID, ExternalUserID: externalUserID}
+			if in.Timestamp != "" {
+				t, err := time.Parse(time.RFC3339, in.Timestamp)
+				if err != nil {
+					apilib.RespondError(w, http.StatusBadRequest, "points["+strconv.Itoa(i)+"]: timestamp must be RFC 3339")
+					return
+				}
+				p.RecordedAt = t
+			}
+			points = append(points, p)
+		}
+		if err := s.InsertMetricPoints(r.Context(), points); err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		apilib.RespondJSON(w, http.StatusCreated, map[string]int{"inserted": len(points)})
+	}
+}
+
+// HandleMetricSeries handles GET /metrics/series?name=&from=&to=&step=&agg=: the metric downsampled into buckets.
+// step is a Go duration (e.g. 5m, 1h); when omitted the range is split into about 200 buckets.
+func HandleMetricSeries(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		q := r.URL.Query()
+		name := strings.TrimSpace(q.Get("name"))
+		if name == "" {
+			apilib.RespondError(w, http.StatusBadRequest, "name required")
+			return
+		}
+		from, to, ok := parseTimeRange(w, r, defaultMetricWindow)
+		if !ok {
+			return
+		}
+		span := to.Sub(from)
+		step := span / defaultMetricBuckets
+		if v := q.Get("step"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				apilib.RespondError(w, http.StatusBadRequest, "step must be a positive duration (e.g. 5m, 1h)")
+				return
+			}
+			step = d
+		}
+		if step < time.Second {
+			step = time.Second
+		}
+		if span/step > maxMetricBuckets {
+			apilib.RespondError(w, http.StatusBadRequest, "step too small: at most "+strconv.Itoa(maxMetricBuckets)+" buckets")
+			return
+		}
+		agg := q.Get("agg")
+		if agg == "" {
+			agg = "avg"
+		}
+		if !store.ValidMetricAggregate(agg) {
+			apilib.RespondError(w, http.StatusBadRequest, "agg must be avg, min, max, sum, count or last")
+			return
+		}
+
+		series, err := s.MetricSeries(r.Context(), name, from, to, step, agg, q.Get("appId"), q.Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if series == nil {
+			series = []store.SeriesPoint{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{
+			"name":   name,
+			"from":   from.Format(time.RFC3339),
+			"to":     to.Format(time.RFC3339),
+			"step":   step.String(),
+			"agg":    agg,
+			"points": series,
+		})
+	}
+}
+
+// HandleMetricNames handles GET /metrics: the metric names recorded for a tenant.
+func HandleMetricNames(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		names, err := s.MetricNames(r.Context(), r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if names == nil {
+			names = []string{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, names)
+	}
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/model"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// neutronTextTypes are the textTypes accepted by Neutron's multipart seed upload.
+var neutronTextTypes = map[string]bool{
+	"text": true, "markdown": true, "json": true, "csv": true, "claude_chat": true, "gpt_chat": true, "email": true,
+}
+
+// neutronSeed is one item of a Neutron-style multipart upload.
+type neutronSeed struct {
+	Text     string
+	TextType string
+	Source   string
+	Title    string
+}
+
+// metadata returns the seed metadata. "tag" keeps the title under the key earlier versions stored it as.
+func (n neutronSeed) metadata() json.RawMessage {
+	b, _ := json.Marshal(map[string]string{"source": n.Source, "title": n.Title, "tag": n.Title, "textType": n.TextType})
+	return b
+}
+
+// storeNeutronSeeds handles the multipart variant of POST /seeds: one seed per text item. The response keeps
+// "id" (the first seed) for single-item clients and lists every seed in "ids", in upload order.
+func storeNeutronSeeds(w http.ResponseWriter, r *http.Request, s *store.Store) {
+	items, err := parseNeutronSeeds(r.MultipartForm)
+	if err != nil {
+		apilib.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	texts := make([]string, len(items))
+	for i, it := range items {
+		texts[i] = it.Text
+	}
+	var embeddings [][]float32
+	if len(texts) == 1 {
+		emb, err := model.Embed(texts[0])
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		embeddings = [][]float32{emb}
+	} else if embeddings, err = model.EmbedBatch(texts); err != nil {
+		apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	appID := r.URL.Query().Get("appId")
+	externalUserID := r.URL.Query().Get("externalUserId")
+	seeds := make([]store.NewSeed, len(items))
+	for i, it := range items {
+		seeds[i] = store.NewSeed{Content: it.Text, Metadata: it.metadata(), Embedding: embeddings[i], AppID: appID, ExternalUserID: externalUserID}
+	}
+	// One transaction, like POST /seeds/batch: a failing item leaves no partial upload behind.
+	ids, err := s.InsertSeeds(r.Context(), seeds)
+	if err != nil {
+		apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{"id": ids[0], "ids": ids})
+}
+
+// parseNeutronSeeds reads the parallel arrays text, textTypes, textSources and textTitles of a Neutron
+// multipart upload. Each field may be a JSON array or a plain string and may be repeated. The attribute
+// arrays may be shorter than text: a single value applies to every item, missing values take the defaults
+// (text, bot_save, Untitled).
+func parseNeutronSeeds(form *multipart.Form) ([]neutronSeed, error) {
+	texts := parseMultipartList(form, "text")
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("content required")
+	}
+	types := parseMultipartList(form, "textTypes")
+	sources := parseMultipartList(form, "textSources")
+	titles := parseMultipartList(form, "textTitles")
+	for name, vals := range map[string][]string{"textTypes": types, "textSources": sources, "textTitles": titles} {
+		if len(vals) > len(texts) {
+			return nil, fmt.Errorf("%s has %d entries for %d texts", name, len(vals), len(texts))
+		}
+	}
+	seeds := make([]neutronSeed, len(texts))
+	for i, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("text[%d] is empty", i)
+		}
+		n := neutronSeed{
+			Text:     text,
+			TextType: strings.ToLower(itemAt(types, i, "text")),
+			Source:   itemAt(sources, i, "bot_save"),
+			Title:    itemAt(titles, i, "Untitled"),
+		}
+		if !neutronTextTypes[n.TextType] {
+			return nil, fmt.Errorf("textTypes[%d]: unknown type %q (text, markdown, json, csv, claude_chat, gpt_chat, email)", i, n.TextType)
+		}
+		if n.TextType == "json" && !json.Valid([]byte(text)) {
+			return nil, fmt.Errorf("text[%d] is not valid JSON", i)
+		}
+		seeds[i] = n
+	}
+	return seeds, nil
+}
+
+// parseMultipartList returns all values of a form field, expanding JSON string arrays.
+func parseMultipartList(form *multipart.Form, key string) []string {
+	if form == nil || form.Value == nil {
+		return nil
+	}
+	var out []string
+	for _, v := range form.Value[key] {
+		s := strings.TrimSpace(v)
+		if s == "" {
+			continue
+		}
+		if strings.HasPrefix(s, "[") {
+			if arr, ok := decodeStringArray(s); ok {
+				out = append(out, arr...)
+				continue
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// rawControlChars escapes the control characters shell scripts put into JSON strings unescaped,
+// e.g. the multi-line conversation the auto-capture hook sends as text=["User: …\nAssistant: …"].
+var rawControlChars = strings.NewReplacer("\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// decodeStringArray parses a JSON array of strings as sent by hand-written shell scripts: it retries with raw
+// control characters escaped and finally falls back to the text between the outer brackets.
+func decodeStringArray(s string) ([]string, bool) {
+	var arr []string
+	if err := json.Unmarshal([]byte(s), &arr); err == nil {
+		return arr, true
+	}
+	if err := json.Unmarshal([]byte(rawControlChars.Replace(s)), &arr); err == nil {
+		return arr, true
+	}
+	// Unescaped quotes inside the text: treat it as a single item.
+	if len(s) >= 4 && strings.HasPrefix(s, `["`) && strings.HasSuffix(s, `"]`) {
+		return []string{s[2 : len(s)-2]}, true
+	}
+	return nil, false
+}
+
+// itemAt returns vals[i], the only value when there is exactly one, or def.
+func itemAt(vals []string, i int, def string) string {
+	switch {
+	case i < len(vals) && strings.TrimSpace(vals[i]) != "":
+		return vals[i]
+	case len(vals) == 1 && strings.TrimSpace(vals[0]) != "":
+		return vals[0]
+	}
+	return def
+}
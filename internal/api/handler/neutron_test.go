@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cabroe/neural-brain/internal/store"
+	"github.com/cabroe/neural-brain/internal/storetest"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// The requests below are the ones skills/vanar-neutron-memory sends, field for field: curl -F for saves
+// (the auto-capture hook puts the conversation into the JSON array with raw newlines), JSON for search and
+// agent contexts. Responses are compared against testdata/neutron/<name>.golden.
+const (
+	neutronTenant  = "?appId=neural-brain&externalUserId=skill-test"
+	saveText       = "Cabroe prefers PostgreSQL with pgvector for all memory storage"
+	autoCaptureRaw = "User: which port does the API use?\nAssistant: Port 8080 unless PORT is set."
+)
+
+// neutronRequest is one replayed request. Form fields are sent as multipart/form-data in order, Body as JSON.
+// "{contextId}" in Path is replaced by the id returned by the previous agent context create.
+type neutronRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Form   []formField     `json:"form,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type formField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type neutronResponse struct {
+	Status int         `json:"status"`
+	Body   interface{} `json:"body"`
+}
+
+type neutronCase struct {
+	name string
+	req  neutronRequest
+}
+
+func saveForm(text, source, title, textType string) []formField {
+	return []formField{
+		{"text", `["` + text + `"]`},
+		{"textTypes", `["` + textType + `"]`},
+		{"textSources", `["` + source + `"]`},
+		{"textTitles", `["` + title + `"]`},
+	}
+}
+
+// TestNeutronParse pins how the skill's multipart uploads are read, including the unescaped newline the
+// auto-capture hook sends. It needs neither a database nor the model.
+func TestNeutronParse(t *testing.T) {
+	cases := []neutronCase{
+		{"parse_save", neutronRequest{Method: "POST", Path: "/seeds" + neutronTenant, Form: saveForm(saveText, "bot_save", "Database preference", "text")}},
+		{"parse_auto_capture", neutronRequest{Method: "POST", Path: "/seeds" + neutronTenant, Form: saveForm(autoCaptureRaw, "auto_capture", "Auto-captured conversation", "text")}},
+		{"parse_defaults", neutronRequest{Method: "POST", Path: "/seeds" + neutronTenant, Form: []formField{{"text", `["first","second"]`}, {"textTitles", "Shared title"}}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := c.req.build(t, "")
+			if err := r.ParseMultipartForm(10 << 20); err != nil {
+				t.Fatalf("parse multipart: %v", err)
+			}
+			items, err := parseNeutronSeeds(r.MultipartForm)
+			if err != nil {
+				t.Fatalf("parseNeutronSeeds: %v", err)
+			}
+			parsed := make([]map[string]interface{}, len(items))
+			for i, it := range items {
+				var meta interface{}
+				if err := json.Unmarshal(it.metadata(), &meta); err != nil {
+					t.Fatal(err)
+				}
+				parsed[i] = map[string]interface{}{"content": it.Text, "metadata": meta}
+			}
+			checkGolden(t, c.name, map[string]interface{}{"request": c.req, "seeds": parsed})
+		})
+	}
+}
+
+// TestNeutronErrors replays malformed skill requests. They are rejected before the store or the model is
+// used, so an offline store is enough.
+func TestNeutronErrors(t *testing.T) {
+	mux := neutronMux(storetest.Offline(t))
+	cases := []neutronCase{
+		{"save_empty_text", neutronRequest{Method: "POST", Path: "/seeds" + neutronTenant, Form: saveForm("", "bot_save", "Empty", "text")}},
+		{"save_unknown_text_type", neutronRequest{Method: "POST", Path: "/seeds" + neutronTenant, Form: saveForm(saveText, "bot_save", "PDF", "pdf")}},
+		{"save_too_many_titles", neutronRequest{Method: "POST", Path: "/seeds" + neutronTenant, Form: []formField{{"text", `["one"]`}, {"textTitles", `["a","b"]`}}}},
+		{"search_empty_query", neutronRequest{Method: "POST", Path: "/seeds/query" + neutronTenant, Body: json.RawMessage(`{"query":"","limit":5,"threshold":0.5}`)}},
+		{"context_missing_agent", neutronRequest{Method: "POST", Path: "/agent-contexts" + neutronTenant, Body: json.RawMessage(`{"agentId":"","memoryType":"episodic","data":{},"metadata":{}}`)}},
+		{"context_unknown_memory_type", neutronRequest{Method: "POST", Path: "/agent-contexts" + neutronTenant, Body: json.RawMessage(`{"agentId":"claude","memoryType":"longterm","data":{},"metadata":{}}`)}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			checkGolden(t, c.name, map[string]interface{}{"request": c.req, "response": replay(t, mux, c.req, "")})
+		})
+	}
+}
+
+// TestNeutronRoundTrip replays a skill session (save, auto-capture, search, agent contexts) against a real
+// database and model. It is skipped unless TEST_DATABASE_URL and GTE_MODEL_PATH are set.
+func TestNeutronRoundTrip(t *testing.T) {
+	storetest.LoadModel(t)
+	mux := neutronMux(storetest.Open(t))
+	cases := []neutronCase{
+		{"save", neutronRequest{Method: "POST", Path: "/seeds" + neutronTenant, Form: saveForm(saveText, "bot_save", "Database preference", "text")}},
+		{"auto_capture", neutronRequest{Method: "POST", Path: "/seeds" + neutronTenant, Form: saveForm(autoCaptureRaw, "auto_capture", "Auto-captured conversation", "text")}},
+		{"search", neutronRequest{Method: "POST", Path: "/seeds/query" + neutronTenant, Body: json.RawMessage(`{"query":"` + saveText + `","limit":1,"threshold":0.5}`)}},
+		{"search_other_tenant", neutronRequest{Method: "POST", Path: "/seeds/query?appId=neural-brain&externalUserId=someone-else", Body: json.RawMessage(`{"query":"` + saveText + `","limit":5,"threshold":0.5}`)}},
+		{"context_create", neutronRequest{Method: "POST", Path: "/agent-contexts" + neutronTenant, Body: json.RawMessage(`{"agentId":"claude","memoryType":"episodic","data":{"summary":"Set up the memory skill"},"metadata":{"source":"skill"}}`)}},
+		{"context_list", neutronRequest{Method: "GET", Path: "/agent-contexts" + neutronTenant + "&agentId=claude"}},
+		{"context_get", neutronRequest{Method: "GET", Path: "/agent-contexts/{contextId}" + neutronTenant}},
+	}
+	var contextID string
+	for _, c := range cases {
+		resp := replay(t, mux, c.req, contextID)
+		if c.name == "context_create" {
+			if body, ok := resp.rawBody.(map[string]interface{}); ok {
+				contextID, _ = body["id"].(string)
+			}
+		}
+		checkGolden(t, c.name, map[string]interface{}{"request": c.req, "response": resp.neutronResponse})
+	}
+}
+
+// neutronMux mounts the handlers the skill talks to under the patterns main.go uses.
+func neutronMux(s *store.Store) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /seeds", HandleStoreSeed(s))
+	mux.HandleFunc("POST /seeds/query", HandleSeedsQuery(s))
+	mux.HandleFunc("POST /agent-contexts", HandleCreateContext(s))
+	mux.HandleFunc("GET /agent-contexts", HandleListContexts(s))
+	mux.HandleFunc("GET /agent-contexts/{id}", HandleGetContext(s))
+	return mux
+}
+
+func (nr neutronRequest) build(t *testing.T, contextID string) *http.Request {
+	t.Helper()
+	path := strings.ReplaceAll(nr.Path, "{contextId}", contextID)
+	var body bytes.Buffer
+	contentType := ""
+	switch {
+	case nr.Form != nil:
+		mw := multipart.NewWriter(&body)
+		for _, f := range nr.Form {
+			if err := mw.WriteField(f.Name, f.Value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := mw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		contentType = mw.FormDataContentType()
+	case nr.Body != nil:
+		body.Write(nr.Body)
+		contentType = "application/json"
+	}
+	r := httptest.NewRequest(nr.Method, path, &body)
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+type replayed struct {
+	neutronResponse
+	rawBody interface{}
+}
+
+func replay(t *testing.T, mux *http.ServeMux, nr neutronRequest, contextID string) replayed {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, nr.build(t, contextID))
+	var body interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("%s %s: response is not JSON: %v\n%s", nr.Method, nr.Path, err, rec.Body.String())
+	}
+	return replayed{neutronResponse{Status: rec.Code, Body: normalize("", body)}, body}
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// normalize replaces values that differ between runs: timestamps, generated UUIDs and model scores.
+func normalize(key string, v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, x := range t {
+			t[k] = normalize(k, x)
+		}
+		return t
+	case []interface{}:
+		for i, x := range t {
+			t[i] = normalize(key, x)
+		}
+		return t
+	case string:
+		switch {
+		case uuidPattern.MatchString(t):
+			return "<uuid>"
+		case key == "createdAt" || key == "updatedAt" || key == "lastAccessedAt":
+			return "<time>"
+		}
+	case float64:
+		if key == "similarity" || key == "score" {
+			return "<score>"
+		}
+	}
+	return v
+}
+
+func checkGolden(t *testing.T, name string, got interface{}) {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(got); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	path := filepath.Join("testdata", "neutron", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(b, want) {
+		t.Errorf("%s differs from %s:\n%s", name, path, diffLines(string(want), string(b)))
+	}
+}
+
+// diffLines lists the lines that differ between want and got, enough to see what changed.
+func diffLines(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	var out strings.Builder
+	for i := 0; i < len(w) || i < len(g); i++ {
+		var wl, gl string
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if wl != gl {
+			fmt.Fprintf(&out, "line %d:\n  want %s\n  got  %s\n", i+1, wl, gl)
+		}
+	}
+	return out.String()
+}
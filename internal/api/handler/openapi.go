@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/api/openapi"
+)
+
+// HandleOpenAPI handles GET /openapi.json: the OpenAPI 3 document of this API.
+func HandleOpenAPI() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openapi.Spec)
+	}
+}
+
+// HandleDocs handles GET /docs: an interactive page rendering /openapi.json.
+func HandleDocs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(openapi.DocsPage)
+	}
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+const (
+	recallDefaultLimit     = 5
+	recallDefaultThreshold = 0.5
+	recallDefaultMaxChars  = 2000
+	recallCharsPerToken    = 4
+	// recallDupSimilarity drops a candidate whose embedding is this close to one already in the block.
+	recallDupSimilarity = 0.95
+)
+
+var recallFormats = map[string]bool{"plain": true, "markdown": true, "xml": true}
+
+// HandleRecall handles POST /recall: search, dedupe and format memories into a prompt-ready block.
+func HandleRecall(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.RecallRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		req.Message = strings.TrimSpace(req.Message)
+		if req.Message == "" {
+			apilib.RespondError(w, http.StatusBadRequest, "message required")
+			return
+		}
+		req.Format = strings.ToLower(strings.TrimSpace(req.Format))
+		if req.Format == "" {
+			req.Format = "plain"
+		}
+		if !recallFormats[req.Format] {
+			apilib.RespondError(w, http.StatusBadRequest, "format must be one of: plain, markdown, xml")
+			return
+		}
+		if req.AppID == "" {
+			req.AppID = r.URL.Query().Get("appId")
+		}
+		if req.ExternalUserID == "" {
+			req.ExternalUserID = r.URL.Query().Get("externalUserId")
+		}
+
+		opts, budget, err := recallOptions(s, &req)
+		if err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		seeds, err := runSearch(s, r, req.Message, opts)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		block, ids, truncated := buildRecallBlock(dedupeSeeds(seeds), req.Format, budget)
+		// Only what made it into the block counts as accessed, not what dedupe or the budget dropped.
+		recordAccess(s, r, ids)
+		apilib.RespondJSON(w, http.StatusOK, apilib.RecallResponse{Block: block, IDs: ids, Truncated: truncated})
+	}
+}
+
+// recallOptions maps a RecallRequest onto search options (threshold, MMR and hybrid rank on by default) and a character budget.
+func recallOptions(s *store.Store, req *apilib.RecallRequest) (searchOptions, int, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = recallDefaultLimit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	threshold := recallDefaultThreshold
+	if req.Threshold != nil {
+		threshold = *req.Threshold
+	}
+	if threshold > 1 {
+		return searchOptions{}, 0, errors.New("threshold must be at most 1")
+	}
+	rankOpts := req.Rank
+	if rankOpts == nil {
+		rankOpts = &apilib.RankOptions{Mode: store.RankHybrid}
+	}
+	rank, err := rankParams(s, rankOpts)
+	if err != nil {
+		return searchOptions{}, 0, err
+	}
+	lambda, err := mmrLambda(req.MMRLambda)
+	if err != nil {
+		return searchOptions{}, 0, err
+	}
+
+	budget := recallDefaultMaxChars
+	if req.MaxChars > 0 {
+		budget = req.MaxChars
+	} else if req.MaxTokens > 0 {
+		budget = req.MaxTokens * recallCharsPerToken
+	}
+	return searchOptions{
+		limit:          limit,
+		threshold:      threshold,
+		appID:          req.AppID,
+		externalUserID: req.ExternalUserID,
+		rank:           rank,
+		mmr:            true,
+		mmrLambda:      lambda,
+		contextWindow:  -1,
+	}, budget, nil
+}
+
+// dedupeSeeds drops seeds whose normalized content repeats, or whose embedding nearly matches an earlier seed.
+func dedupeSeeds(seeds []store.Seed) []store.Seed {
+	seen := make(map[string]bool, len(seeds))
+	out := make([]store.Seed, 0, len(seeds))
+outer:
+	for _, se := range seeds {
+		key := strings.ToLower(strings.Join(strings.Fields(se.Content), " "))
+		if key == "" || seen[key] {
+			continue
+		}
+		for _, kept := range out {
+			if len(se.Embedding) > 0 && store.Cosine(se.Embedding, kept.Embedding) >= recallDupSimilarity {
+				continue outer
+			}
+		}
+		seen[key] = true
+		out = append(out, se)
+	}
+	return out
+}
+
+// buildRecallBlock renders seeds in format until budget characters are used.
+// A single item that does not fit is cut with an ellipsis so the block is never empty when there are hits.
+func buildRecallBlock(seeds []store.Seed, format string, budget int) (string, []int64, bool) {
+	ids := []int64{}
+	if len(seeds) == 0 {
+		return "", ids, false
+	}
+	var header, footer string
+	switch format {
+	case "markdown":
+		header, footer = "## Recalled memories\n\n", ""
+	case "xml":
+		header, footer = "<memories>\n", "</memories>\n"
+	default:
+		header, footer = "RECALLED MEMORIES:\n", ""
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	used := len(header) + len(footer)
+	truncated := false
+	for i, se := range seeds {
+		item := formatRecallItem(se, se.Content, format)
+		if used+len(item) > budget {
+			truncated = true
+			if i > 0 {
+				break
+			}
+			// Cut the first item's content to whatever still fits.
+			room := budget - used - len(formatRecallItem(se, "…", format))
+			if room <= 0 {
+				break
+			}
+			item = formatRecallItem(se, truncateRunes(se.Content, room)+"…", format)
+		}
+		b.WriteString(item)
+		used += len(item)
+		ids = append(ids, se.ID)
+	}
+	if len(ids) == 0 {
+		return "", ids, truncated
+	}
+	b.WriteString(footer)
+	return b.String(), ids, truncated
+}
+
+func formatRecallItem(se store.Seed, content, format string) string {
+	switch format {
+	case "markdown":
+		return fmt.Sprintf("- %s\n", strings.Join(strings.Fields(content), " "))
+	case "xml":
+		return fmt.Sprintf("<memory id=\"%d\" score=\"%.2f\">%s</memory>\n", se.ID, se.Score, html.EscapeString(content))
+	default:
+		return fmt.Sprintf("- %s\n", content)
+	}
+}
+
+// truncateRunes cuts s to at most n bytes without splitting a UTF-8 sequence.
+func truncateRunes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && n < len(s) && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/model"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// defaultDigestExclude keeps system-generated seeds out of reflection to avoid feedback loops.
+var defaultDigestExclude = []string{"metrik", "learning", "belief"}
+
+const (
+	defaultBeliefImportance = 8
+	defaultBeliefConfidence = 0.8
+	defaultDigestLimit      = 50
+	maxDigestLimit          = 500
+)
+
+// HandleReflectionDigest handles GET /reflection/digest?hours=24&limit=50&excludeTypes=metrik,learning:
+// recent seeds in the window, type-filtered and de-duplicated, plus a prompt-ready text rendering.
+func HandleReflectionDigest(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		hours := 24.0
+		if v := r.URL.Query().Get("hours"); v != "" {
+			h, err := strconv.ParseFloat(v, 64)
+			if err != nil || h <= 0 {
+				apilib.RespondError(w, http.StatusBadRequest, "invalid hours")
+				return
+			}
+			hours = h
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit <= 0 {
+			limit = defaultDigestLimit
+		}
+		if limit > maxDigestLimit {
+			limit = maxDigestLimit
+		}
+		exclude := defaultDigestExclude
+		if v, ok := r.URL.Query()["excludeTypes"]; ok {
+			exclude = []string{}
+			for _, t := range strings.Split(strings.Join(v, ","), ",") {
+				if t = strings.TrimSpace(t); t != "" {
+					exclude = append(exclude, t)
+				}
+			}
+		}
+
+		since := time.Now().Add(-time.Duration(hours * float64(time.Hour)))
+		seeds, err := s.RecentDigest(r.Context(), since, exclude, limit, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		seeds = dedupeSeeds(seeds)
+
+		var text strings.Builder
+		for _, se := range seeds {
+			var meta struct {
+				Tags []string `json:"tags"`
+			}
+			_ = json.Unmarshal(se.Metadata, &meta)
+			tags, _ := json.Marshal(meta.Tags)
+			if meta.Tags == nil {
+				tags = []byte("[]")
+			}
+			fmt.Fprintf(&text, "[%s] (ID: %d): %s | Tags: %s\n", se.CreatedAt, se.ID, se.Content, tags)
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{
+			"since": since.UTC().Format(time.RFC3339),
+			"count": len(seeds),
+			"seeds": seeds,
+			"text":  text.String(),
+		})
+	}
+}
+
+// HandleCommitBelief handles POST /beliefs: store an insight with provenance, revising similar beliefs.
+func HandleCommitBelief(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.CommitBeliefRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		req.Content = strings.TrimSpace(req.Content)
+		if req.Content == "" {
+			apilib.RespondError(w, http.StatusBadRequest, "content required")
+			return
+		}
+		b := store.Belief{
+			Content:        req.Content,
+			Importance:     defaultBeliefImportance,
+			Confidence:     defaultBeliefConfidence,
+			SourceSeedIDs:  req.SourceSeedIDs,
+			AppID:          r.URL.Query().Get("appId"),
+			ExternalUserID: r.URL.Query().Get("externalUserId"),
+		}
+		if req.Importance != nil {
+			if *req.Importance < 1 || *req.Importance > 10 {
+				apilib.RespondError(w, http.StatusBadRequest, "importance must be between 1 and 10")
+				return
+			}
+			b.Importance = *req.Importance
+		}
+		if req.Confidence != nil {
+			if *req.Confidence < 0 || *req.Confidence > 1 {
+				apilib.RespondError(w, http.StatusBadRequest, "confidence must be between 0 and 1")
+				return
+			}
+			b.Confidence = *req.Confidence
+		}
+
+		emb, err := model.Embed(b.Content)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rev, err := s.CommitBelief(r.Context(), b, emb)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		status := http.StatusCreated
+		if rev.Action == store.BeliefReinforced {
+			status = http.StatusOK
+		}
+		apilib.RespondJSON(w, status, rev)
+	}
+}
+
+// HandleListBeliefs handles GET /beliefs?status=active|superseded.
+func HandleListBeliefs(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		status := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status")))
+		if status != "" && status != "active" && status != "superseded" {
+			apilib.RespondError(w, http.StatusBadRequest, "status must be one of: active, superseded")
+			return
+		}
+		list, err := s.ListBeliefs(r.Context(), status, r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if list == nil {
+			list = []store.Belief{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, list)
+	}
+}
@@ -2,12 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
-	"mime/multipart"
+	"time"
 
-	"github.com/jackc/pgx/v5"
 	apilib "github.com/cabroe/neural-brain/internal/api"
 	"github.com/cabroe/neural-brain/internal/store"
 	"github.com/cabroe/neural-brain/internal/model"
@@ -26,30 +28,21 @@ func HandleStoreSeed(s *store.Store) http.HandlerFunc {
 
 		ct := r.Header.Get("Content-Type")
 		if strings.HasPrefix(ct, "multipart/form-data") {
-			if err := r.ParseMultipartForm(10 << 20); err != nil {
+			if err := r.ParseMultipartForm(MaxSeedBodyBytes); err != nil {
 				apilib.RespondError(w, http.StatusBadRequest, "invalid multipart form")
 				return
 			}
-			content = parseMultipartText(r.MultipartForm, "text")
-			source := parseMultipartText(r.MultipartForm, "textSources")
-			if source == "" {
-				source = "bot_save"
-			}
-			title := parseMultipartText(r.MultipartForm, "textTitles")
-			if title == "" {
-				title = "Untitled"
-			}
-			metadata, _ = json.Marshal(map[string]string{"source": source, "tag": title})
-		} else {
-			var req apilib.StoreSeedRequest
-			if err := apilib.DecodeJSON(r, &req); err != nil {
-				apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
-				return
-			}
-			content = req.Content
-			if req.Metadata != nil {
-				metadata = req.Metadata
-			}
+			storeNeutronSeeds(w, r, s)
+			return
+		}
+		var req apilib.StoreSeedRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		content = req.Content
+		if req.Metadata != nil {
+			metadata = req.Metadata
 		}
 
 		if content == "" {
@@ -86,6 +79,65 @@ func HandleStoreSeed(s *store.Store) http.HandlerFunc {
 	}
 }
 
+// maxSeedBatch is the most seeds POST /seeds/batch stores per request.
+const maxSeedBatch = 100
+
+// HandleStoreSeedsBatch handles POST /seeds/batch: embed and store several seeds in one request. All texts are
+// embedded before the first write; the IDs come back in request order (the existing ID for a deduplicated seed).
+func HandleStoreSeedsBatch(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.StoreSeedsBatchRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		if len(req.Seeds) == 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "seeds required")
+			return
+		}
+		if len(req.Seeds) > maxSeedBatch {
+			apilib.RespondError(w, http.StatusBadRequest, "at most "+strconv.Itoa(maxSeedBatch)+" seeds per request")
+			return
+		}
+		texts := make([]string, len(req.Seeds))
+		for i, seed := range req.Seeds {
+			if strings.TrimSpace(seed.Content) == "" {
+				apilib.RespondError(w, http.StatusBadRequest, "seeds["+strconv.Itoa(i)+"]: content required")
+				return
+			}
+			texts[i] = seed.Content
+		}
+
+		embeddings, err := model.EmbedBatch(texts)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		appID := r.URL.Query().Get("appId")
+		externalUserID := r.URL.Query().Get("externalUserId")
+		seeds := make([]store.NewSeed, len(req.Seeds))
+		for i, seed := range req.Seeds {
+			metadata := seed.Metadata
+			if metadata == nil {
+				metadata = []byte("{}")
+			}
+			seeds[i] = store.NewSeed{Content: seed.Content, Metadata: metadata, Embedding: embeddings[i], AppID: appID, ExternalUserID: externalUserID}
+		}
+		// One transaction: a failure stores nothing, so retrying the whole batch cannot duplicate seeds.
+		ids, err := s.InsertSeeds(r.Context(), seeds)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{"ids": ids})
+	}
+}
+
 // HandleSearch handles GET /search?q=...&limit=...&threshold=...&seedIds=1,2,3
 func HandleSearch(s *store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -110,10 +162,59 @@ func HandleSearch(s *store.Store) http.HandlerFunc {
 			}
 		}
 
-		appID := r.URL.Query().Get("appId")
-		externalUserID := r.URL.Query().Get("externalUserId")
-
-		seeds, err := runSearch(s, r, q, limit, threshold, seedIDs, appID, externalUserID)
+		rank, err := parseRankQuery(s, r.URL.Query())
+		if err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		mmr, err := parseBoolParam(r.URL.Query(), "mmr")
+		if err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		var lambdaPtr *float64
+		if l := r.URL.Query().Get("lambda"); l != "" {
+			v, err := strconv.ParseFloat(l, 64)
+			if err != nil {
+				apilib.RespondError(w, http.StatusBadRequest, "invalid lambda")
+				return
+			}
+			lambdaPtr = &v
+		}
+		lambda, err := mmrLambda(lambdaPtr)
+		if err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		contextWindow := -1
+		if c := r.URL.Query().Get("context"); c != "" {
+			n, err := strconv.Atoi(c)
+			if err != nil || n < 0 {
+				apilib.RespondError(w, http.StatusBadRequest, "invalid context")
+				return
+			}
+			contextWindow = n
+		}
+		expand, err := parseBoolParam(r.URL.Query(), "expand")
+		if err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		entityRef := strings.TrimSpace(r.URL.Query().Get("entity"))
+
+		seeds, err := runSearch(s, r, q, searchOptions{
+			limit:          limit,
+			threshold:      threshold,
+			seedIDs:        seedIDs,
+			appID:          r.URL.Query().Get("appId"),
+			externalUserID: r.URL.Query().Get("externalUserId"),
+			rank:           rank,
+			mmr:            mmr,
+			mmrLambda:      lambda,
+			contextWindow:  contextWindow,
+			expand:         expand,
+			entity:         entityRef,
+		})
 		if err != nil {
 			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
 			return
@@ -121,6 +222,7 @@ func HandleSearch(s *store.Store) http.HandlerFunc {
 		if seeds == nil {
 			seeds = []store.Seed{}
 		}
+		recordHits(s, r, seeds)
 		apilib.RespondJSON(w, http.StatusOK, seeds)
 	}
 }
@@ -177,10 +279,34 @@ func HandleSeedsQuery(s *store.Store) http.HandlerFunc {
 		if threshold < 0 {
 			threshold = -1
 		}
-		appID := r.URL.Query().Get("appId")
-		externalUserID := r.URL.Query().Get("externalUserId")
-
-		seeds, err := runSearch(s, r, req.Query, limit, threshold, req.SeedIDs, appID, externalUserID)
+		rank, err := rankParams(s, req.Rank)
+		if err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		lambda, err := mmrLambda(req.MMRLambda)
+		if err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		contextWindow := -1
+		if req.ContextWindow != nil {
+			contextWindow = *req.ContextWindow
+		}
+
+		seeds, err := runSearch(s, r, req.Query, searchOptions{
+			limit:          limit,
+			threshold:      threshold,
+			seedIDs:        req.SeedIDs,
+			appID:          r.URL.Query().Get("appId"),
+			externalUserID: r.URL.Query().Get("externalUserId"),
+			rank:           rank,
+			mmr:            req.MMR,
+			mmrLambda:      lambda,
+			contextWindow:  contextWindow,
+			expand:         req.Expand,
+			entity:         strings.TrimSpace(req.Entity),
+		})
 		if err != nil {
 			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
 			return
@@ -188,19 +314,24 @@ func HandleSeedsQuery(s *store.Store) http.HandlerFunc {
 		results := make([]apilib.SeedQueryResult, 0, len(seeds))
 		for _, se := range seeds {
 			results = append(results, apilib.SeedQueryResult{
-				SeedID:     strconv.FormatInt(se.ID, 10),
-				Content:    se.Content,
-				Similarity: se.Score,
+				SeedID:         strconv.FormatInt(se.ID, 10),
+				Content:        se.Content,
+				Similarity:     se.Score,
+				ScoreBreakdown: (*apilib.ScoreBreakdown)(se.ScoreBreakdown),
+				Document:       (*apilib.DocumentContext)(se.Document),
+				Via:            (*apilib.EdgeRef)(se.Via),
 			})
 		}
 		if results == nil {
 			results = []apilib.SeedQueryResult{}
 		}
+		recordHits(s, r, seeds)
 		apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{"results": results})
 	}
 }
 
-// HandleUpdateSeedMetadata handles PATCH /seeds/{id}/metadata.
+// HandleUpdateSeedMetadata handles PATCH /seeds/{id}/metadata with a shallow merge (application/json),
+// JSON Merge Patch or JSON Patch body. If-Match makes the update conditional (412 on conflict).
 func HandleUpdateSeedMetadata(s *store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPatch {
@@ -214,23 +345,28 @@ func HandleUpdateSeedMetadata(s *store.Store) http.HandlerFunc {
 			return
 		}
 
-		var patch json.RawMessage
-		if err := apilib.DecodeJSON(r, &patch); err != nil {
-			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+		ifVersions, ok := ifMatchVersions(w, r)
+		if !ok {
+			return
+		}
+
+		apply, ok := metadataPatcher(w, r)
+		if !ok {
 			return
 		}
 
-		err = s.UpdateSeedMetadata(r.Context(), id, patch)
+		version, err := s.PatchSeedMetadata(r.Context(), id, ifVersions, apply)
 		if err != nil {
-			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			respondPatchError(w, r, err)
 			return
 		}
-		
-		apilib.RespondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+
+		w.Header().Set("ETag", versionETag(version))
+		apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "version": version})
 	}
 }
 
-// HandleUpdateSeedTags handles POST /seeds/{id}/tags.
+// HandleUpdateSeedTags handles POST /seeds/{id}/tags. If-Match makes the update conditional (412 on conflict).
 func HandleUpdateSeedTags(s *store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -244,6 +380,11 @@ func HandleUpdateSeedTags(s *store.Store) http.HandlerFunc {
 			return
 		}
 
+		ifVersions, ok := ifMatchVersions(w, r)
+		if !ok {
+			return
+		}
+
 		var tags []string
 		if err := apilib.DecodeJSON(r, &tags); err != nil {
 			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON array of tags")
@@ -253,17 +394,18 @@ func HandleUpdateSeedTags(s *store.Store) http.HandlerFunc {
 		patchObj := map[string][]string{"tags": tags}
 		patchBytes, _ := json.Marshal(patchObj)
 
-		err = s.UpdateSeedMetadata(r.Context(), id, patchBytes)
+		version, err := s.UpdateSeedMetadata(r.Context(), id, patchBytes, ifVersions)
 		if err != nil {
-			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			respondWriteError(w, r, err)
 			return
 		}
 
-		apilib.RespondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		w.Header().Set("ETag", versionETag(version))
+		apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "version": version})
 	}
 }
 
-// HandleGetSeed handles GET /seeds/{id}.
+// HandleGetSeed handles GET /seeds/{id}. The ETag is the seed version; If-None-Match yields 304.
 func HandleGetSeed(s *store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -287,10 +429,40 @@ func HandleGetSeed(s *store.Store) http.HandlerFunc {
 			return
 		}
 
+		etag := versionETag(seed.Version)
+		w.Header().Set("ETag", etag)
+		if inm := r.Header.Get("If-None-Match"); inm != "" && (inm == "*" || strings.Contains(inm, etag)) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
 		apilib.RespondJSON(w, http.StatusOK, seed)
 	}
 }
 
+// HandleDeleteSeed handles DELETE /seeds/{id}. If-Match makes the delete conditional (412 on conflict).
+func HandleDeleteSeed(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		ifVersions, ok := ifMatchVersions(w, r)
+		if !ok {
+			return
+		}
+		if err := s.DeleteSeed(r.Context(), id, ifVersions); err != nil {
+			respondWriteError(w, r, err)
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
 // HandleGetStats handles GET /stats.
 func HandleGetStats(s *store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -318,7 +490,7 @@ func HandleGetStats(s *store.Store) http.HandlerFunc {
 	}
 }
 
-// HandleUpdateSeed handles PUT /seeds/{id} for full overwriting.
+// HandleUpdateSeed handles PUT /seeds/{id} for full overwriting. If-Match makes the update conditional (412 on conflict).
 func HandleUpdateSeed(s *store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPut {
@@ -332,6 +504,11 @@ func HandleUpdateSeed(s *store.Store) http.HandlerFunc {
 			return
 		}
 
+		ifVersions, ok := ifMatchVersions(w, r)
+		if !ok {
+			return
+		}
+
 		var req apilib.StoreSeedRequest
 		if err := apilib.DecodeJSON(r, &req); err != nil {
 			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
@@ -356,43 +533,19 @@ func HandleUpdateSeed(s *store.Store) http.HandlerFunc {
 		appID := r.URL.Query().Get("appId")
 		externalUserID := r.URL.Query().Get("externalUserId")
 
-		err = s.UpdateSeed(r.Context(), id, req.Content, metadata, emb, appID, externalUserID)
+		version, err := s.UpdateSeed(r.Context(), id, req.Content, metadata, emb, appID, externalUserID, ifVersions)
 		if err != nil {
-			if err == pgx.ErrNoRows {
-				apilib.RespondError(w, http.StatusNotFound, "not found")
-			} else {
-				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
-			}
+			respondWriteError(w, r, err)
 			return
 		}
 
-		apilib.RespondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		w.Header().Set("ETag", versionETag(version))
+		apilib.RespondJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "version": version})
 	}
 }
 
 // Helper functions
 
-func parseMultipartText(form *multipart.Form, key string) string {
-	if form == nil || form.Value == nil {
-		return ""
-	}
-	vals := form.Value[key]
-	if len(vals) == 0 {
-		return ""
-	}
-	s := strings.TrimSpace(vals[0])
-	if s == "" {
-		return ""
-	}
-	if (strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]")) || strings.HasPrefix(s, "[\"") {
-		var arr []string
-		if err := json.Unmarshal([]byte(s), &arr); err == nil && len(arr) > 0 {
-			return arr[0]
-		}
-	}
-	return s
-}
-
 func parseSearchParams(limitStr, thresholdStr string) (limit int, threshold float64) {
 	limit = 30
 	if l := limitStr; l != "" {
@@ -409,23 +562,226 @@ func parseSearchParams(limitStr, thresholdStr string) (limit int, threshold floa
 	return limit, threshold
 }
 
-func runSearch(s *store.Store, r *http.Request, q string, limit int, threshold float64, seedIDs []int64, appID, externalUserID string) ([]store.Seed, error) {
+// rankCandidateFactor is how many candidates per requested result are fetched before re-ranking (hybrid rank or MMR).
+const (
+	rankCandidateFactor = 4
+	maxRankCandidates   = 200
+)
+
+// searchOptions bundles the per-request knobs of runSearch.
+type searchOptions struct {
+	limit          int
+	threshold      float64
+	seedIDs        []int64
+	appID          string
+	externalUserID string
+	rank           store.RankParams
+	mmr            bool
+	mmrLambda      float64
+	contextWindow  int  // < 0: no document context; otherwise neighbouring chunks on each side
+	expand         bool   // append seeds one graph hop away from the hits
+	entity         string // restrict to seeds mentioning this entity (ID, name or alias)
+}
+
+// parseBoolParam reads an optional boolean query parameter; absent means false.
+func parseBoolParam(q url.Values, name string) (bool, error) {
+	v := q.Get(name)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.New("invalid " + name)
+	}
+	return b, nil
+}
+
+// mmrLambda validates an optional MMR lambda, defaulting to store.DefaultMMRLambda.
+func mmrLambda(v *float64) (float64, error) {
+	if v == nil {
+		return store.DefaultMMRLambda, nil
+	}
+	if *v < 0 || *v > 1 {
+		return 0, errors.New("lambda must be between 0 and 1")
+	}
+	return *v, nil
+}
+
+// parseRankQuery reads RankOptions from query parameters (rank, halfLifeHours, wSimilarity, wRecency, wImportance, wAccess).
+func parseRankQuery(s *store.Store, q url.Values) (store.RankParams, error) {
+	opts := &apilib.RankOptions{Mode: q.Get("rank")}
+	if v := q.Get("halfLifeHours"); v != "" {
+		h, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return store.RankParams{}, errors.New("invalid halfLifeHours")
+		}
+		opts.HalfLifeHours = h
+	}
+	for name, dst := range map[string]**float64{
+		"wSimilarity": &opts.WeightSimilarity,
+		"wRecency":    &opts.WeightRecency,
+		"wImportance": &opts.WeightImportance,
+		"wAccess":     &opts.WeightAccess,
+	} {
+		if v := q.Get(name); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return store.RankParams{}, errors.New("invalid " + name)
+			}
+			*dst = &f
+		}
+	}
+	return rankParams(s, opts)
+}
+
+// rankParams applies opts on top of the store defaults and validates the result.
+func rankParams(s *store.Store, opts *apilib.RankOptions) (store.RankParams, error) {
+	p := s.RankParams()
+	if opts == nil {
+		return p, nil
+	}
+	switch mode := strings.ToLower(strings.TrimSpace(opts.Mode)); mode {
+	case "":
+	case store.RankSimilarity, store.RankHybrid:
+		p.Mode = mode
+	default:
+		return p, errors.New("rank must be one of: similarity, hybrid")
+	}
+	if opts.HalfLifeHours < 0 {
+		return p, errors.New("halfLifeHours must be positive")
+	}
+	if opts.HalfLifeHours > 0 {
+		p.HalfLife = time.Duration(opts.HalfLifeHours * float64(time.Hour))
+	}
+	for _, w := range []struct {
+		src *float64
+		dst *float64
+	}{
+		{opts.WeightSimilarity, &p.WeightSimilarity},
+		{opts.WeightRecency, &p.WeightRecency},
+		{opts.WeightImportance, &p.WeightImportance},
+		{opts.WeightAccess, &p.WeightAccess},
+	} {
+		if w.src == nil {
+			continue
+		}
+		if *w.src < 0 {
+			return p, errors.New("rank weights must not be negative")
+		}
+		*w.dst = *w.src
+	}
+	return p, nil
+}
+
+func runSearch(s *store.Store, r *http.Request, q string, opts searchOptions) ([]store.Seed, error) {
+	if opts.entity != "" {
+		ids, err := s.EntitySeedIDs(r.Context(), opts.entity, opts.appID, opts.externalUserID)
+		if err != nil {
+			return nil, err
+		}
+		opts.seedIDs = intersectIDs(opts.seedIDs, ids)
+		if len(opts.seedIDs) == 0 {
+			return nil, nil
+		}
+	}
 	emb, err := model.Embed(q)
 	if err != nil {
 		return nil, err
 	}
-	seeds, err := s.Search(r.Context(), emb, limit, seedIDs, appID, externalUserID)
+	fetch := opts.limit
+	if opts.rank.Mode == store.RankHybrid || opts.mmr {
+		fetch = opts.limit * rankCandidateFactor
+		if fetch > maxRankCandidates {
+			fetch = maxRankCandidates
+		}
+	}
+	var seeds []store.Seed
+	if opts.mmr {
+		seeds, err = s.SearchWithEmbeddings(r.Context(), emb, fetch, opts.seedIDs, opts.appID, opts.externalUserID)
+	} else {
+		seeds, err = s.Search(r.Context(), emb, fetch, opts.seedIDs, opts.appID, opts.externalUserID)
+	}
 	if err != nil {
 		return nil, err
 	}
-	if threshold >= 0 {
+	if opts.threshold >= 0 {
 		filtered := seeds[:0]
 		for _, se := range seeds {
-			if se.Score >= threshold {
+			if se.Score >= opts.threshold {
 				filtered = append(filtered, se)
 			}
 		}
 		seeds = filtered
 	}
+	seeds = store.Rank(seeds, opts.rank, time.Now())
+	if opts.mmr {
+		seeds = store.MMR(seeds, opts.mmrLambda, opts.limit)
+	}
+	if len(seeds) > opts.limit {
+		seeds = seeds[:opts.limit]
+	}
+
+	if opts.contextWindow >= 0 {
+		if err := s.AttachDocumentContext(r.Context(), seeds, opts.contextWindow); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]int64, len(seeds))
+	for i, se := range seeds {
+		ids[i] = se.ID
+	}
+
+	if opts.expand && len(ids) > 0 {
+		// Related seeds follow the hits (Score 0, Via set); recordHits does not count them as accessed.
+		related, err := s.Neighbors(r.Context(), ids, 1, nil, opts.appID, opts.externalUserID)
+		if err != nil {
+			return nil, err
+		}
+		if len(related) > opts.limit {
+			related = related[:opts.limit]
+		}
+		seeds = append(seeds, related...)
+	}
 	return seeds, nil
 }
+
+// recordHits counts the search hits returned to the client as accessed. Seeds reached through the relation
+// graph (Via set) are context, not hits. Callers that filter the results further (recall) record only what
+// they return.
+func recordHits(s *store.Store, r *http.Request, seeds []store.Seed) {
+	ids := make([]int64, 0, len(seeds))
+	for _, se := range seeds {
+		if se.Via == nil {
+			ids = append(ids, se.ID)
+		}
+	}
+	recordAccess(s, r, ids)
+}
+
+func recordAccess(s *store.Store, r *http.Request, ids []int64) {
+	if len(ids) == 0 {
+		return
+	}
+	if err := s.RecordAccess(r.Context(), ids); err != nil {
+		log.Printf("record access: %v", err)
+	}
+}
+
+// intersectIDs restricts an optional seed ID filter to ids; with no prior filter it returns ids.
+func intersectIDs(filter, ids []int64) []int64 {
+	if len(filter) == 0 {
+		return ids
+	}
+	allowed := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		allowed[id] = true
+	}
+	var out []int64
+	for _, id := range filter {
+		if allowed[id] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/store"
+	"github.com/cabroe/neural-brain/internal/webhook"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// HandleCreateWebhook handles POST /webhooks?appId=&externalUserId=: register a URL for events of the tenant in
+// the query. Receiving the events of all tenants needs "allTenants": true and no tenant. Unless allowPrivate,
+// the URL must not point at a loopback, private or link-local address. The secret is returned only in this
+// response.
+func HandleCreateWebhook(s *store.Store, allowPrivate bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req apilib.CreateWebhookRequest
+		if err := apilib.DecodeJSON(r, &req); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		appID, externalUserID := r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId")
+		if req.AllTenants && (appID != "" || externalUserID != "") {
+			apilib.RespondError(w, http.StatusBadRequest, "allTenants cannot be combined with appId or externalUserId")
+			return
+		}
+		if !req.AllTenants && appID == "" {
+			apilib.RespondError(w, http.StatusBadRequest, "appId required (or allTenants: true for the events of every tenant)")
+			return
+		}
+		if err := webhook.CheckURL(r.Context(), req.URL, allowPrivate); err != nil {
+			apilib.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for _, t := range req.Events {
+			if !store.ValidEventType(t) {
+				apilib.RespondError(w, http.StatusBadRequest, "unknown event type: "+t)
+				return
+			}
+		}
+		secret := req.Secret
+		if secret == "" {
+			b := make([]byte, 24)
+			if _, err := rand.Read(b); err != nil {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			secret = "whsec_" + hex.EncodeToString(b)
+		}
+		hook := store.Webhook{
+			URL:            req.URL,
+			Secret:         secret,
+			EventTypes:     req.Events,
+			Description:    req.Description,
+			Active:         true,
+			AppID:          appID,
+			ExternalUserID: externalUserID,
+		}
+		id, err := s.InsertWebhook(r.Context(), hook)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		created, err := s.GetWebhook(r.Context(), id)
+		if err != nil || created == nil {
+			apilib.RespondError(w, http.StatusInternalServerError, "webhook not readable after insert")
+			return
+		}
+		apilib.RespondJSON(w, http.StatusCreated, created)
+	}
+}
+
+// HandleListWebhooks handles GET /webhooks?appId=&externalUserId=.
+func HandleListWebhooks(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		hooks, err := s.ListWebhooks(r.Context(), r.URL.Query().Get("appId"), r.URL.Query().Get("externalUserId"))
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if hooks == nil {
+			hooks = []store.Webhook{}
+		}
+		for i := range hooks {
+			hooks[i].Secret = ""
+		}
+		apilib.RespondJSON(w, http.StatusOK, hooks)
+	}
+}
+
+// HandleGetWebhook handles GET /webhooks/{id}.
+func HandleGetWebhook(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		hook, err := s.GetWebhook(r.Context(), id)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if hook == nil {
+			apilib.RespondError(w, http.StatusNotFound, "not found")
+			return
+		}
+		hook.Secret = ""
+		apilib.RespondJSON(w, http.StatusOK, hook)
+	}
+}
+
+// HandleDeleteWebhook handles DELETE /webhooks/{id}; queued deliveries are dropped with it.
+func HandleDeleteWebhook(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		if err := s.DeleteWebhook(r.Context(), id); err != nil {
+			if err == pgx.ErrNoRows {
+				apilib.RespondError(w, http.StatusNotFound, "not found")
+			} else {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+// HandleListWebhookDeliveries handles GET /webhooks/{id}/deliveries?status=dead&limit=50, newest first.
+func HandleListWebhookDeliveries(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		status := r.URL.Query().Get("status")
+		if status != "" && !store.ValidDeliveryStatus(status) {
+			apilib.RespondError(w, http.StatusBadRequest, "status must be pending, succeeded or dead")
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit <= 0 {
+			limit = defaultDeliveryLimit
+		}
+		if limit > maxDeliveryLimit {
+			limit = maxDeliveryLimit
+		}
+		hook, err := s.GetWebhook(r.Context(), id)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if hook == nil {
+			apilib.RespondError(w, http.StatusNotFound, "not found")
+			return
+		}
+		deliveries, err := s.ListWebhookDeliveries(r.Context(), id, status, limit)
+		if err != nil {
+			apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if deliveries == nil {
+			deliveries = []store.WebhookDelivery{}
+		}
+		apilib.RespondJSON(w, http.StatusOK, deliveries)
+	}
+}
+
+// HandleRetryWebhookDelivery handles POST /webhooks/{id}/deliveries/{deliveryId}/retry: requeue a dead delivery.
+func HandleRetryWebhookDelivery(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil || id <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		deliveryID, err := strconv.ParseInt(r.PathValue("deliveryId"), 10, 64)
+		if err != nil || deliveryID <= 0 {
+			apilib.RespondError(w, http.StatusBadRequest, "invalid deliveryId")
+			return
+		}
+		if err := s.RetryWebhookDelivery(r.Context(), id, deliveryID); err != nil {
+			if err == pgx.ErrNoRows {
+				apilib.RespondError(w, http.StatusNotFound, "no dead delivery with that id")
+			} else {
+				apilib.RespondError(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		apilib.RespondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
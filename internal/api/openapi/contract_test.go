@@ -0,0 +1,322 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/api/openapi"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+type schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Enum       []interface{}      `json:"enum"`
+	Properties map[string]*schema `json:"properties"`
+	Items      *schema            `json:"items"`
+	AllOf      []*schema          `json:"allOf"`
+}
+
+type document struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadSpec(t *testing.T) *document {
+	t.Helper()
+	var doc document
+	if err := json.Unmarshal(openapi.Spec, &doc); err != nil {
+		t.Fatalf("openapi.json: %v", err)
+	}
+	return &doc
+}
+
+// contractTypes are the Go types whose JSON encoding a component schema documents. Types encoded from maps
+// (e.g. Stats, IdResponse) have no struct to compare and are not listed.
+var contractTypes = map[string][]interface{}{
+	"StoreSeedRequest":        {apilib.StoreSeedRequest{}},
+	"StoreSeedsBatchRequest":  {apilib.StoreSeedsBatchRequest{}},
+	"SeedsQueryRequest":       {apilib.SeedsQueryRequest{}},
+	"RankOptions":             {apilib.RankOptions{}},
+	"SeedQueryResult":         {apilib.SeedQueryResult{}},
+	"ScoreBreakdown":          {apilib.ScoreBreakdown{}, store.ScoreBreakdown{}},
+	"DocumentContext":         {apilib.DocumentContext{}, store.DocumentContext{}},
+	"EdgeRef":                 {apilib.EdgeRef{}, store.EdgeRef{}},
+	"CreateContextRequest":    {apilib.CreateContextRequest{}},
+	"RecallRequest":           {apilib.RecallRequest{}},
+	"RecallResponse":          {apilib.RecallResponse{}},
+	"CaptureRequest":          {apilib.CaptureRequest{}},
+	"CaptureMessage":          {apilib.CaptureMessage{}},
+	"CapturedSeed":            {apilib.CapturedSeed{}},
+	"CaptureResponse":         {apilib.CaptureResponse{}},
+	"CreateDocumentRequest":   {apilib.CreateDocumentRequest{}},
+	"CreateGoalRequest":       {apilib.CreateGoalRequest{}},
+	"UpdateGoalRequest":       {apilib.UpdateGoalRequest{}},
+	"EvaluateGoalsRequest":    {apilib.EvaluateGoalsRequest{}},
+	"SetEmotionRequest":       {apilib.SetEmotionRequest{}},
+	"VAD":                     {apilib.VAD{}, store.VAD{}},
+	"EmotionEventRequest":     {apilib.EmotionEventRequest{}},
+	"CommitBeliefRequest":     {apilib.CommitBeliefRequest{}},
+	"MetricPointInput":        {apilib.MetricPointInput{}},
+	"MetricPointsRequest":     {apilib.MetricPointsRequest{}},
+	"LearningRuleRequest":     {apilib.LearningRuleRequest{}},
+	"EvaluateLearningRequest": {apilib.EvaluateLearningRequest{}},
+	"CreateEdgeRequest":       {apilib.CreateEdgeRequest{}},
+	"CreateEntityRequest":     {apilib.CreateEntityRequest{}},
+	"ComputeClustersRequest":  {apilib.ComputeClustersRequest{}},
+	"MergeDuplicatesRequest":  {apilib.MergeDuplicatesRequest{}},
+	"CreateWebhookRequest":    {apilib.CreateWebhookRequest{}},
+
+	"Seed":               {store.Seed{}},
+	"AgentContext":       {store.AgentContext{}},
+	"Document":           {store.Document{}},
+	"Goal":               {store.Goal{}},
+	"GoalScore":          {store.GoalScore{}},
+	"Emotion":            {store.Emotion{}},
+	"EmotionEvent":       {store.EmotionEvent{}},
+	"Belief":             {store.Belief{}},
+	"BeliefRevision":     {store.BeliefRevision{}},
+	"SeriesPoint":        {store.SeriesPoint{}},
+	"LearningRule":       {store.LearningRule{}},
+	"LearningEvaluation": {store.LearningEvaluation{}},
+	"Edge":               {store.Edge{}},
+	"Entity":             {store.Entity{}},
+	"Cluster":            {store.Cluster{}},
+	"DuplicatePair":      {store.DuplicatePair{}},
+	"DuplicateGroup":     {store.DuplicateGroup{}},
+	"JobRun":             {store.JobRun{}},
+	"Event":              {store.Event{}},
+	"Webhook":            {store.Webhook{}},
+	"WebhookDelivery":    {store.WebhookDelivery{}},
+}
+
+// TestSchemasMatchTypes compares every documented schema with the JSON fields of its Go types: the same
+// property names, and compatible JSON types for each.
+func TestSchemasMatchTypes(t *testing.T) {
+	doc := loadSpec(t)
+	names := make([]string, 0, len(contractTypes))
+	for name := range contractTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sc, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Errorf("components.schemas has no %s", name)
+			continue
+		}
+		props := properties(doc, sc)
+		for _, v := range contractTypes[name] {
+			typ := reflect.TypeOf(v)
+			fields := jsonFields(typ)
+			for field, ft := range fields {
+				p, ok := props[field]
+				if !ok {
+					t.Errorf("%s: %s.%s is not in the schema", name, typ, field)
+					continue
+				}
+				if want, got := jsonType(ft), schemaType(doc, p); want != "" && got != "" && want != got {
+					t.Errorf("%s.%s: schema type %s, Go type %s encodes as %s", name, field, got, ft, want)
+				}
+			}
+			for prop := range props {
+				if _, ok := fields[prop]; !ok {
+					t.Errorf("%s: property %q has no field in %s", name, prop, typ)
+				}
+			}
+		}
+	}
+}
+
+// properties returns a schema's properties, merging allOf parts.
+func properties(doc *document, sc *schema) map[string]*schema {
+	sc = resolve(doc, sc)
+	props := map[string]*schema{}
+	for _, part := range sc.AllOf {
+		for k, v := range properties(doc, part) {
+			props[k] = v
+		}
+	}
+	for k, v := range sc.Properties {
+		props[k] = v
+	}
+	return props
+}
+
+func resolve(doc *document, sc *schema) *schema {
+	for sc != nil && sc.Ref != "" {
+		sc = doc.Components.Schemas[strings.TrimPrefix(sc.Ref, "#/components/schemas/")]
+	}
+	if sc == nil {
+		return &schema{}
+	}
+	return sc
+}
+
+// schemaType is the JSON type a property schema describes, "" if any value is allowed.
+func schemaType(doc *document, sc *schema) string {
+	sc = resolve(doc, sc)
+	if sc.Type == "" && (len(sc.Properties) > 0 || len(sc.AllOf) > 0) {
+		return "object"
+	}
+	return sc.Type
+}
+
+// jsonFields returns the JSON names of a struct's encoded fields, including promoted ones of embedded structs.
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for k, v := range jsonFields(f.Type) {
+				if _, ok := fields[k]; !ok {
+					fields[k] = v
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+var (
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+	timeType       = reflect.TypeOf(time.Time{})
+)
+
+// jsonType is the JSON type encoding/json produces for t, "" for raw JSON.
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == rawMessageType:
+		return ""
+	case t == timeType:
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	}
+	return ""
+}
+
+// TestRoutesDocumented checks that every pattern main.go registers is in paths, and every documented
+// operation is registered.
+func TestRoutesDocumented(t *testing.T) {
+	doc := loadSpec(t)
+	routes := muxPatterns(t, filepath.Join("..", "..", "..", "main.go"))
+	if len(routes) == 0 {
+		t.Fatal("no mux patterns found in main.go")
+	}
+	registered := map[string]bool{}
+	for _, pattern := range routes {
+		method, path, ok := strings.Cut(pattern, " ")
+		if !ok {
+			method, path = "", pattern
+		}
+		if path == "/" {
+			continue // the dashboard
+		}
+		ops, ok := doc.Paths[path]
+		if !ok {
+			t.Errorf("%s is registered in main.go but not documented", pattern)
+			continue
+		}
+		if method == "" {
+			for op := range ops {
+				registered[strings.ToUpper(op)+" "+path] = true
+			}
+			continue
+		}
+		registered[pattern] = true
+		if _, ok := ops[strings.ToLower(method)]; !ok {
+			t.Errorf("%s is registered in main.go but paths[%q] has no %s", pattern, path, strings.ToLower(method))
+		}
+	}
+	for path, ops := range doc.Paths {
+		for op := range ops {
+			method := strings.ToUpper(op)
+			if !isMethod(method) {
+				continue // e.g. shared "parameters"
+			}
+			if !registered[method+" "+path] {
+				t.Errorf("%s %s is documented but not registered in main.go", method, path)
+			}
+		}
+	}
+}
+
+func isMethod(m string) bool {
+	switch m {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// muxPatterns returns the string patterns of all mux.Handle and mux.HandleFunc calls in a Go file.
+func muxPatterns(t *testing.T, file string) []string {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		t.Fatalf("parse %s: %v", file, err)
+	}
+	var patterns []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "Handle" && sel.Sel.Name != "HandleFunc") {
+			return true
+		}
+		if recv, ok := sel.X.(*ast.Ident); !ok || recv.Name != "mux" {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		if s, err := strconv.Unquote(lit.Value); err == nil {
+			patterns = append(patterns, s)
+		}
+		return true
+	})
+	return patterns
+}
@@ -0,0 +1,15 @@
+// Package openapi embeds the OpenAPI 3 document of the HTTP API and its interactive docs page.
+package openapi
+
+import _ "embed"
+
+// Spec is the OpenAPI document served at GET /openapi.json. Keep it in sync with the routes in main.go
+// and the request/response types in internal/api.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// DocsPage renders Spec with a "try it" form per operation; it is served at GET /docs.
+//
+//go:embed docs.html
+var DocsPage []byte
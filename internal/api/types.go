@@ -1,6 +1,8 @@
 package api
 
-import "encoding/json"
+import (
+	"encoding/json"
+)
 
 // StoreSeedRequest is the JSON body for POST /seeds.
 type StoreSeedRequest struct {
@@ -8,19 +10,74 @@ type StoreSeedRequest struct {
 	Metadata json.RawMessage `json:"metadata"`
 }
 
+// StoreSeedsBatchRequest is the JSON body for POST /seeds/batch.
+type StoreSeedsBatchRequest struct {
+	Seeds []StoreSeedRequest `json:"seeds"`
+}
+
 // SeedsQueryRequest is the JSON body for POST /seeds/query (Neutron-compatible).
 type SeedsQueryRequest struct {
-	Query     string  `json:"query"`
-	Limit     int     `json:"limit"`
-	Threshold float64 `json:"threshold"`
-	SeedIDs   []int64 `json:"seedIds,omitempty"`
+	Query     string       `json:"query"`
+	Limit     int          `json:"limit"`
+	Threshold float64      `json:"threshold"`
+	SeedIDs   []int64      `json:"seedIds,omitempty"`
+	Rank      *RankOptions `json:"rank,omitempty"`
+	MMR       bool         `json:"mmr,omitempty"`       // diversify results with maximal marginal relevance
+	MMRLambda *float64     `json:"mmrLambda,omitempty"` // 1 = pure relevance, 0 = pure diversity
+	// ContextWindow attaches parent document title and this many neighbouring chunks to document hits.
+	ContextWindow *int `json:"contextWindow,omitempty"`
+	// Expand appends seeds one relation-graph hop away from the hits.
+	Expand bool `json:"expand,omitempty"`
+	// Entity restricts results to seeds mentioning this entity (ID, name or alias).
+	Entity string `json:"entity,omitempty"`
+}
+
+// RankOptions selects the ranking mode and weights for a search. Unset fields keep the server defaults.
+// GET /search accepts the same names as query parameters (rank=hybrid&halfLifeHours=24&wRecency=2).
+type RankOptions struct {
+	Mode             string   `json:"mode"`
+	HalfLifeHours    float64  `json:"halfLifeHours"`
+	WeightSimilarity *float64 `json:"wSimilarity"`
+	WeightRecency    *float64 `json:"wRecency"`
+	WeightImportance *float64 `json:"wImportance"`
+	WeightAccess     *float64 `json:"wAccess"`
 }
 
 // SeedQueryResult is a Neutron-style result item: seedId, content, similarity.
 type SeedQueryResult struct {
-	SeedID     string  `json:"seedId"`
-	Content    string  `json:"content"`
+	SeedID         string           `json:"seedId"`
+	Content        string           `json:"content"`
+	Similarity     float64          `json:"similarity"`
+	ScoreBreakdown *ScoreBreakdown  `json:"scoreBreakdown,omitempty"`
+	Document       *DocumentContext `json:"document,omitempty"`
+	Via            *EdgeRef         `json:"via,omitempty"` // set for results added by expand
+}
+
+// ScoreBreakdown shows how a hybrid-ranked result's score was composed.
+type ScoreBreakdown struct {
 	Similarity float64 `json:"similarity"`
+	Recency    float64 `json:"recency"`
+	Importance float64 `json:"importance"`
+	Access     float64 `json:"access"`
+	Total      float64 `json:"total"`
+}
+
+// DocumentContext places a document chunk within its document.
+type DocumentContext struct {
+	DocumentID int64  `json:"documentId"`
+	Title      string `json:"title"`
+	ChunkIndex int    `json:"chunkIndex"`
+	ChunkCount int    `json:"chunkCount"`
+	Context    string `json:"context"`
+}
+
+// EdgeRef tells how a result added by graph expansion is related to a hit.
+type EdgeRef struct {
+	SeedID    int64   `json:"seedId"`
+	Type      string  `json:"type"`
+	Direction string  `json:"direction"`
+	Weight    float64 `json:"weight"`
+	Depth     int     `json:"depth"`
 }
 
 // CreateContextRequest is the JSON body for POST /agent-contexts (Neutron uses data/metadata, we accept payload or data).
@@ -31,3 +88,189 @@ type CreateContextRequest struct {
 	Data       json.RawMessage `json:"data"`
 	Metadata   json.RawMessage `json:"metadata"`
 }
+
+// RecallRequest is the JSON body for POST /recall. Tenant fields fall back to the appId/externalUserId query params.
+type RecallRequest struct {
+	Message        string       `json:"message"`
+	AppID          string       `json:"appId"`
+	ExternalUserID string       `json:"externalUserId"`
+	Limit          int          `json:"limit"`
+	Threshold      *float64     `json:"threshold"`
+	MaxTokens      int          `json:"maxTokens"` // approximate, 4 characters per token
+	MaxChars       int          `json:"maxChars"`  // takes precedence over maxTokens
+	Format         string       `json:"format"`    // plain (default), markdown, xml
+	MMRLambda      *float64     `json:"mmrLambda"`
+	Rank           *RankOptions `json:"rank"`
+}
+
+// RecallResponse is the prompt-ready memory block returned by POST /recall.
+type RecallResponse struct {
+	Block     string  `json:"block"`
+	IDs       []int64 `json:"ids"`
+	Truncated bool    `json:"truncated"`
+}
+
+// CaptureRequest is the JSON body for POST /capture: one conversation turn.
+type CaptureRequest struct {
+	Messages       []CaptureMessage `json:"messages"`
+	TurnID         string           `json:"turnId"`  // optional client-side turn id, stored on every captured seed
+	AgentID        string           `json:"agentId"` // owner of the episodic turn context; defaults to appId
+	AppID          string           `json:"appId"`
+	ExternalUserID string           `json:"externalUserId"`
+}
+
+// CaptureMessage is one message of a captured turn.
+type CaptureMessage struct {
+	Role string `json:"role"` // user or assistant, optional
+	Text string `json:"text"`
+}
+
+// CapturedSeed is a memory stored (or skipped) by POST /capture.
+type CapturedSeed struct {
+	ID          int64  `json:"id,omitempty"`
+	Content     string `json:"content"`
+	Reason      string `json:"reason,omitempty"`      // set for skipped candidates
+	DuplicateOf int64  `json:"duplicateOf,omitempty"` // existing seed a skipped candidate matched
+}
+
+// CaptureResponse lists what POST /capture stored and skipped.
+type CaptureResponse struct {
+	TurnContextID string         `json:"turnContextId,omitempty"`
+	Stored        []CapturedSeed `json:"stored"`
+	Skipped       []CapturedSeed `json:"skipped"`
+}
+
+// CreateDocumentRequest is the JSON body for POST /documents (multipart uploads use a "file" field instead).
+type CreateDocumentRequest struct {
+	Title         string          `json:"title"`
+	Content       string          `json:"content"`
+	Format        string          `json:"format"` // text (default) or markdown
+	Metadata      json.RawMessage `json:"metadata"`
+	ChunkTokens   int             `json:"chunkTokens"`
+	OverlapTokens *int            `json:"overlapTokens"`
+}
+
+// CreateGoalRequest is the JSON body for POST /goals.
+type CreateGoalRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ParentID    *int64 `json:"parentId"`
+	Priority    int    `json:"priority"`
+	Deadline    string `json:"deadline"` // RFC 3339, optional
+}
+
+// UpdateGoalRequest is the JSON body for PATCH /goals/{id}. Omitted fields are unchanged; deadline "" clears it.
+type UpdateGoalRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Priority    *int    `json:"priority"`
+	Deadline    *string `json:"deadline"`
+	Status      *string `json:"status"`
+}
+
+// EvaluateGoalsRequest is the JSON body for POST /goals/evaluate.
+type EvaluateGoalsRequest struct {
+	Action string `json:"action"`
+}
+
+// SetEmotionRequest is the JSON body for PUT /agents/{id}/emotion. All values are on the 0-10 scale.
+type SetEmotionRequest struct {
+	Valence            *float64 `json:"valence"`
+	Arousal            *float64 `json:"arousal"`
+	Dominance          *float64 `json:"dominance"`
+	Reason             string   `json:"reason"`
+	Baseline           *VAD     `json:"baseline"`           // optional: state the agent decays toward
+	DecayHalfLifeHours *float64 `json:"decayHalfLifeHours"` // optional: 0 disables decay
+}
+
+// VAD is an emotion state on the 0-10 scale.
+type VAD struct {
+	Valence   float64 `json:"valence"`
+	Arousal   float64 `json:"arousal"`
+	Dominance float64 `json:"dominance"`
+}
+
+// EmotionEventRequest is the JSON body for POST /agents/{id}/emotion/events: deltas applied to the current state.
+type EmotionEventRequest struct {
+	Valence   float64 `json:"valence"`
+	Arousal   float64 `json:"arousal"`
+	Dominance float64 `json:"dominance"`
+	Reason    string  `json:"reason"`
+}
+
+// CommitBeliefRequest is the JSON body for POST /beliefs.
+type CommitBeliefRequest struct {
+	Content       string   `json:"content"`
+	Importance    *float64 `json:"importance"` // 1-10, default 8
+	Confidence    *float64 `json:"confidence"` // 0-1, default 0.8
+	SourceSeedIDs []int64  `json:"sourceSeedIds"`
+}
+
+// MetricPointInput is one observation in POST /metrics/points. Timestamp is RFC 3339 and defaults to now.
+type MetricPointInput struct {
+	Name      string          `json:"name"`
+	Value     *float64        `json:"value"`
+	Labels    json.RawMessage `json:"labels"`
+	Timestamp string          `json:"timestamp"`
+}
+
+// MetricPointsRequest is the JSON body for POST /metrics/points: either a single point or a "points" batch.
+type MetricPointsRequest struct {
+	MetricPointInput
+	Points []MetricPointInput `json:"points"`
+}
+
+// LearningRuleRequest is the JSON body for POST /learning/rules and PUT /learning/rules/{id}.
+type LearningRuleRequest struct {
+	Name         string          `json:"name"`
+	Kind         string          `json:"kind"`      // semantic or lexical
+	Pattern      string          `json:"pattern"`   // prototype phrase or regex
+	Threshold    float64         `json:"threshold"` // semantic: minimum similarity, default 0.8
+	WindowHours  float64         `json:"windowHours"`
+	MinCount     int             `json:"minCount"`
+	Action       string          `json:"action"` // learning_seed, tag or emotion
+	ActionParams json.RawMessage `json:"actionParams"`
+	Enabled      *bool           `json:"enabled"` // default true
+}
+
+// EvaluateLearningRequest is the optional JSON body for POST /learning/evaluate.
+type EvaluateLearningRequest struct {
+	RuleID int64 `json:"ruleId"` // 0: all enabled rules
+	DryRun bool  `json:"dryRun"`
+}
+
+// CreateEdgeRequest is the JSON body for POST /seeds/{id}/edges: an edge from the path seed to TargetID.
+type CreateEdgeRequest struct {
+	TargetID int64    `json:"targetId"`
+	Type     string   `json:"type"`
+	Weight   *float64 `json:"weight"` // default 1
+}
+
+// CreateEntityRequest is the JSON body for POST /entities.
+type CreateEntityRequest struct {
+	Name    string   `json:"name"`
+	Kind    string   `json:"kind"`
+	Aliases []string `json:"aliases"`
+}
+
+// ComputeClustersRequest is the optional JSON body for POST /clusters/compute.
+type ComputeClustersRequest struct {
+	K             int `json:"k"`             // 0: chosen from the number of seeds
+	MaxIterations int `json:"maxIterations"` // default 25
+	Keywords      int `json:"keywords"`      // keywords per cluster, default 5
+}
+
+// MergeDuplicatesRequest is the JSON body for POST /duplicates/merge.
+type MergeDuplicatesRequest struct {
+	SurvivorID int64   `json:"survivorId"` // optional, default: lowest of seedIds
+	SeedIDs    []int64 `json:"seedIds"`    // seeds to fold into the survivor; may include it
+}
+
+// CreateWebhookRequest is the JSON body for POST /webhooks.
+type CreateWebhookRequest struct {
+	URL         string   `json:"url"`
+	Events      []string `json:"events"` // event types; empty: all
+	Secret      string   `json:"secret"` // HMAC key; generated when empty
+	Description string   `json:"description"`
+	AllTenants  bool     `json:"allTenants"` // required to register without appId: receive every tenant's events
+}
@@ -0,0 +1,132 @@
+// Package capture turns conversation turns into candidate memory statements.
+package capture
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// Message is one utterance of a conversation turn.
+type Message struct {
+	Role string `json:"role"`
+	Text string `json:"text"`
+}
+
+// Candidate is a statement worth remembering, with the role that said it (empty if unknown).
+type Candidate struct {
+	Content string `json:"content"`
+	Role    string `json:"role"`
+}
+
+// Extractor splits a turn into candidate memories. Implementations must be safe for concurrent use.
+type Extractor interface {
+	Extract(ctx context.Context, turn []Message) ([]Candidate, error)
+}
+
+// MaxCandidates caps how many statements a single turn may produce.
+const MaxCandidates = 10
+
+const (
+	minWords = 4
+	minChars = 15
+)
+
+// chatter holds lowercased phrases (and prefixes) that carry no lasting information.
+var chatter = []string{
+	"hi", "hello", "hey", "hallo", "moin", "servus",
+	"thanks", "thank you", "danke", "vielen dank",
+	"ok", "okay", "sure", "great", "cool", "nice", "perfect", "gerne", "bitte", "alles klar", "super",
+	"let me", "i'll", "i will", "here is", "here's", "ich werde", "hier ist", "lass mich",
+	"sounds good", "got it", "verstanden", "no problem", "kein problem",
+}
+
+// Heuristic is the default Extractor: sentence segmentation plus chatter and question filtering.
+type Heuristic struct{}
+
+// Extract implements Extractor.
+func (Heuristic) Extract(_ context.Context, turn []Message) ([]Candidate, error) {
+	var out []Candidate
+	seen := make(map[string]bool)
+	for _, m := range turn {
+		role := strings.ToLower(strings.TrimSpace(m.Role))
+		if role == "system" || role == "tool" {
+			continue
+		}
+		for _, sent := range Sentences(m.Text) {
+			key := strings.ToLower(sent)
+			if seen[key] || IsTrivial(sent) {
+				continue
+			}
+			seen[key] = true
+			out = append(out, Candidate{Content: sent, Role: role})
+			if len(out) >= MaxCandidates {
+				return out, nil
+			}
+		}
+	}
+	return out, nil
+}
+
+// Sentences splits text at line breaks, list bullets and sentence-ending punctuation followed by whitespace.
+func Sentences(text string) []string {
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•#>0123456789.) "))
+		if line == "" {
+			continue
+		}
+		runes := []rune(line)
+		start := 0
+		for i, r := range runes {
+			if r != '.' && r != '!' && r != '?' {
+				continue
+			}
+			if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+				continue // e.g. "v1.2", "example.com"
+			}
+			if s := strings.TrimSpace(string(runes[start : i+1])); s != "" {
+				out = append(out, s)
+			}
+			start = i + 1
+		}
+		if s := strings.TrimSpace(string(runes[start:])); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// IsTrivial reports whether a sentence is too short, a question, or conversational filler.
+func IsTrivial(sent string) bool {
+	if len(sent) < minChars || len(strings.Fields(sent)) < minWords {
+		return true
+	}
+	if strings.HasSuffix(sent, "?") {
+		return true
+	}
+	lower := strings.ToLower(strings.TrimRight(sent, ".!"))
+	for _, c := range chatter {
+		if lower == c || strings.HasPrefix(lower, c+" ") || strings.HasPrefix(lower, c+",") || strings.HasPrefix(lower, c+"!") {
+			return true
+		}
+	}
+	return false
+}
+
+// Fallback tries Primary and falls back to Secondary when it fails. An empty result from Primary is its
+// answer ("nothing worth keeping") and is returned as is.
+type Fallback struct {
+	Primary   Extractor
+	Secondary Extractor
+}
+
+// Extract implements Extractor.
+func (f Fallback) Extract(ctx context.Context, turn []Message) ([]Candidate, error) {
+	if f.Primary != nil {
+		if out, err := f.Primary.Extract(ctx, turn); err == nil {
+			return out, nil
+		}
+	}
+	return f.Secondary.Extract(ctx, turn)
+}
@@ -0,0 +1,96 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const llmSystemPrompt = `Extract durable facts, preferences, decisions and commitments from the conversation turn.
+Ignore greetings, small talk, questions and anything only relevant right now.
+Reply with a JSON array of short, self-contained statements (strings) and nothing else. Reply [] if there is nothing to keep.`
+
+// LLM is an Extractor backed by a local OpenAI-compatible chat completions endpoint
+// (llama.cpp server, Ollama's /v1, LM Studio, ...).
+type LLM struct {
+	URL    string // e.g. http://localhost:11434/v1/chat/completions
+	Model  string
+	Client *http.Client
+}
+
+// LLMTimeout bounds one completion. POST /capture still has to embed and store the result within the
+// server's 10 s write timeout, so a slow model fails over to the heuristic instead of outliving the response.
+const LLMTimeout = 5 * time.Second
+
+// NewLLM returns an LLM extractor that gives up after LLMTimeout.
+func NewLLM(url, model string) *LLM {
+	return &LLM{URL: url, Model: model, Client: &http.Client{Timeout: LLMTimeout}}
+}
+
+// Extract implements Extractor.
+func (l *LLM) Extract(ctx context.Context, turn []Message) ([]Candidate, error) {
+	var transcript strings.Builder
+	for _, m := range turn {
+		fmt.Fprintf(&transcript, "%s: %s\n", m.Role, m.Text)
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"model":       l.Model,
+		"temperature": 0,
+		"messages": []map[string]string{
+			{"role": "system", "content": llmSystemPrompt},
+			{"role": "user", "content": transcript.String()},
+		},
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("llm extractor: status %d", resp.StatusCode)
+	}
+	var completion struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return nil, err
+	}
+	if len(completion.Choices) == 0 {
+		return nil, errors.New("llm extractor: empty response")
+	}
+	content := strings.TrimSpace(completion.Choices[0].Message.Content)
+	// Tolerate models that wrap the array in a code fence or prose.
+	if i, j := strings.Index(content, "["), strings.LastIndex(content, "]"); i >= 0 && j > i {
+		content = content[i : j+1]
+	}
+	var statements []string
+	if err := json.Unmarshal([]byte(content), &statements); err != nil {
+		return nil, fmt.Errorf("llm extractor: %w", err)
+	}
+	var out []Candidate
+	for _, st := range statements {
+		st = strings.TrimSpace(st)
+		if st == "" {
+			continue
+		}
+		out = append(out, Candidate{Content: st})
+		if len(out) >= MaxCandidates {
+			break
+		}
+	}
+	return out, nil
+}
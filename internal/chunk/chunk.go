@@ -0,0 +1,173 @@
+// Package chunk splits long documents into overlapping, token-bounded pieces for embedding.
+package chunk
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GTE-Small truncates input at 512 WordPiece tokens (including [CLS]/[SEP]); the defaults leave headroom
+// for the estimate below being off.
+const (
+	DefaultMaxTokens     = 400
+	DefaultOverlapTokens = 50
+)
+
+// Chunk is a slice of the source text. Start/End are byte offsets into the original document.
+type Chunk struct {
+	Index  int
+	Text   string
+	Start  int
+	End    int
+	Tokens int
+}
+
+type word struct {
+	start, end int
+	tokens     int
+	breakAfter bool // paragraph or heading boundary follows this word
+}
+
+// EstimateTokens approximates the WordPiece token count of a single whitespace-delimited word:
+// punctuation splits into its own tokens and long or non-ASCII words break into several sub-words.
+func EstimateTokens(w string) int {
+	n := 0
+	run := 0
+	flush := func() {
+		if run > 0 {
+			n += 1 + (run-1)/6
+			run = 0
+		}
+	}
+	for _, r := range w {
+		switch {
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			flush()
+			n++
+		case r >= utf8.RuneSelf:
+			run += 2
+		default:
+			run++
+		}
+	}
+	flush()
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+// Split cuts text into chunks of at most maxTokens estimated tokens, repeating roughly overlapTokens
+// at the start of each following chunk. It prefers to end a chunk at a paragraph or heading boundary.
+func Split(text string, maxTokens, overlapTokens int) []Chunk {
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxTokens
+	}
+	if overlapTokens < 0 || overlapTokens >= maxTokens {
+		overlapTokens = 0
+	}
+	words := scanWords(text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var chunks []Chunk
+	start := 0
+	for start < len(words) {
+		end, tokens := start, 0
+		lastBreak := -1
+		for end < len(words) && (tokens+words[end].tokens <= maxTokens || end == start) {
+			tokens += words[end].tokens
+			if words[end].breakAfter {
+				lastBreak = end
+			}
+			end++
+		}
+		// Back off to a paragraph boundary if one lies in the last third of the window.
+		if end < len(words) && lastBreak >= start && lastBreak < end-1 {
+			kept := 0
+			for i := start; i <= lastBreak; i++ {
+				kept += words[i].tokens
+			}
+			if kept*3 >= maxTokens*2 {
+				end, tokens = lastBreak+1, kept
+			}
+		}
+		chunks = append(chunks, Chunk{
+			Index:  len(chunks),
+			Text:   text[words[start].start:words[end-1].end],
+			Start:  words[start].start,
+			End:    words[end-1].end,
+			Tokens: tokens,
+		})
+		if end >= len(words) {
+			break
+		}
+		next, overlap := end, 0
+		for next > start+1 && overlap+words[next-1].tokens <= overlapTokens {
+			next--
+			overlap += words[next].tokens
+		}
+		start = next
+	}
+	return chunks
+}
+
+// scanWords returns whitespace-delimited words with offsets, marking blank lines and
+// markdown headings as boundaries.
+func scanWords(text string) []word {
+	var words []word
+	i := 0
+	for i < len(text) {
+		r, _ := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsSpace(r) {
+			j := i
+			for j < len(text) {
+				r2, s2 := utf8.DecodeRuneInString(text[j:])
+				if unicode.IsSpace(r2) {
+					break
+				}
+				j += s2
+			}
+			if len(words) > 0 && strings.HasPrefix(text[i:j], "#") && atLineStart(text, i) {
+				words[len(words)-1].breakAfter = true
+			}
+			words = append(words, word{start: i, end: j, tokens: EstimateTokens(text[i:j])})
+			i = j
+			continue
+		}
+		// Whitespace run: a blank line ends a paragraph.
+		j := i
+		newlines := 0
+		for j < len(text) {
+			r2, s2 := utf8.DecodeRuneInString(text[j:])
+			if !unicode.IsSpace(r2) {
+				break
+			}
+			if r2 == '\n' {
+				newlines++
+			}
+			j += s2
+		}
+		if newlines >= 2 && len(words) > 0 {
+			words[len(words)-1].breakAfter = true
+		}
+		i = j
+	}
+	return words
+}
+
+func atLineStart(text string, i int) bool {
+	for k := i - 1; k >= 0; k-- {
+		switch text[k] {
+		case '\n':
+			return true
+		case ' ', '\t':
+			continue
+		default:
+			return false
+		}
+	}
+	return true
+}
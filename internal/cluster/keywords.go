@@ -0,0 +1,100 @@
+package cluster
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// stopwords are frequent German and English function words excluded from cluster keywords.
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		aber alle allem allen aller alles als also auch auf aus bei beim bin bis bist das dass dem den der des die dies diese
+		dieser dieses doch dort durch ein eine einem einen einer eines für hab habe haben hat hatte hier ich ihm ihn ihr im in
+		ist jetzt kann kein keine man mehr mein meine mich mir mit muss nach nicht noch nur oder ohne schon sehr sein seine
+		sich sie sind so über um und uns unter vom von vor war waren was weil wenn wer wie wir wird wo zu zum zur
+		about after all also and are because been but can could did does for from had has have her here him his how into
+		its just more not now only our out over she should some than that the their them then there these they this those
+		very was were what when where which who will with would you your user assistant
+	`) {
+		stopwords[w] = true
+	}
+}
+
+// Tokens returns the lowercased words of text that are at least three letters long and not stopwords.
+func Tokens(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := words[:0]
+	for _, w := range words {
+		if utf8.RuneCountInString(w) >= 3 && !stopwords[w] && !isNumber(w) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+func isNumber(w string) bool {
+	for _, r := range w {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Keywords returns up to n terms per cluster that are frequent in the cluster but rare in the others
+// (term frequency in the cluster times inverse cluster frequency). texts[i] is assigned to cluster assign[i].
+func Keywords(texts []string, assign []int, k, n int) [][]string {
+	tf := make([]map[string]int, k)
+	for c := range tf {
+		tf[c] = map[string]int{}
+	}
+	for i, t := range texts {
+		seen := map[string]bool{}
+		for _, w := range Tokens(t) {
+			if !seen[w] { // count documents, not repetitions within one seed
+				seen[w] = true
+				tf[assign[i]][w]++
+			}
+		}
+	}
+	clusterFreq := map[string]int{}
+	for _, m := range tf {
+		for w := range m {
+			clusterFreq[w]++
+		}
+	}
+
+	out := make([][]string, k)
+	for c, m := range tf {
+		type scored struct {
+			word  string
+			score float64
+		}
+		var terms []scored
+		for w, count := range m {
+			if count < 2 && len(m) > n {
+				continue // a single mention says little about the cluster
+			}
+			terms = append(terms, scored{w, float64(count) / float64(clusterFreq[w])})
+		}
+		sort.Slice(terms, func(i, j int) bool {
+			if terms[i].score != terms[j].score {
+				return terms[i].score > terms[j].score
+			}
+			return terms[i].word < terms[j].word
+		})
+		for i := 0; i < len(terms) && i < n; i++ {
+			out[c] = append(out[c], terms[i].word)
+		}
+		if out[c] == nil {
+			out[c] = []string{}
+		}
+	}
+	return out
+}
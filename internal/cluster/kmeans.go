@@ -0,0 +1,146 @@
+// Package cluster groups seed embeddings into topics (spherical k-means) and labels them with keywords.
+package cluster
+
+import (
+	"math"
+	"math/rand"
+)
+
+// DefaultMaxIterations bounds Lloyd iterations when the caller does not set a limit.
+const DefaultMaxIterations = 25
+
+// MaxK caps the number of clusters.
+const MaxK = 100
+
+// AutoK picks a cluster count for n items (the sqrt(n/2) rule of thumb), between 2 and MaxK.
+func AutoK(n int) int {
+	k := int(math.Round(math.Sqrt(float64(n) / 2)))
+	if k < 2 {
+		k = 2
+	}
+	if k > MaxK {
+		k = MaxK
+	}
+	return k
+}
+
+// KMeans clusters L2-normalised vectors by cosine similarity (spherical k-means with k-means++ seeding).
+// It returns the cluster index of each vector and the unit-length centroids. k is clamped to len(vectors).
+// The result is deterministic for a given seed.
+func KMeans(vectors [][]float32, k, maxIter int, seed int64) (assign []int, centroids [][]float32) {
+	n := len(vectors)
+	if n == 0 || k <= 0 {
+		return nil, nil
+	}
+	if k > n {
+		k = n
+	}
+	if maxIter <= 0 {
+		maxIter = DefaultMaxIterations
+	}
+	rng := rand.New(rand.NewSource(seed))
+	centroids = seedCentroids(vectors, k, rng)
+	assign = make([]int, n)
+	for i := range assign {
+		assign[i] = -1
+	}
+
+	for iter := 0; iter < maxIter; iter++ {
+		changed := 0
+		for i, v := range vectors {
+			best, bestSim := 0, math.Inf(-1)
+			for c, cen := range centroids {
+				if sim := dot(v, cen); sim > bestSim {
+					best, bestSim = c, sim
+				}
+			}
+			if assign[i] != best {
+				assign[i] = best
+				changed++
+			}
+		}
+		if changed == 0 {
+			break
+		}
+
+		dim := len(vectors[0])
+		sums := make([][]float64, k)
+		counts := make([]int, k)
+		for c := range sums {
+			sums[c] = make([]float64, dim)
+		}
+		for i, v := range vectors {
+			c := assign[i]
+			counts[c]++
+			for d, x := range v {
+				sums[c][d] += float64(x)
+			}
+		}
+		for c := range centroids {
+			if counts[c] == 0 {
+				// Re-seed an empty cluster with a random vector so k stays meaningful.
+				centroids[c] = append([]float32(nil), vectors[rng.Intn(n)]...)
+				continue
+			}
+			centroids[c] = normalize(sums[c])
+		}
+	}
+	return assign, centroids
+}
+
+// seedCentroids implements k-means++: each next centroid is drawn with probability proportional
+// to its squared cosine distance from the nearest centroid chosen so far.
+func seedCentroids(vectors [][]float32, k int, rng *rand.Rand) [][]float32 {
+	centroids := make([][]float32, 0, k)
+	centroids = append(centroids, append([]float32(nil), vectors[rng.Intn(len(vectors))]...))
+	dist := make([]float64, len(vectors))
+	for len(centroids) < k {
+		var total float64
+		last := centroids[len(centroids)-1]
+		for i, v := range vectors {
+			d := 1 - dot(v, last)
+			d *= d
+			if len(centroids) == 1 || d < dist[i] {
+				dist[i] = d
+			}
+			total += dist[i]
+		}
+		next := rng.Intn(len(vectors))
+		if total > 0 {
+			r := rng.Float64() * total
+			for i, d := range dist {
+				r -= d
+				if r <= 0 {
+					next = i
+					break
+				}
+			}
+		}
+		centroids = append(centroids, append([]float32(nil), vectors[next]...))
+	}
+	return centroids
+}
+
+func dot(a, b []float32) float64 {
+	var s float64
+	for i := range a {
+		s += float64(a[i]) * float64(b[i])
+	}
+	return s
+}
+
+func normalize(v []float64) []float32 {
+	var norm float64
+	for _, x := range v {
+		norm += x * x
+	}
+	norm = math.Sqrt(norm)
+	out := make([]float32, len(v))
+	if norm == 0 {
+		return out
+	}
+	for i, x := range v {
+		out[i] = float32(x / norm)
+	}
+	return out
+}
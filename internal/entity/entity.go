@@ -0,0 +1,241 @@
+// Package entity extracts named entities (people, organisations, topics, handles) from seed content.
+//
+// The default extractor combines a gazetteer of known entities with a few conservative rules. A local
+// NER model can be plugged in by implementing Extractor.
+package entity
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Entity kinds produced by the built-in extractors. Gazetteer entries may use any kind.
+const (
+	KindPerson       = "person"
+	KindOrganization = "organization"
+	KindPlace        = "place"
+	KindTopic        = "topic"
+	KindContact      = "contact"
+	KindOther        = "other"
+)
+
+// Mention is an entity found in a text: Name is the canonical name, Text the surface form matched.
+type Mention struct {
+	Name string
+	Kind string
+	Text string
+}
+
+// Extractor finds entity mentions in text.
+type Extractor interface {
+	Extract(text string) []Mention
+}
+
+// Learner is implemented by extractors that can be taught new entities at runtime (e.g. Gazetteer).
+type Learner interface {
+	Learn(name, kind string, aliases []string)
+}
+
+// Multi runs several extractors and keeps the first mention of each canonical name (case-insensitive).
+type Multi []Extractor
+
+func (m Multi) Extract(text string) []Mention {
+	var out []Mention
+	seen := map[string]bool{}
+	for _, ex := range m {
+		for _, mn := range ex.Extract(text) {
+			key := strings.ToLower(mn.Name)
+			if !seen[key] {
+				seen[key] = true
+				out = append(out, mn)
+			}
+		}
+	}
+	return out
+}
+
+// Learn forwards to every extractor that is a Learner.
+func (m Multi) Learn(name, kind string, aliases []string) {
+	for _, ex := range m {
+		if l, ok := ex.(Learner); ok {
+			l.Learn(name, kind, aliases)
+		}
+	}
+}
+
+// Gazetteer matches known names and aliases as whole words, case-insensitively. It is safe for concurrent use.
+type Gazetteer struct {
+	mu      sync.RWMutex
+	entries map[string]gazetteerEntry // lowercased surface form -> entity
+}
+
+type gazetteerEntry struct {
+	name, kind string
+}
+
+// NewGazetteer returns an empty gazetteer.
+func NewGazetteer() *Gazetteer {
+	return &Gazetteer{entries: make(map[string]gazetteerEntry)}
+}
+
+// Learn adds an entity under its name and all aliases. Forms shorter than two characters are ignored.
+func (g *Gazetteer) Learn(name, kind string, aliases []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, form := range append([]string{name}, aliases...) {
+		form = strings.ToLower(strings.TrimSpace(form))
+		if utf8.RuneCountInString(form) < 2 {
+			continue
+		}
+		g.entries[form] = gazetteerEntry{name: name, kind: kind}
+	}
+}
+
+// LoadFile adds the entries of a JSON file of the form [{"name": "...", "kind": "person", "aliases": ["..."]}].
+func (g *Gazetteer) LoadFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var entries []struct {
+		Name    string   `json:"name"`
+		Kind    string   `json:"kind"`
+		Aliases []string `json:"aliases"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return 0, err
+	}
+	for _, e := range entries {
+		if e.Kind == "" {
+			e.Kind = KindOther
+		}
+		g.Learn(e.Name, e.Kind, e.Aliases)
+	}
+	return len(entries), nil
+}
+
+// Len returns the number of surface forms known.
+func (g *Gazetteer) Len() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.entries)
+}
+
+func (g *Gazetteer) Extract(text string) []Mention {
+	lower := strings.ToLower(text)
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	var out []Mention
+	for form, e := range g.entries {
+		if i := indexWord(lower, form); i >= 0 {
+			out = append(out, Mention{Name: e.name, Kind: e.kind, Text: text[i : i+len(form)]})
+		}
+	}
+	// Map iteration is random; keep results stable.
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// TenantGazetteers keeps one Gazetteer per tenant for entities taught at runtime, so a name registered by one
+// tenant is never extracted from another tenant's content. The zero value is ready to use.
+type TenantGazetteers struct {
+	mu sync.RWMutex
+	m  map[[2]string]*Gazetteer
+}
+
+// For returns the tenant's gazetteer, creating it if needed.
+func (t *TenantGazetteers) For(appID, externalUserID string) *Gazetteer {
+	key := [2]string{appID, externalUserID}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.m == nil {
+		t.m = make(map[[2]string]*Gazetteer)
+	}
+	g, ok := t.m[key]
+	if !ok {
+		g = NewGazetteer()
+		t.m[key] = g
+	}
+	return g
+}
+
+// Lookup returns the tenant's gazetteer, or nil if nothing was learned for it.
+func (t *TenantGazetteers) Lookup(appID, externalUserID string) *Gazetteer {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.m[[2]string{appID, externalUserID}]
+}
+
+// indexWord returns the byte index of the first occurrence of word in s that is not part of a longer word, or -1.
+func indexWord(s, word string) int {
+	for off := 0; ; {
+		i := strings.Index(s[off:], word)
+		if i < 0 {
+			return -1
+		}
+		i += off
+		before, _ := utf8.DecodeLastRuneInString(s[:i])
+		after, _ := utf8.DecodeRuneInString(s[i+len(word):])
+		if !isWordRune(before) && !isWordRune(after) {
+			return i
+		}
+		off = i + 1
+	}
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+var (
+	reHandle  = regexp.MustCompile(`(?:^|[^\w@])(@[A-Za-z0-9_]{2,30})\b`)
+	reHashtag = regexp.MustCompile(`(?:^|[^\w#])(#[\p{L}0-9_]{2,40})`)
+	reEmail   = regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)
+	// Two to four consecutive capitalised words, e.g. "Carsten Müller", "Neural Brain".
+	reProperName = regexp.MustCompile(`\p{Lu}[\p{Ll}]+(?:[ -]\p{Lu}[\p{Ll}]+){1,3}`)
+)
+
+// leadingCommonWords are capitalised at the start of a sentence (or as German nouns) without being names;
+// they are stripped from the front of a proper-name match.
+var leadingCommonWords = map[string]bool{
+	"der": true, "die": true, "das": true, "ein": true, "eine": true, "ich": true, "wir": true, "heute": true,
+	"gestern": true, "morgen": true, "und": true, "aber": true, "mit": true, "für": true, "von": true, "bei": true,
+	"herr": true, "frau": true, "user": true, "assistant": true,
+	"the": true, "a": true, "an": true, "i": true, "we": true, "today": true, "yesterday": true, "and": true,
+	"but": true, "with": true, "for": true, "from": true, "mr": true, "mrs": true, "ms": true, "dear": true,
+}
+
+// Rules extracts handles (@name), hashtags, e-mail addresses and multi-word capitalised names.
+// Single capitalised words are not extracted: German capitalises every noun.
+type Rules struct{}
+
+func (Rules) Extract(text string) []Mention {
+	var out []Mention
+	for _, m := range reHandle.FindAllStringSubmatch(text, -1) {
+		out = append(out, Mention{Name: m[1], Kind: KindContact, Text: m[1]})
+	}
+	for _, m := range reHashtag.FindAllStringSubmatch(text, -1) {
+		out = append(out, Mention{Name: strings.TrimPrefix(m[1], "#"), Kind: KindTopic, Text: m[1]})
+	}
+	for _, m := range reEmail.FindAllString(text, -1) {
+		out = append(out, Mention{Name: strings.ToLower(m), Kind: KindContact, Text: m})
+	}
+	for _, m := range reProperName.FindAllString(text, -1) {
+		words := strings.Fields(m)
+		for len(words) > 0 && leadingCommonWords[strings.ToLower(words[0])] {
+			words = words[1:]
+		}
+		if len(words) < 2 {
+			continue
+		}
+		name := strings.Join(words, " ")
+		out = append(out, Mention{Name: name, Kind: KindOther, Text: name})
+	}
+	return out
+}
@@ -0,0 +1,196 @@
+// Package events fans out the persisted change log to live subscribers (GET /events).
+//
+// Every server instance runs one Hub. It LISTENs on the Postgres notification channel that the event
+// triggers publish to, reads new rows from the events table and hands them to its local subscribers,
+// so a write on any instance reaches clients connected to all of them. Events are read in store.EventCursor
+// order once their transaction is final; the hub re-checks periodically because an event held back by an
+// older open transaction becomes readable without a new notification.
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+const (
+	subscriberBuffer = 64
+	fetchBatch       = 500
+	maxBackoff       = 30 * time.Second
+	recheckInterval  = time.Second
+)
+
+// Subscription receives events matching its filters. C is closed when the subscriber falls too far
+// behind or the hub stops; clients are expected to reconnect with Last-Event-ID and replay the gap.
+type Subscription struct {
+	C              <-chan store.Event
+	ch             chan store.Event
+	types          []string
+	appID          string
+	externalUserID string
+}
+
+// Hub distributes events recorded in Postgres to the subscribers of this instance.
+type Hub struct {
+	store *store.Store
+	mu    sync.Mutex
+	subs  map[*Subscription]struct{}
+	wake  chan struct{}
+}
+
+// NewHub returns a hub reading from s. Call Run to start it.
+func NewHub(s *store.Store) *Hub {
+	return &Hub{store: s, subs: map[*Subscription]struct{}{}, wake: make(chan struct{}, 1)}
+}
+
+// Subscribe registers a subscriber. Empty filters match everything.
+func (h *Hub) Subscribe(types []string, appID, externalUserID string) *Subscription {
+	ch := make(chan store.Event, subscriberBuffer)
+	sub := &Subscription{C: ch, ch: ch, types: types, appID: appID, externalUserID: externalUserID}
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// Unsubscribe removes the subscriber and closes its channel. It is safe to call more than once.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Run listens for notifications until ctx is cancelled, reconnecting with backoff when the listening
+// connection fails. All subscriptions are closed on return.
+func (h *Hub) Run(ctx context.Context) {
+	defer h.closeAll()
+	var cursor store.EventCursor
+	for backoff := time.Second; ; {
+		c, err := h.store.LatestEventCursor(ctx)
+		if err == nil {
+			cursor = c
+			break
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("events: read cursor: %v", err)
+		if !sleep(ctx, backoff) {
+			return
+		}
+		backoff = nextBackoff(backoff)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		recheck := time.NewTicker(recheckInterval)
+		defer recheck.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.wake:
+			case <-recheck.C:
+			}
+			cursor = h.dispatch(ctx, cursor)
+		}
+	}()
+
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := h.store.ListenEvents(ctx, h.notify)
+		if ctx.Err() != nil {
+			break
+		}
+		if time.Since(started) > maxBackoff {
+			backoff = time.Second
+		}
+		log.Printf("events: listen: %v (retrying in %s)", err, backoff)
+		if !sleep(ctx, backoff) {
+			break
+		}
+		backoff = nextBackoff(backoff)
+	}
+	<-done
+}
+
+// notify wakes the dispatcher; notifications arriving while it is busy collapse into one fetch.
+func (h *Hub) notify() {
+	select {
+	case h.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatch reads all final events after cursor and delivers them, returning the new cursor.
+func (h *Hub) dispatch(ctx context.Context, cursor store.EventCursor) store.EventCursor {
+	for {
+		batch, err := h.store.EventsAfter(ctx, cursor, nil, "", "", fetchBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("events: fetch: %v", err)
+			}
+			return cursor
+		}
+		for _, e := range batch {
+			h.broadcast(e)
+			cursor = e.Cursor()
+		}
+		if len(batch) < fetchBatch {
+			return cursor
+		}
+	}
+}
+
+func (h *Hub) broadcast(e store.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		if !e.Matches(sub.types, sub.appID, sub.externalUserID) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			// Slow consumer: drop it rather than block everyone else. It resumes via Last-Event-ID.
+			delete(h.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+func nextBackoff(d time.Duration) time.Duration {
+	d *= 2
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d
+}
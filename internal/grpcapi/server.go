@@ -0,0 +1,375 @@
+// Package grpcapi implements the NeuralBrain gRPC service (proto/neuralbrain/v1) on top of the store.
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/cabroe/neural-brain/internal/store"
+	pb "github.com/cabroe/neural-brain/proto/neuralbrain/v1"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 100
+)
+
+// memoryTypes mirrors the memoryType values accepted by POST /agent-contexts.
+var memoryTypes = map[string]bool{
+	"episodic": true, "semantic": true, "procedural": true, "working": true,
+}
+
+// Server implements pb.NeuralBrainServer.
+type Server struct {
+	pb.UnimplementedNeuralBrainServer
+	store *store.Store
+	embed func(string) ([]float32, error)
+}
+
+// NewServer returns a gRPC server with the NeuralBrain service registered, backed by s and embed
+// (normally model.Embed).
+func NewServer(s *store.Store, embed func(string) ([]float32, error)) *grpc.Server {
+	g := grpc.NewServer()
+	pb.RegisterNeuralBrainServer(g, &Server{store: s, embed: embed})
+	return g
+}
+
+func tenant(t *pb.Tenant) (string, string) {
+	return t.GetAppId(), t.GetExternalUserId()
+}
+
+func (srv *Server) StoreSeed(ctx context.Context, req *pb.StoreSeedRequest) (*pb.StoreSeedResponse, error) {
+	id, err := srv.storeSeed(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.StoreSeedResponse{Id: id}, nil
+}
+
+func (srv *Server) storeSeed(ctx context.Context, req *pb.StoreSeedRequest) (int64, error) {
+	n, err := srv.newSeed(req)
+	if err != nil {
+		return 0, err
+	}
+	id, err := srv.store.Insert(ctx, n.Content, n.Metadata, n.Embedding, n.AppID, n.ExternalUserID)
+	if err != nil {
+		return 0, status.Error(codes.Internal, err.Error())
+	}
+	return id, nil
+}
+
+// newSeed validates and embeds a store request.
+func (srv *Server) newSeed(req *pb.StoreSeedRequest) (store.NewSeed, error) {
+	if req.GetContent() == "" {
+		return store.NewSeed{}, status.Error(codes.InvalidArgument, "content required")
+	}
+	metadata, err := structJSON(req.GetMetadata())
+	if err != nil {
+		return store.NewSeed{}, err
+	}
+	emb, err := srv.embed(req.GetContent())
+	if err != nil {
+		return store.NewSeed{}, status.Error(codes.Internal, err.Error())
+	}
+	appID, externalUserID := tenant(req.GetTenant())
+	return store.NewSeed{Content: req.GetContent(), Metadata: metadata, Embedding: emb, AppID: appID, ExternalUserID: externalUserID}, nil
+}
+
+// BulkInsert embeds every streamed seed and stores them in one transaction once the stream ends, so a failure
+// leaves nothing behind and the client can simply retry the whole stream.
+func (srv *Server) BulkInsert(stream grpc.ClientStreamingServer[pb.StoreSeedRequest, pb.BulkInsertResponse]) error {
+	var seeds []store.NewSeed
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		n, err := srv.newSeed(req)
+		if err != nil {
+			return err
+		}
+		seeds = append(seeds, n)
+	}
+	ids, err := srv.store.InsertSeeds(stream.Context(), seeds)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return stream.SendAndClose(&pb.BulkInsertResponse{Ids: ids})
+}
+
+func (srv *Server) GetSeed(ctx context.Context, req *pb.GetSeedRequest) (*pb.Seed, error) {
+	se, err := srv.store.GetSeed(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if se == nil {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return seedProto(se)
+}
+
+func (srv *Server) UpdateSeed(ctx context.Context, req *pb.UpdateSeedRequest) (*pb.Seed, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+	if req.GetContent() == "" {
+		return nil, status.Error(codes.InvalidArgument, "content required")
+	}
+	metadata, err := structJSON(req.GetMetadata())
+	if err != nil {
+		return nil, err
+	}
+	emb, err := srv.embed(req.GetContent())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	appID, externalUserID := tenant(req.GetTenant())
+	if _, err := srv.store.UpdateSeed(ctx, req.GetId(), req.GetContent(), metadata, emb, appID, externalUserID, nil); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return srv.GetSeed(ctx, &pb.GetSeedRequest{Id: req.GetId()})
+}
+
+func (srv *Server) SearchSeeds(ctx context.Context, req *pb.SearchSeedsRequest) (*pb.SeedList, error) {
+	seeds, err := srv.search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return seedList(seeds)
+}
+
+func (srv *Server) SearchStream(req *pb.SearchSeedsRequest, stream grpc.ServerStreamingServer[pb.Seed]) error {
+	seeds, err := srv.search(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	for i := range seeds {
+		m, err := seedProto(&seeds[i])
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// search runs a plain similarity search with the optional threshold and records the access.
+func (srv *Server) search(ctx context.Context, req *pb.SearchSeedsRequest) ([]store.Seed, error) {
+	if req.GetQuery() == "" {
+		return nil, status.Error(codes.InvalidArgument, "query required")
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	emb, err := srv.embed(req.GetQuery())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	appID, externalUserID := tenant(req.GetTenant())
+	seeds, err := srv.store.Search(ctx, emb, limit, req.GetSeedIds(), appID, externalUserID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if req.Threshold != nil {
+		filtered := seeds[:0]
+		for _, se := range seeds {
+			if se.Score >= req.GetThreshold() {
+				filtered = append(filtered, se)
+			}
+		}
+		seeds = filtered
+	}
+	ids := make([]int64, len(seeds))
+	for i, se := range seeds {
+		ids[i] = se.ID
+	}
+	if err := srv.store.RecordAccess(ctx, ids); err != nil {
+		log.Printf("grpc: record access: %v", err)
+	}
+	return seeds, nil
+}
+
+func (srv *Server) RecentSeeds(ctx context.Context, req *pb.RecentSeedsRequest) (*pb.SeedList, error) {
+	limit := int(req.GetLimit())
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	appID, externalUserID := tenant(req.GetTenant())
+	seeds, err := srv.store.GetRecent(ctx, limit, appID, externalUserID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return seedList(seeds)
+}
+
+func (srv *Server) CreateContext(ctx context.Context, req *pb.CreateContextRequest) (*pb.AgentContext, error) {
+	agentID := strings.TrimSpace(req.GetAgentId())
+	if agentID == "" {
+		return nil, status.Error(codes.InvalidArgument, "agentId required")
+	}
+	memoryType := strings.ToLower(strings.TrimSpace(req.GetMemoryType()))
+	if !memoryTypes[memoryType] {
+		return nil, status.Error(codes.InvalidArgument, "memoryType must be one of: episodic, semantic, procedural, working")
+	}
+	payload, err := structJSON(req.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+	appID, externalUserID := tenant(req.GetTenant())
+	id, err := srv.store.InsertContext(ctx, agentID, memoryType, payload, appID, externalUserID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return srv.GetContext(ctx, &pb.GetContextRequest{Id: id})
+}
+
+func (srv *Server) GetContext(ctx context.Context, req *pb.GetContextRequest) (*pb.AgentContext, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id required")
+	}
+	c, err := srv.store.GetContext(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if c == nil {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return contextProto(c)
+}
+
+func (srv *Server) ListContexts(ctx context.Context, req *pb.ListContextsRequest) (*pb.ContextList, error) {
+	memoryType := strings.ToLower(strings.TrimSpace(req.GetMemoryType()))
+	if memoryType != "" && !memoryTypes[memoryType] {
+		return nil, status.Error(codes.InvalidArgument, "memoryType must be one of: episodic, semantic, procedural, working")
+	}
+	appID, externalUserID := tenant(req.GetTenant())
+	list, err := srv.store.ListContexts(ctx, strings.TrimSpace(req.GetAgentId()), memoryType, appID, externalUserID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	out := &pb.ContextList{Contexts: make([]*pb.AgentContext, 0, len(list))}
+	for i := range list {
+		c, err := contextProto(&list[i])
+		if err != nil {
+			return nil, err
+		}
+		out.Contexts = append(out.Contexts, c)
+	}
+	return out, nil
+}
+
+func (srv *Server) GetStats(ctx context.Context, _ *pb.GetStatsRequest) (*pb.Stats, error) {
+	seeds, err := srv.store.SeedsCount(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	contexts, err := srv.store.AgentContextsCount(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.Stats{Seeds: seeds, AgentContexts: contexts}, nil
+}
+
+func seedList(seeds []store.Seed) (*pb.SeedList, error) {
+	out := &pb.SeedList{Seeds: make([]*pb.Seed, 0, len(seeds))}
+	for i := range seeds {
+		m, err := seedProto(&seeds[i])
+		if err != nil {
+			return nil, err
+		}
+		out.Seeds = append(out.Seeds, m)
+	}
+	return out, nil
+}
+
+func seedProto(se *store.Seed) (*pb.Seed, error) {
+	metadata, err := jsonStruct(se.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Seed{
+		Id:             se.ID,
+		Content:        se.Content,
+		Metadata:       metadata,
+		AppId:          se.AppID,
+		ExternalUserId: se.ExternalUserID,
+		CreatedAt:      timestamp(se.CreatedAt),
+		AccessCount:    se.AccessCount,
+		LastAccessedAt: timestamp(se.LastAccessedAt),
+		Similarity:     se.Score,
+	}, nil
+}
+
+func contextProto(c *store.AgentContext) (*pb.AgentContext, error) {
+	payload, err := jsonStruct(c.Payload)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AgentContext{
+		Id:             c.ID,
+		AgentId:        c.AgentID,
+		MemoryType:     c.MemoryType,
+		Payload:        payload,
+		AppId:          c.AppID,
+		ExternalUserId: c.ExternalUserID,
+		CreatedAt:      timestamp(c.CreatedAt),
+	}, nil
+}
+
+// structJSON converts an optional Struct into the JSON object stored in Postgres ({} when unset).
+func structJSON(s *structpb.Struct) (json.RawMessage, error) {
+	if s == nil {
+		return json.RawMessage("{}"), nil
+	}
+	b, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid struct: "+err.Error())
+	}
+	return b, nil
+}
+
+// jsonStruct converts a stored JSON object into a Struct; non-object values yield nil.
+func jsonStruct(raw json.RawMessage) (*structpb.Struct, error) {
+	var m map[string]interface{}
+	if len(raw) == 0 || json.Unmarshal(raw, &m) != nil || m == nil {
+		return nil, nil
+	}
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return s, nil
+}
+
+// timestamp parses the RFC 3339 strings the store returns; empty or invalid values yield nil.
+func timestamp(v string) *timestamppb.Timestamp {
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,141 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cabroe/neural-brain/internal/learning"
+	"github.com/cabroe/neural-brain/internal/metrics"
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// Default schedules for the built-in jobs; overridable per job via config.
+var DefaultSchedules = map[string]string{
+	"purge":    "30 3 * * *",
+	"reaper":   "*/15 * * * *",
+	"stats":    "@every 5m",
+	"learning": "*/30 * * * *",
+}
+
+// Purge deletes old metric points, job run history, event log entries, finished webhook deliveries and
+// expired idempotency keys.
+type Purge struct {
+	Store               *store.Store
+	MetricsRetention    time.Duration // default 90 days
+	RunsRetention       time.Duration // default 30 days
+	EventsRetention     time.Duration // default 7 days; bounds how far Last-Event-ID can resume
+	DeliveriesRetention time.Duration // succeeded and dead webhook deliveries; default 30 days
+}
+
+func (Purge) Name() string { return "purge" }
+
+func (j Purge) Run(ctx context.Context) (string, error) {
+	metricsRetention, runsRetention := j.MetricsRetention, j.RunsRetention
+	eventsRetention, deliveriesRetention := j.EventsRetention, j.DeliveriesRetention
+	if metricsRetention <= 0 {
+		metricsRetention = 90 * 24 * time.Hour
+	}
+	if runsRetention <= 0 {
+		runsRetention = 30 * 24 * time.Hour
+	}
+	if eventsRetention <= 0 {
+		eventsRetention = 7 * 24 * time.Hour
+	}
+	if deliveriesRetention <= 0 {
+		deliveriesRetention = 30 * 24 * time.Hour
+	}
+	now := time.Now()
+	points, err := j.Store.PurgeMetricPoints(ctx, now.Add(-metricsRetention))
+	if err != nil {
+		return "", err
+	}
+	runs, err := j.Store.PurgeJobRuns(ctx, now.Add(-runsRetention))
+	if err != nil {
+		return "", err
+	}
+	evts, err := j.Store.PurgeEvents(ctx, now.Add(-eventsRetention))
+	if err != nil {
+		return "", err
+	}
+	deliveries, err := j.Store.PurgeWebhookDeliveries(ctx, now.Add(-deliveriesRetention))
+	if err != nil {
+		return "", err
+	}
+	keys, err := j.Store.PurgeIdempotencyKeys(ctx, now)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("deleted %d metric points, %d job runs, %d events, %d webhook deliveries, %d idempotency keys",
+		points, runs, evts, deliveries, keys), nil
+}
+
+// Reaper fails job runs abandoned by a crashed instance and, if WorkingTTL is set, removes working-memory
+// contexts older than it. Deleting contexts is opt-in: clients may rely on working memory outliving a day.
+type Reaper struct {
+	Store      *store.Store
+	WorkingTTL time.Duration // 0: keep working contexts
+	StaleAfter time.Duration // default 2h; must exceed the longest job
+}
+
+func (Reaper) Name() string { return "reaper" }
+
+func (j Reaper) Run(ctx context.Context) (string, error) {
+	stale := j.StaleAfter
+	if stale <= 0 {
+		stale = 2 * runTimeout
+	}
+	runs, err := j.Store.FailStaleJobRuns(ctx, stale)
+	if err != nil {
+		return "", err
+	}
+	if j.WorkingTTL <= 0 {
+		return fmt.Sprintf("failed %d stale runs", runs), nil
+	}
+	contexts, err := j.Store.DeleteWorkingContexts(ctx, time.Now().Add(-j.WorkingTTL))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("deleted %d working contexts, failed %d stale runs", contexts, runs), nil
+}
+
+// Stats samples seed and agent-context counts per tenant into the metrics time series.
+type Stats struct {
+	Store *store.Store
+}
+
+func (Stats) Name() string { return "stats" }
+
+func (j Stats) Run(ctx context.Context) (string, error) {
+	if err := metrics.SampleCounts(ctx, j.Store); err != nil {
+		return "", err
+	}
+	return "sampled seed and context counts", nil
+}
+
+// Learning evaluates all enabled auto-learning rules.
+type Learning struct {
+	Engine *learning.Engine
+}
+
+func (Learning) Name() string { return "learning" }
+
+func (j Learning) Run(ctx context.Context) (string, error) {
+	results, err := j.Engine.EvaluateAll(ctx, false)
+	if err != nil {
+		return "", err
+	}
+	fired, failed := 0, 0
+	for _, r := range results {
+		if r.Fired {
+			fired++
+		}
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return "", fmt.Errorf("%d of %d rules failed (see /learning/evaluations)", failed, len(results))
+	}
+	return fmt.Sprintf("evaluated %d rules, %d fired", len(results), fired), nil
+}
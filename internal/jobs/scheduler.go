@@ -0,0 +1,227 @@
+// Package jobs runs background maintenance work inside the server on cron schedules.
+//
+// With several server instances against one database, every instance computes the same activation
+// times (cron fields and @every intervals are aligned to the clock, not to the start time), and a
+// scheduled run first claims its (job, activation) row in job_runs, so each activation executes at
+// most once; the other instances skip it. Every run also takes a Postgres advisory lock named after
+// the job, so a manual run never overlaps a scheduled one.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// Job is a unit of background work. Run returns a short human-readable summary for the run history.
+type Job interface {
+	Name() string
+	Run(ctx context.Context) (string, error)
+}
+
+var (
+	ErrUnknownJob = errors.New("unknown job")
+	ErrJobRunning = errors.New("job is already running")
+)
+
+// runTimeout bounds a single run so a stuck job cannot hold its lock forever.
+const runTimeout = time.Hour
+
+// Status describes a registered job for GET /jobs.
+type Status struct {
+	Name      string        `json:"name"`
+	Schedule  string        `json:"schedule"` // empty: manual only
+	NextRunAt string        `json:"nextRunAt,omitempty"`
+	LastRun   *store.JobRun `json:"lastRun,omitempty"`
+}
+
+type entry struct {
+	job      Job
+	spec     string
+	schedule Schedule
+	next     time.Time
+}
+
+// Scheduler holds the registered jobs and fires them on schedule.
+type Scheduler struct {
+	store   *store.Store
+	mu      sync.Mutex
+	entries map[string]*entry
+	wg      sync.WaitGroup
+	base    context.Context // parent of every run; cancelled on shutdown
+	cancel  context.CancelFunc
+}
+
+// NewScheduler returns an empty scheduler.
+func NewScheduler(s *store.Store) *Scheduler {
+	base, cancel := context.WithCancel(context.Background())
+	return &Scheduler{store: s, entries: make(map[string]*entry), base: base, cancel: cancel}
+}
+
+// Register adds a job. An empty spec registers it for manual runs only.
+func (sc *Scheduler) Register(job Job, spec string) error {
+	e := &entry{job: job, spec: spec}
+	if spec != "" {
+		sched, err := ParseSchedule(spec)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", job.Name(), err)
+		}
+		e.schedule = sched
+		e.next = sched.Next(time.Now())
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if _, dup := sc.entries[job.Name()]; dup {
+		return fmt.Errorf("job %s registered twice", job.Name())
+	}
+	sc.entries[job.Name()] = e
+	return nil
+}
+
+// Run fires due jobs until ctx is done, then cancels running jobs and waits for them to finish.
+func (sc *Scheduler) Run(ctx context.Context) {
+	timer := time.NewTimer(sc.untilNext())
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			sc.cancel()
+			sc.wg.Wait()
+			return
+		case <-timer.C:
+		}
+		now := time.Now()
+		sc.mu.Lock()
+		due := make(map[string]time.Time)
+		for name, e := range sc.entries {
+			if e.schedule != nil && !e.next.IsZero() && !e.next.After(now) {
+				due[name] = e.next
+				e.next = e.schedule.Next(now)
+			}
+		}
+		sc.mu.Unlock()
+		for name, slot := range due {
+			_, err := sc.start(ctx, name, store.JobTriggerSchedule, slot)
+			if err != nil && !errors.Is(err, ErrJobRunning) && !errors.Is(err, store.ErrSlotClaimed) {
+				log.Printf("job %s: %v", name, err)
+			}
+		}
+		timer.Reset(sc.untilNext())
+	}
+}
+
+// untilNext returns the wait until the earliest scheduled activation (at most one minute, to pick up clock changes).
+func (sc *Scheduler) untilNext() time.Duration {
+	wait := time.Minute
+	now := time.Now()
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for _, e := range sc.entries {
+		if e.schedule == nil || e.next.IsZero() {
+			continue
+		}
+		if d := e.next.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+// Trigger starts a run of the named job in the background and returns the run id.
+// It returns ErrJobRunning if the job's advisory lock is held, on this or another instance.
+func (sc *Scheduler) Trigger(ctx context.Context, name, trigger string) (int64, error) {
+	return sc.start(ctx, name, trigger, time.Time{})
+}
+
+// start is Trigger for a given scheduled slot; a zero slot is not deduplicated.
+func (sc *Scheduler) start(ctx context.Context, name, trigger string, slot time.Time) (int64, error) {
+	sc.mu.Lock()
+	e, ok := sc.entries[name]
+	sc.mu.Unlock()
+	if !ok {
+		return 0, ErrUnknownJob
+	}
+
+	release, locked, err := sc.store.TryAdvisoryLock(ctx, "job:"+name)
+	if err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, ErrJobRunning
+	}
+	runID, err := sc.store.StartJobRun(ctx, name, trigger, slot)
+	if err != nil {
+		release()
+		return 0, err
+	}
+
+	sc.wg.Add(1)
+	go func() {
+		defer sc.wg.Done()
+		defer release()
+		// Detached from the caller: a manual trigger's request ends long before the job does.
+		runCtx, cancel := context.WithTimeout(sc.base, runTimeout)
+		defer cancel()
+
+		status, errMsg := store.JobSucceeded, ""
+		output, err := runJob(runCtx, e.job)
+		if err != nil {
+			status, errMsg = store.JobFailed, err.Error()
+			log.Printf("job %s failed: %v", name, err)
+		}
+		if err := sc.store.FinishJobRun(context.Background(), runID, status, output, errMsg); err != nil {
+			log.Printf("job %s: record result: %v", name, err)
+		}
+	}()
+	return runID, nil
+}
+
+// runJob converts a panic in a job into an error so it is recorded and the lock is released.
+func runJob(ctx context.Context, job Job) (output string, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// Statuses lists all registered jobs with their schedule and most recent run, sorted by name.
+func (sc *Scheduler) Statuses(ctx context.Context) ([]Status, error) {
+	latest, err := sc.store.LatestJobRuns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sc.mu.Lock()
+	statuses := make([]Status, 0, len(sc.entries))
+	for name, e := range sc.entries {
+		st := Status{Name: name, Schedule: e.spec}
+		if !e.next.IsZero() {
+			st.NextRunAt = e.next.Format(time.RFC3339)
+		}
+		if jr, ok := latest[name]; ok {
+			st.LastRun = &jr
+		}
+		statuses = append(statuses, st)
+	}
+	sc.mu.Unlock()
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses, nil
+}
+
+// Has reports whether a job with this name is registered.
+func (sc *Scheduler) Has(name string) bool {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	_, ok := sc.entries[name]
+	return ok
+}
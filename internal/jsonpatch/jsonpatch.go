@@ -0,0 +1,410 @@
+// Package jsonpatch applies JSON Merge Patch (RFC 7386) and JSON Patch (RFC 6902) documents to JSON values.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Media types selecting the patch format.
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+var (
+	// ErrInvalidPatch is wrapped by errors about a malformed patch document.
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrTestFailed is returned when a JSON Patch "test" operation does not match.
+	ErrTestFailed = errors.New("test operation failed")
+	// ErrCannotApply is wrapped by errors about a well-formed patch that does not fit the document,
+	// e.g. a path that does not exist.
+	ErrCannotApply = errors.New("patch cannot be applied")
+)
+
+// Operation is one JSON Patch operation.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// MergePatch applies an RFC 7386 merge patch to doc: objects merge recursively, null removes a key and
+// any other value replaces the target.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	var target interface{}
+	if len(bytes.TrimSpace(doc)) > 0 {
+		if target, err = decode(doc); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(mergeValue(target, p))
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+	po, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	to, ok := target.(map[string]interface{})
+	if !ok {
+		to = map[string]interface{}{}
+	}
+	for k, v := range po {
+		if v == nil {
+			delete(to, k)
+			continue
+		}
+		to[k] = mergeValue(to[k], v)
+	}
+	return to
+}
+
+// DecodePatch parses and validates an RFC 6902 patch document.
+func DecodePatch(patch []byte) ([]Operation, error) {
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: expected an array of operations: %v", ErrInvalidPatch, err)
+	}
+	for i, op := range ops {
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, fmt.Errorf("%w: operation %d (%s) requires value", ErrInvalidPatch, i, op.Op)
+			}
+		case "move", "copy":
+			if _, err := parsePointer(op.From); err != nil {
+				return nil, fmt.Errorf("%w: operation %d: from: %v", ErrInvalidPatch, i, err)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("%w: operation %d: unknown op %q", ErrInvalidPatch, i, op.Op)
+		}
+		if _, err := parsePointer(op.Path); err != nil {
+			return nil, fmt.Errorf("%w: operation %d: path: %v", ErrInvalidPatch, i, err)
+		}
+	}
+	return ops, nil
+}
+
+// Apply applies JSON Patch operations to doc. Either all operations apply or an error is returned.
+func Apply(doc []byte, ops []Operation) ([]byte, error) {
+	root, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range ops {
+		if root, err = applyOp(root, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(root)
+}
+
+func applyOp(root interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add", "replace", "test":
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: value: %v", ErrInvalidPatch, err)
+		}
+		switch op.Op {
+		case "add":
+			return add(root, path, value)
+		case "replace":
+			if len(path) == 0 {
+				return value, nil
+			}
+			if _, err := get(root, path); err != nil {
+				return nil, err
+			}
+			if root, _, err = remove(root, path); err != nil {
+				return nil, err
+			}
+			return add(root, path, value)
+		default:
+			current, err := get(root, path)
+			if err != nil {
+				return nil, err
+			}
+			if !equal(current, value) {
+				return nil, ErrTestFailed
+			}
+			return root, nil
+		}
+	case "remove":
+		root, _, err = remove(root, path)
+		return root, err
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if op.Op == "move" {
+			if isPrefix(from, path) && len(from) < len(path) {
+				return nil, fmt.Errorf("%w: cannot move a value into itself", ErrCannotApply)
+			}
+			if root, value, err = remove(root, from); err != nil {
+				return nil, err
+			}
+		} else {
+			if value, err = get(root, from); err != nil {
+				return nil, err
+			}
+			value = deepCopy(value)
+		}
+		return add(root, path, value)
+	}
+	return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped reference tokens. "~" must be followed by
+// "0" or "1".
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("pointer %q must start with /", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		if !strings.Contains(t, "~") {
+			continue
+		}
+		var b strings.Builder
+		for j := 0; j < len(t); j++ {
+			if t[j] != '~' {
+				b.WriteByte(t[j])
+				continue
+			}
+			if j+1 == len(t) || (t[j+1] != '0' && t[j+1] != '1') {
+				return nil, fmt.Errorf("pointer %q has an invalid escape in %q", p, t)
+			}
+			j++
+			if t[j] == '0' {
+				b.WriteByte('~')
+			} else {
+				b.WriteByte('/')
+			}
+		}
+		tokens[i] = b.String()
+	}
+	return tokens, nil
+}
+
+func get(node interface{}, path []string) (interface{}, error) {
+	for _, tok := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			v, ok := n[tok]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q does not exist", ErrCannotApply, tok)
+			}
+			node = v
+		case []interface{}:
+			i, err := index(tok, len(n))
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("%w: cannot descend into a scalar at %q", ErrCannotApply, tok)
+		}
+	}
+	return node, nil
+}
+
+// add inserts value at path and returns the (possibly new) root.
+func add(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[last] = value
+		return root, nil
+	case []interface{}:
+		i := len(p)
+		if last != "-" {
+			if i, err = index(last, len(p)+1); err != nil {
+				return nil, err
+			}
+		}
+		grown := append(p, nil)
+		copy(grown[i+1:], grown[i:])
+		grown[i] = value
+		return setAt(root, path[:len(path)-1], grown)
+	}
+	return nil, fmt.Errorf("%w: parent of %q is not a container", ErrCannotApply, last)
+}
+
+// remove deletes the value at path and returns the new root and the removed value.
+func remove(root interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the whole document", ErrCannotApply)
+	}
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	last := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		v, ok := p[last]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: member %q does not exist", ErrCannotApply, last)
+		}
+		delete(p, last)
+		return root, v, nil
+	case []interface{}:
+		i, err := index(last, len(p))
+		if err != nil {
+			return nil, nil, err
+		}
+		v := p[i]
+		shrunk := append(p[:i:i], p[i+1:]...)
+		root, err = setAt(root, path[:len(path)-1], shrunk)
+		return root, v, err
+	}
+	return nil, nil, fmt.Errorf("%w: parent of %q is not a container", ErrCannotApply, last)
+}
+
+// setAt replaces the value at path; arrays change identity when they grow or shrink.
+func setAt(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[last] = value
+	case []interface{}:
+		i, err := index(last, len(p))
+		if err != nil {
+			return nil, err
+		}
+		p[i] = value
+	}
+	return root, nil
+}
+
+// index parses an array index token; it must be below max.
+func index(tok string, max int) (int, error) {
+	if tok == "" || (len(tok) > 1 && tok[0] == '0') || strings.TrimLeft(tok, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrCannotApply, tok)
+	}
+	i, err := strconv.Atoi(tok)
+	if err != nil || i >= max {
+		return 0, fmt.Errorf("%w: array index %q out of range", ErrCannotApply, tok)
+	}
+	return i, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func decode(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("trailing data after JSON value")
+	}
+	return v, nil
+}
+
+// equal compares decoded JSON values; numbers compare by value, not spelling, also inside objects and arrays.
+func equal(a, b interface{}) bool {
+	switch ta := a.(type) {
+	case json.Number:
+		nb, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		fa, errA := ta.Float64()
+		fb, errB := nb.Float64()
+		if errA == nil && errB == nil {
+			return fa == fb
+		}
+		return ta == nb
+	case map[string]interface{}:
+		tb, ok := b.(map[string]interface{})
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for k, v := range ta {
+			w, ok := tb[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		tb, ok := b.([]interface{})
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for i := range ta {
+			if !equal(ta[i], tb[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, x := range t {
+			m[k] = deepCopy(x)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, x := range t {
+			s[i] = deepCopy(x)
+		}
+		return s
+	}
+	return v
+}
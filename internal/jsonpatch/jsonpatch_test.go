@@ -0,0 +1,203 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestApplyRFC6902 runs the examples of RFC 6902 Appendix A, plus the cases the RFC leaves to the pointer
+// syntax of RFC 6901. A.13 (duplicate "op" members) is not listed: encoding/json keeps the last member.
+func TestApplyRFC6902(t *testing.T) {
+	cases := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string // result document; empty if err is set
+		err   error
+	}{
+		{"A.1 add object member",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			`{"baz": "qux", "foo": "bar"}`, nil},
+		{"A.2 add array element",
+			`{"foo": ["bar", "baz"]}`,
+			`[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			`{"foo": ["bar", "qux", "baz"]}`, nil},
+		{"A.3 remove object member",
+			`{"baz": "qux", "foo": "bar"}`,
+			`[{"op": "remove", "path": "/baz"}]`,
+			`{"foo": "bar"}`, nil},
+		{"A.4 remove array element",
+			`{"foo": ["bar", "qux", "baz"]}`,
+			`[{"op": "remove", "path": "/foo/1"}]`,
+			`{"foo": ["bar", "baz"]}`, nil},
+		{"A.5 replace value",
+			`{"baz": "qux", "foo": "bar"}`,
+			`[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			`{"baz": "boo", "foo": "bar"}`, nil},
+		{"A.6 move value",
+			`{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			`[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			`{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`, nil},
+		{"A.7 move array element",
+			`{"foo": ["all", "grass", "cows", "eat"]}`,
+			`[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			`{"foo": ["all", "cows", "eat", "grass"]}`, nil},
+		{"A.8 test value: success",
+			`{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			`[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			`{"baz": "qux", "foo": ["a", 2, "c"]}`, nil},
+		{"A.9 test value: error",
+			`{"baz": "qux"}`,
+			`[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			"", ErrTestFailed},
+		{"A.10 add nested member object",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			`{"foo": "bar", "child": {"grandchild": {}}}`, nil},
+		{"A.11 ignore unrecognized elements",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			`{"foo": "bar", "baz": "qux"}`, nil},
+		{"A.12 add to nonexistent target",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			"", ErrCannotApply},
+		{"A.14 ~ escape ordering",
+			`{"/": 9, "~1": 10}`,
+			`[{"op": "test", "path": "/~01", "value": 10}]`,
+			`{"/": 9, "~1": 10}`, nil},
+		{"A.15 compare strings and numbers",
+			`{"/": 9, "~1": 10}`,
+			`[{"op": "test", "path": "/~01", "value": "10"}]`,
+			"", ErrTestFailed},
+		{"A.16 add array value",
+			`{"foo": ["bar"]}`,
+			`[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			`{"foo": ["bar", ["abc", "def"]]}`, nil},
+
+		{"replace whole document",
+			`{"foo": "bar"}`,
+			`[{"op": "replace", "path": "", "value": {"baz": "qux"}}]`,
+			`{"baz": "qux"}`, nil},
+		{"test whole document",
+			`{"foo": 1}`,
+			`[{"op": "test", "path": "", "value": {"foo": 1.0}}]`,
+			`{"foo": 1}`, nil},
+		{"replace missing member",
+			`{"foo": "bar"}`,
+			`[{"op": "replace", "path": "/baz", "value": 1}]`,
+			"", ErrCannotApply},
+		{"remove whole document",
+			`{"foo": "bar"}`,
+			`[{"op": "remove", "path": ""}]`,
+			"", ErrCannotApply},
+		{"move into own child",
+			`{"foo": {"bar": 1}}`,
+			`[{"op": "move", "from": "/foo", "path": "/foo/bar/baz"}]`,
+			"", ErrCannotApply},
+		{"array index with leading zero",
+			`{"foo": ["a", "b"]}`,
+			`[{"op": "remove", "path": "/foo/01"}]`,
+			"", ErrCannotApply},
+		{"atomic: failed test keeps earlier operations out",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz", "value": 1}, {"op": "test", "path": "/foo", "value": "qux"}]`,
+			"", ErrTestFailed},
+		{"invalid escape ~2",
+			`{"foo": "bar"}`,
+			`[{"op": "test", "path": "/a~2b", "value": 1}]`,
+			"", ErrInvalidPatch},
+		{"trailing ~",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/foo~", "value": 1}]`,
+			"", ErrInvalidPatch},
+		{"invalid escape in from",
+			`{"foo": "bar"}`,
+			`[{"op": "copy", "from": "/foo~", "path": "/baz"}]`,
+			"", ErrInvalidPatch},
+		{"pointer without leading slash",
+			`{"foo": "bar"}`,
+			`[{"op": "remove", "path": "foo"}]`,
+			"", ErrInvalidPatch},
+		{"missing value",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz"}]`,
+			"", ErrInvalidPatch},
+		{"unknown op",
+			`{"foo": "bar"}`,
+			`[{"op": "merge", "path": "/foo", "value": 1}]`,
+			"", ErrInvalidPatch},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := decodeAndApply([]byte(c.doc), []byte(c.patch))
+			if c.err != nil {
+				if !errors.Is(err, c.err) {
+					t.Fatalf("error %v, want %v", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertJSON(t, got, c.want)
+		})
+	}
+}
+
+func decodeAndApply(doc, patch []byte) ([]byte, error) {
+	ops, err := DecodePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	return Apply(doc, ops)
+}
+
+// TestMergePatchRFC7386 runs the examples of RFC 7386 Appendix A.
+func TestMergePatchRFC7386(t *testing.T) {
+	cases := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, c := range cases {
+		t.Run(c.doc+" + "+c.patch, func(t *testing.T) {
+			got, err := MergePatch([]byte(c.doc), []byte(c.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertJSON(t, got, c.want)
+		})
+	}
+}
+
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("result is not JSON: %v\n%s", err, got)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("bad expectation %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
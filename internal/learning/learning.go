@@ -0,0 +1,273 @@
+// Package learning evaluates auto-learning rules against recent seeds and runs their actions.
+package learning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// Rule defaults.
+const (
+	DefaultThreshold   = 0.8
+	DefaultWindowHours = 24.0
+	DefaultMinCount    = 5
+)
+
+// maxCandidates caps the seeds considered per evaluation (the newest win).
+const maxCandidates = 1000
+
+// ExcludedTypes are seed types the engine never matches, so its own output cannot feed back into it.
+var ExcludedTypes = []string{"metrik", "learning", "belief"}
+
+// SeedParams are the action params for learning_seed. {rule} and {count} in Content are substituted.
+type SeedParams struct {
+	Content    string   `json:"content"`
+	Importance *float64 `json:"importance"`
+}
+
+// TagParams are the action params for tag.
+type TagParams struct {
+	Tag string `json:"tag"`
+}
+
+// EmotionParams are the action params for emotion: deltas applied to the agent's state.
+type EmotionParams struct {
+	AgentID string `json:"agentId"`
+	store.VAD
+}
+
+const defaultSeedContent = `Auto-Learning: {count} Treffer für Regel "{rule}". Mehr darauf achten.`
+
+// Normalize applies defaults and validates a rule. It does not compute the prototype embedding.
+func Normalize(r *store.LearningRule) error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return errors.New("name required")
+	}
+	if strings.TrimSpace(r.Pattern) == "" {
+		return errors.New("pattern required")
+	}
+	switch r.Kind {
+	case store.RuleSemantic:
+		if r.Threshold == 0 {
+			r.Threshold = DefaultThreshold
+		}
+		if r.Threshold < 0 || r.Threshold > 1 {
+			return errors.New("threshold must be between 0 and 1")
+		}
+	case store.RuleLexical:
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("invalid regex: %v", err)
+		}
+	default:
+		return errors.New("kind must be semantic or lexical")
+	}
+	if r.WindowHours == 0 {
+		r.WindowHours = DefaultWindowHours
+	}
+	if r.WindowHours < 0 {
+		return errors.New("windowHours must be positive")
+	}
+	if r.MinCount == 0 {
+		r.MinCount = DefaultMinCount
+	}
+	if r.MinCount < 0 {
+		return errors.New("minCount must be positive")
+	}
+	if len(r.ActionParams) == 0 {
+		r.ActionParams = json.RawMessage("{}")
+	}
+	switch r.Action {
+	case store.ActionLearningSeed:
+		var p SeedParams
+		if err := json.Unmarshal(r.ActionParams, &p); err != nil {
+			return errors.New("actionParams: invalid learning_seed params")
+		}
+	case store.ActionTag:
+		var p TagParams
+		if err := json.Unmarshal(r.ActionParams, &p); err != nil || strings.TrimSpace(p.Tag) == "" {
+			return errors.New("actionParams.tag required")
+		}
+	case store.ActionEmotion:
+		var p EmotionParams
+		if err := json.Unmarshal(r.ActionParams, &p); err != nil || strings.TrimSpace(p.AgentID) == "" {
+			return errors.New("actionParams.agentId required")
+		}
+	default:
+		return errors.New("action must be learning_seed, tag or emotion")
+	}
+	return nil
+}
+
+// Result is the outcome of evaluating one rule.
+type Result struct {
+	RuleID         int64   `json:"ruleId"`
+	RuleName       string  `json:"ruleName"`
+	MatchCount     int     `json:"matchCount"`
+	MatchedSeedIDs []int64 `json:"matchedSeedIds"`
+	Fired          bool    `json:"fired"`
+	Result         string  `json:"result,omitempty"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// Engine evaluates rules. Embed is used for the content of learning seeds.
+type Engine struct {
+	Store *store.Store
+	Embed func(text string) ([]float32, error)
+}
+
+// EvaluateAll evaluates every enabled rule. With dryRun, no action runs and nothing is logged.
+func (e *Engine) EvaluateAll(ctx context.Context, dryRun bool) ([]Result, error) {
+	rules, err := e.Store.ListLearningRules(ctx, true, "", "")
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Result, 0, len(rules))
+	for _, r := range rules {
+		results = append(results, e.Evaluate(ctx, r, dryRun))
+	}
+	return results, nil
+}
+
+// Evaluate counts the rule's matches among seeds created within its window (and after it last fired),
+// runs the action when the count reaches MinCount and logs the evaluation.
+func (e *Engine) Evaluate(ctx context.Context, r store.LearningRule, dryRun bool) Result {
+	res := Result{RuleID: r.ID, RuleName: r.Name, MatchedSeedIDs: []int64{}}
+	now := time.Now()
+	since := now.Add(-time.Duration(r.WindowHours * float64(time.Hour)))
+	if r.LastFiredAt != "" {
+		if t, err := time.Parse(time.RFC3339, r.LastFiredAt); err == nil && t.After(since) {
+			since = t
+		}
+	}
+
+	matches, err := e.match(ctx, r, since)
+	if err != nil {
+		res.Error = err.Error()
+	} else {
+		res.MatchedSeedIDs = matches
+		res.MatchCount = len(matches)
+		if res.MatchCount >= r.MinCount {
+			res.Fired = true
+			if dryRun {
+				res.Result = "dry run: action " + r.Action + " not executed"
+			} else if res.Result, err = e.act(ctx, r, matches); err != nil {
+				res.Error = err.Error()
+			} else if err := e.Store.MarkLearningRuleFired(ctx, r.ID, now); err != nil {
+				res.Error = err.Error()
+			}
+		}
+	}
+	if !dryRun {
+		logErr := e.Store.InsertLearningEvaluation(ctx, store.LearningEvaluation{
+			RuleID: r.ID, MatchCount: res.MatchCount, MatchedSeedIDs: res.MatchedSeedIDs, Fired: res.Fired, Result: res.Result, Error: res.Error,
+		})
+		if logErr != nil && res.Error == "" {
+			res.Error = "log evaluation: " + logErr.Error()
+		}
+	}
+	return res
+}
+
+func (e *Engine) match(ctx context.Context, r store.LearningRule, since time.Time) ([]int64, error) {
+	var re *regexp.Regexp
+	switch r.Kind {
+	case store.RuleLexical:
+		var err error
+		if re, err = regexp.Compile(r.Pattern); err != nil {
+			return nil, err
+		}
+	case store.RuleSemantic:
+		if len(r.Embedding) == 0 {
+			return nil, errors.New("semantic rule has no prototype embedding")
+		}
+	}
+	seeds, err := e.Store.RecentDigest(ctx, since, ExcludedTypes, maxCandidates, r.AppID, r.ExternalUserID)
+	if err != nil {
+		return nil, err
+	}
+	ids := []int64{}
+	for _, se := range seeds {
+		var ok bool
+		if re != nil {
+			ok = re.MatchString(se.Content)
+		} else {
+			ok = store.Cosine(r.Embedding, se.Embedding) >= r.Threshold
+		}
+		if ok {
+			ids = append(ids, se.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (e *Engine) act(ctx context.Context, r store.LearningRule, matches []int64) (string, error) {
+	switch r.Action {
+	case store.ActionLearningSeed:
+		var p SeedParams
+		if err := json.Unmarshal(r.ActionParams, &p); err != nil {
+			return "", err
+		}
+		content := p.Content
+		if content == "" {
+			content = defaultSeedContent
+		}
+		content = strings.NewReplacer("{rule}", r.Name, "{count}", strconv.Itoa(len(matches))).Replace(content)
+		meta := map[string]interface{}{
+			"type":          "learning",
+			"ruleId":        r.ID,
+			"rule":          r.Name,
+			"matchCount":    len(matches),
+			"sourceSeedIds": matches,
+			"tags":          []string{"auto-learning"},
+		}
+		if p.Importance != nil {
+			meta["importance"] = *p.Importance
+		}
+		metaBytes, _ := json.Marshal(meta)
+		emb, err := e.Embed(content)
+		if err != nil {
+			return "", err
+		}
+		id, err := e.Store.Insert(ctx, content, metaBytes, emb, r.AppID, r.ExternalUserID)
+		if err != nil {
+			return "", err
+		}
+		if err := e.Store.LinkDerivedFrom(ctx, id, matches); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("created learning seed %d", id), nil
+
+	case store.ActionTag:
+		var p TagParams
+		if err := json.Unmarshal(r.ActionParams, &p); err != nil {
+			return "", err
+		}
+		n, err := e.Store.AddSeedTag(ctx, matches, p.Tag)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("tagged %d seeds with %q", n, p.Tag), nil
+
+	case store.ActionEmotion:
+		var p EmotionParams
+		if err := json.Unmarshal(r.ActionParams, &p); err != nil {
+			return "", err
+		}
+		reason := fmt.Sprintf("learning rule %q: %d matches", r.Name, len(matches))
+		em, err := e.Store.ApplyEmotionEvent(ctx, p.AgentID, r.AppID, r.ExternalUserID, p.VAD, reason)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("emotion of %s now V%.1f A%.1f D%.1f", p.AgentID, em.Valence, em.Arousal, em.Dominance), nil
+	}
+	return "", fmt.Errorf("unknown action %q", r.Action)
+}
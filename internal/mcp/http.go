@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+)
+
+// SessionHeader carries the session ID assigned on initialize.
+const SessionHeader = "Mcp-Session-Id"
+
+const (
+	// sessionIdleTimeout drops HTTP sessions that have not been used for this long.
+	sessionIdleTimeout = 24 * time.Hour
+	// maxSessions bounds the session table; initialize beyond it evicts the least recently used session.
+	maxSessions = 10000
+)
+
+type httpSession struct {
+	Session
+	lastSeen time.Time
+}
+
+// HTTPHandler implements the streamable HTTP transport on a single endpoint. Responses are plain JSON
+// (no server-initiated SSE stream). The tenant is taken from the endpoint URL used for initialize
+// (?appId=...&externalUserId=...&agentId=...) and pinned to the session. Sessions live in this process's
+// memory, so behind several instances a client must stick to the one that initialized it.
+type HTTPHandler struct {
+	srv      *Server
+	mu       sync.Mutex
+	sessions map[string]*httpSession
+}
+
+// NewHTTPHandler returns the HTTP transport for srv.
+func NewHTTPHandler(srv *Server) *HTTPHandler {
+	return &HTTPHandler{srv: srv, sessions: make(map[string]*httpSession)}
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.post(w, r)
+	case http.MethodDelete:
+		h.mu.Lock()
+		_, ok := h.sessions[r.Header.Get(SessionHeader)]
+		delete(h.sessions, r.Header.Get(SessionHeader))
+		h.mu.Unlock()
+		if !ok {
+			apilib.RespondError(w, http.StatusNotFound, "unknown session")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "POST, DELETE")
+		apilib.RespondError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (h *HTTPHandler) post(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageBytes+1))
+	if err != nil || len(body) > maxMessageBytes {
+		apilib.RespondError(w, http.StatusRequestEntityTooLarge, "message too large")
+		return
+	}
+	req, resp := parse(body)
+	if req == nil {
+		apilib.RespondJSON(w, http.StatusBadRequest, resp)
+		return
+	}
+
+	var sess *Session
+	if req.Method == "initialize" {
+		id, s := h.newSession(r)
+		w.Header().Set(SessionHeader, id)
+		sess = s
+	} else {
+		id := r.Header.Get(SessionHeader)
+		if id == "" {
+			apilib.RespondJSON(w, http.StatusBadRequest, errorResponse(req.ID, codeInvalidRequest, "missing "+SessionHeader+" header"))
+			return
+		}
+		var ok bool
+		if sess, ok = h.session(id); !ok {
+			apilib.RespondJSON(w, http.StatusNotFound, errorResponse(req.ID, codeInvalidRequest, "unknown session"))
+			return
+		}
+	}
+
+	resp = h.srv.handle(r.Context(), sess, req)
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	apilib.RespondJSON(w, http.StatusOK, resp)
+}
+
+func (h *HTTPHandler) newSession(r *http.Request) (string, *Session) {
+	b := make([]byte, 16)
+	rand.Read(b)
+	id := hex.EncodeToString(b)
+	q := r.URL.Query()
+	s := &httpSession{
+		Session: Session{
+			AppID:          q.Get("appId"),
+			ExternalUserID: q.Get("externalUserId"),
+			AgentID:        q.Get("agentId"),
+		},
+		lastSeen: time.Now(),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var lru string
+	for sid, old := range h.sessions {
+		if time.Since(old.lastSeen) > sessionIdleTimeout {
+			delete(h.sessions, sid)
+		} else if lru == "" || old.lastSeen.Before(h.sessions[lru].lastSeen) {
+			lru = sid
+		}
+	}
+	if len(h.sessions) >= maxSessions {
+		delete(h.sessions, lru)
+	}
+	h.sessions[id] = s
+	return id, &s.Session
+}
+
+func (h *HTTPHandler) session(id string) (*Session, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	s.lastSeen = time.Now()
+	return &s.Session, true
+}
@@ -0,0 +1,159 @@
+// Package mcp exposes the memory store as a Model Context Protocol server (JSON-RPC 2.0) over stdio
+// and streamable HTTP, so MCP-capable agents can use it without the shell skills.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// ProtocolVersion is the newest MCP revision this server speaks.
+const ProtocolVersion = "2025-06-18"
+
+// supportedVersions are the revisions accepted from clients during initialize.
+var supportedVersions = map[string]bool{
+	"2024-11-05": true,
+	"2025-03-26": true,
+	"2025-06-18": true,
+}
+
+const serverName = "neural-brain"
+
+// JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Session is the per-connection configuration: every tool call is scoped to its tenant.
+type Session struct {
+	AppID          string
+	ExternalUserID string
+	// AgentID is the default owner for context tools; it falls back to AppID.
+	AgentID string
+}
+
+func (s *Session) agentID() string {
+	if s.AgentID != "" {
+		return s.AgentID
+	}
+	return s.AppID
+}
+
+// Server answers MCP requests against a store.
+type Server struct {
+	store *store.Store
+	embed func(string) ([]float32, error)
+}
+
+// NewServer returns a server backed by s that embeds text with embed (normally model.Embed).
+func NewServer(s *store.Store, embed func(string) ([]float32, error)) *Server {
+	return &Server{store: s, embed: embed}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// isNotification reports whether the message expects no response (no id, or a client response to us).
+func (r *request) isNotification() bool {
+	return len(r.ID) == 0 || r.Method == ""
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
+
+// parse decodes one JSON-RPC message; on failure it returns the error response to send.
+func parse(raw []byte) (*request, *response) {
+	var req request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, errorResponse(nil, codeParseError, "parse error")
+	}
+	if req.JSONRPC != "2.0" {
+		return nil, errorResponse(req.ID, codeInvalidRequest, "jsonrpc must be 2.0")
+	}
+	return &req, nil
+}
+
+// handle dispatches a request and returns its response, or nil for notifications.
+func (srv *Server) handle(ctx context.Context, sess *Session, req *request) *response {
+	if req.isNotification() {
+		return nil
+	}
+	var result interface{}
+	var err error
+	switch req.Method {
+	case "initialize":
+		result, err = initializeResult(req.Params)
+	case "ping":
+		result = struct{}{}
+	case "tools/list":
+		result = map[string]interface{}{"tools": toolList}
+	case "tools/call":
+		result, err = srv.callTool(ctx, sess, req.Params)
+	default:
+		return errorResponse(req.ID, codeMethodNotFound, "method not found: "+req.Method)
+	}
+	if err != nil {
+		var pe paramsError
+		if errors.As(err, &pe) {
+			return errorResponse(req.ID, codeInvalidParams, err.Error())
+		}
+		return errorResponse(req.ID, codeInternalError, err.Error())
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// paramsError marks malformed request params (JSON-RPC invalid params).
+type paramsError string
+
+func (e paramsError) Error() string { return string(e) }
+
+func initializeResult(params json.RawMessage) (interface{}, error) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, paramsError("invalid initialize params")
+		}
+	}
+	version := ProtocolVersion
+	if supportedVersions[p.ProtocolVersion] {
+		version = p.ProtocolVersion
+	}
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"tools": map[string]interface{}{"listChanged": false},
+		},
+		"serverInfo": map[string]string{"name": serverName, "version": "1.0.0"},
+		"instructions": "Long-term memory. Use memory_search before answering questions about the user or past work, " +
+			"memory_save for facts worth remembering, and context_create/context_list for agent state.",
+	}, nil
+}
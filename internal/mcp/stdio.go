@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// maxMessageBytes bounds a single JSON-RPC message on stdio and HTTP.
+const maxMessageBytes = 4 << 20
+
+// ServeStdio speaks MCP over newline-delimited JSON on r/w until r is closed or ctx is cancelled.
+// Messages are handled in order; logs must go elsewhere (stderr), never to w.
+func (srv *Server) ServeStdio(ctx context.Context, sess *Session, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageBytes)
+	enc := json.NewEncoder(w)
+
+	lines := make(chan []byte)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				select {
+				case err := <-scanErr:
+					return err
+				default:
+					return nil
+				}
+			}
+			if len(line) == 0 {
+				continue
+			}
+			req, resp := parse(line)
+			if req != nil {
+				resp = srv.handle(ctx, sess, req)
+			}
+			if resp == nil {
+				continue
+			}
+			if err := enc.Encode(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
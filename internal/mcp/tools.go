@@ -0,0 +1,294 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+const (
+	defaultToolLimit = 10
+	maxToolLimit     = 100
+)
+
+// memoryTypes mirrors the memoryType values accepted by POST /agent-contexts.
+var memoryTypes = map[string]bool{
+	"episodic": true, "semantic": true, "procedural": true, "working": true,
+}
+
+type tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+var toolList = []tool{
+	{
+		Name:        "memory_save",
+		Description: "Store a memory (fact, preference, decision). Near-duplicates update the existing memory.",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+			`"content":{"type":"string","description":"The memory text"},` +
+			`"metadata":{"type":"object","description":"Optional metadata, e.g. {\"tags\":[\"work\"],\"importance\":7}"}` +
+			`},"required":["content"]}`),
+	},
+	{
+		Name:        "memory_search",
+		Description: "Semantic search over stored memories, most similar first.",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+			`"query":{"type":"string"},` +
+			`"limit":{"type":"integer","minimum":1,"maximum":100,"default":10},` +
+			`"threshold":{"type":"number","minimum":0,"maximum":1,"description":"Minimum cosine similarity"}` +
+			`},"required":["query"]}`),
+	},
+	{
+		Name:        "memory_recent",
+		Description: "The most recently stored memories, newest first.",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+			`"limit":{"type":"integer","minimum":1,"maximum":100,"default":10}` +
+			`}}`),
+	},
+	{
+		Name:        "context_create",
+		Description: "Store an agent context (episodic, semantic, procedural or working memory payload).",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+			`"agentId":{"type":"string","description":"Defaults to the session agent"},` +
+			`"memoryType":{"type":"string","enum":["episodic","semantic","procedural","working"]},` +
+			`"payload":{"type":"object"}` +
+			`},"required":["memoryType","payload"]}`),
+	},
+	{
+		Name:        "context_list",
+		Description: "List agent contexts, oldest first, optionally filtered by memory type.",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+			`"agentId":{"type":"string","description":"Defaults to the session agent"},` +
+			`"memoryType":{"type":"string","enum":["episodic","semantic","procedural","working"]}` +
+			`}}`),
+	},
+}
+
+// memory is the tool-facing view of a seed.
+type memory struct {
+	ID         int64           `json:"id"`
+	Content    string          `json:"content"`
+	Metadata   json.RawMessage `json:"metadata,omitempty"`
+	Similarity float64         `json:"similarity,omitempty"`
+	CreatedAt  string          `json:"createdAt,omitempty"`
+}
+
+func memories(seeds []store.Seed) []memory {
+	out := make([]memory, len(seeds))
+	for i, se := range seeds {
+		out[i] = memory{ID: se.ID, Content: se.Content, Metadata: se.Metadata, Similarity: se.Score, CreatedAt: se.CreatedAt}
+	}
+	return out
+}
+
+// callTool runs tools/call. Failures inside a tool are reported as an isError result so the model can
+// see them; unknown tools and malformed arguments are protocol errors.
+func (srv *Server) callTool(ctx context.Context, sess *Session, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, paramsError("invalid tools/call params")
+	}
+	args := p.Arguments
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+
+	var out interface{}
+	var err error
+	switch p.Name {
+	case "memory_save":
+		out, err = srv.memorySave(ctx, sess, args)
+	case "memory_search":
+		out, err = srv.memorySearch(ctx, sess, args)
+	case "memory_recent":
+		out, err = srv.memoryRecent(ctx, sess, args)
+	case "context_create":
+		out, err = srv.contextCreate(ctx, sess, args)
+	case "context_list":
+		out, err = srv.contextList(ctx, sess, args)
+	default:
+		return nil, paramsError("unknown tool: " + p.Name)
+	}
+	if err != nil {
+		if _, ok := err.(paramsError); ok {
+			return nil, err
+		}
+		return toolResult(map[string]string{"error": err.Error()}, true), nil
+	}
+	return toolResult(out, false), nil
+}
+
+// toolResult wraps out as text content plus structured content.
+func toolResult(out interface{}, isError bool) map[string]interface{} {
+	text, _ := json.Marshal(out)
+	res := map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": string(text)}},
+		"isError": isError,
+	}
+	if !isError {
+		res["structuredContent"] = out
+	}
+	return res
+}
+
+func decodeArgs(args json.RawMessage, dst interface{}) error {
+	if err := json.Unmarshal(args, dst); err != nil {
+		return paramsError("invalid arguments: " + err.Error())
+	}
+	return nil
+}
+
+func clampLimit(n int) int {
+	if n <= 0 {
+		return defaultToolLimit
+	}
+	if n > maxToolLimit {
+		return maxToolLimit
+	}
+	return n
+}
+
+func (srv *Server) memorySave(ctx context.Context, sess *Session, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		Content  string          `json:"content"`
+		Metadata json.RawMessage `json:"metadata"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	a.Content = strings.TrimSpace(a.Content)
+	if a.Content == "" {
+		return nil, paramsError("content required")
+	}
+	metadata := a.Metadata
+	if len(metadata) == 0 || string(metadata) == "null" {
+		metadata = json.RawMessage(`{"source":"mcp"}`)
+	}
+	emb, err := srv.embed(a.Content)
+	if err != nil {
+		return nil, err
+	}
+	id, err := srv.store.Insert(ctx, a.Content, metadata, emb, sess.AppID, sess.ExternalUserID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"id": id}, nil
+}
+
+func (srv *Server) memorySearch(ctx context.Context, sess *Session, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		Query     string   `json:"query"`
+		Limit     int      `json:"limit"`
+		Threshold *float64 `json:"threshold"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(a.Query) == "" {
+		return nil, paramsError("query required")
+	}
+	emb, err := srv.embed(a.Query)
+	if err != nil {
+		return nil, err
+	}
+	seeds, err := srv.store.Search(ctx, emb, clampLimit(a.Limit), nil, sess.AppID, sess.ExternalUserID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Threshold != nil {
+		filtered := seeds[:0]
+		for _, se := range seeds {
+			if se.Score >= *a.Threshold {
+				filtered = append(filtered, se)
+			}
+		}
+		seeds = filtered
+	}
+	ids := make([]int64, len(seeds))
+	for i, se := range seeds {
+		ids[i] = se.ID
+	}
+	if err := srv.store.RecordAccess(ctx, ids); err != nil {
+		log.Printf("mcp: record access: %v", err)
+	}
+	return map[string]interface{}{"memories": memories(seeds)}, nil
+}
+
+func (srv *Server) memoryRecent(ctx context.Context, sess *Session, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		Limit int `json:"limit"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	seeds, err := srv.store.GetRecent(ctx, clampLimit(a.Limit), sess.AppID, sess.ExternalUserID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"memories": memories(seeds)}, nil
+}
+
+func (srv *Server) contextCreate(ctx context.Context, sess *Session, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		AgentID    string          `json:"agentId"`
+		MemoryType string          `json:"memoryType"`
+		Payload    json.RawMessage `json:"payload"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	agentID := strings.TrimSpace(a.AgentID)
+	if agentID == "" {
+		agentID = sess.agentID()
+	}
+	if agentID == "" {
+		return nil, paramsError("agentId required (no session agent configured)")
+	}
+	memoryType := strings.ToLower(strings.TrimSpace(a.MemoryType))
+	if !memoryTypes[memoryType] {
+		return nil, paramsError("memoryType must be one of: episodic, semantic, procedural, working")
+	}
+	if len(a.Payload) == 0 || a.Payload[0] != '{' {
+		return nil, paramsError("payload must be an object")
+	}
+	id, err := srv.store.InsertContext(ctx, agentID, memoryType, a.Payload, sess.AppID, sess.ExternalUserID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"id": id, "agentId": agentID, "memoryType": memoryType}, nil
+}
+
+func (srv *Server) contextList(ctx context.Context, sess *Session, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		AgentID    string `json:"agentId"`
+		MemoryType string `json:"memoryType"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	agentID := strings.TrimSpace(a.AgentID)
+	if agentID == "" {
+		agentID = sess.agentID()
+	}
+	memoryType := strings.ToLower(strings.TrimSpace(a.MemoryType))
+	if memoryType != "" && !memoryTypes[memoryType] {
+		return nil, paramsError(fmt.Sprintf("unknown memoryType %q", memoryType))
+	}
+	list, err := srv.store.ListContexts(ctx, agentID, memoryType, sess.AppID, sess.ExternalUserID)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []store.AgentContext{}
+	}
+	return map[string]interface{}{"contexts": list}, nil
+}
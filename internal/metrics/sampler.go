@@ -0,0 +1,57 @@
+// Package metrics samples internal counters into the metric_points time series.
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// SampleCounts records seed and agent-context counts for every tenant, plus global totals. A tenant whose
+// rows were all deleted drops out of the GROUP BY; it gets an explicit 0 once, so its series does not stay
+// at the last non-zero count.
+func SampleCounts(ctx context.Context, s *store.Store) error {
+	seeds, err := s.SeedsCountByTenant(ctx)
+	if err != nil {
+		return err
+	}
+	contexts, err := s.AgentContextsCountByTenant(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var points []store.MetricPoint
+	add := func(name string, counts []store.TenantCount, total string) error {
+		previous, err := s.LastSampleTenants(ctx, name)
+		if err != nil {
+			return err
+		}
+		type tenant struct{ appID, externalUserID string }
+		seen := make(map[tenant]bool, len(counts))
+		var sum int64
+		for _, c := range counts {
+			sum += c.Count
+			seen[tenant{c.AppID, c.ExternalUserID}] = true
+			points = append(points, store.MetricPoint{
+				Name: name, Value: float64(c.Count), AppID: c.AppID, ExternalUserID: c.ExternalUserID, RecordedAt: now,
+			})
+		}
+		for _, p := range previous {
+			if !seen[tenant{p.AppID, p.ExternalUserID}] {
+				points = append(points, store.MetricPoint{
+					Name: name, Value: 0, AppID: p.AppID, ExternalUserID: p.ExternalUserID, RecordedAt: now,
+				})
+			}
+		}
+		points = append(points, store.MetricPoint{Name: total, Value: float64(sum), RecordedAt: now})
+		return nil
+	}
+	if err := add(store.MetricSeedsCount, seeds, store.MetricSeedsTotal); err != nil {
+		return err
+	}
+	if err := add(store.MetricAgentContextsCount, contexts, store.MetricAgentContextsTotal); err != nil {
+		return err
+	}
+	return s.InsertMetricPoints(ctx, points)
+}
@@ -0,0 +1,276 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+)
+
+// BeliefRevisionSimilarity is the cosine similarity above which a new insight is compared against
+// an existing belief instead of being stored independently.
+const BeliefRevisionSimilarity = 0.85
+
+// Belief revision outcomes reported by CommitBelief.
+const (
+	BeliefCreated    = "created"    // no similar belief existed
+	BeliefReinforced = "reinforced" // same statement again: confidence and sources merged into the existing belief
+	BeliefSuperseded = "superseded" // contradicts an existing belief with lower or equal confidence, which is retired
+	BeliefContested  = "contested"  // contradicts a more confident belief: both stay active, the old one is weakened
+)
+
+// Belief is an insight derived from other memories.
+type Belief struct {
+	ID             int64   `json:"id"`
+	Content        string  `json:"content"`
+	Confidence     float64 `json:"confidence"`
+	Importance     float64 `json:"importance"`
+	SourceSeedIDs  []int64 `json:"sourceSeedIds"`
+	Status         string  `json:"status"`
+	SupersededBy   *int64  `json:"supersededBy,omitempty"`
+	SeedID         *int64  `json:"seedId,omitempty"`
+	AppID          string  `json:"appId,omitempty"`
+	ExternalUserID string  `json:"externalUserId,omitempty"`
+	CreatedAt      string  `json:"createdAt,omitempty"`
+	UpdatedAt      string  `json:"updatedAt,omitempty"`
+}
+
+// BeliefRevision describes what CommitBelief did.
+type BeliefRevision struct {
+	Action     string  `json:"action"`
+	BeliefID   int64   `json:"beliefId"`
+	RelatedID  int64   `json:"relatedId,omitempty"` // the existing belief that was reinforced, superseded or contested
+	Similarity float64 `json:"similarity,omitempty"`
+}
+
+var negations = map[string]bool{
+	"not": true, "no": true, "never": true, "none": true, "nothing": true, "cannot": true, "without": true,
+	"nicht": true, "kein": true, "keine": true, "keinen": true, "keiner": true, "keinem": true,
+	"nie": true, "niemals": true, "nichts": true, "ohne": true,
+}
+
+// Contradicts is a lexical heuristic for two highly similar statements: they contradict when exactly
+// one of them is negated ("X likes Go" vs "X does not like Go").
+func Contradicts(a, b string) bool {
+	return negated(a) != negated(b)
+}
+
+func negated(s string) bool {
+	n := 0
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(r == '\'' || r == '’' || r >= 'a' && r <= 'z' || r >= 0x80)
+	}) {
+		if negations[w] || strings.HasSuffix(w, "n't") || strings.HasSuffix(w, "n’t") {
+			n++
+		}
+	}
+	return n%2 == 1
+}
+
+// CommitBelief stores an insight as a belief (mirrored as a searchable seed tagged reflection/core-belief)
+// and revises the most similar active belief of the same tenant when it is above BeliefRevisionSimilarity.
+func (s *Store) CommitBelief(ctx context.Context, b Belief, embedding []float32) (*BeliefRevision, error) {
+	vec := pgvector.NewVector(embedding)
+	if b.SourceSeedIDs == nil {
+		b.SourceSeedIDs = []int64{}
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// FOR UPDATE below locks nothing while the tenant has no similar belief yet. The transaction lock
+	// serializes commits per tenant, so two concurrent commits of the same insight cannot both insert.
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`,
+		advisoryKey("belief:"+b.AppID+"\x00"+b.ExternalUserID)); err != nil {
+		return nil, err
+	}
+
+	var old Belief
+	var sim float64
+	err = tx.QueryRow(ctx,
+		`SELECT id, content, confidence, importance, source_seed_ids, seed_id, 1 - (embedding <=> $1)
+		 FROM beliefs WHERE status = 'active' AND COALESCE(app_id, '') = $2 AND COALESCE(external_user_id, '') = $3
+		 ORDER BY embedding <=> $1 LIMIT 1 FOR UPDATE`,
+		vec, b.AppID, b.ExternalUserID,
+	).Scan(&old.ID, &old.Content, &old.Confidence, &old.Importance, &old.SourceSeedIDs, &old.SeedID, &sim)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	similar := err == nil && sim >= BeliefRevisionSimilarity
+
+	if similar && !Contradicts(old.Content, b.Content) {
+		confidence := 1 - (1-old.Confidence)*(1-b.Confidence)
+		_, err = tx.Exec(ctx,
+			`UPDATE beliefs SET confidence = $2, importance = GREATEST(importance, $3),
+			        source_seed_ids = ARRAY(SELECT DISTINCT unnest(source_seed_ids || $4::bigint[])), updated_at = NOW()
+			 WHERE id = $1`,
+			old.ID, confidence, b.Importance, b.SourceSeedIDs,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := syncBeliefSeed(ctx, tx, old.SeedID, map[string]interface{}{"confidence": confidence}); err != nil {
+			return nil, err
+		}
+		return &BeliefRevision{Action: BeliefReinforced, BeliefID: old.ID, RelatedID: old.ID, Similarity: sim}, tx.Commit(ctx)
+	}
+
+	var id int64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO beliefs (content, embedding, confidence, importance, source_seed_ids, app_id, external_user_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		b.Content, vec, b.Confidence, b.Importance, b.SourceSeedIDs, b.AppID, b.ExternalUserID,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"type":          "belief",
+		"source":        "self_reflection",
+		"tags":          []string{"reflection", "core-belief"},
+		"importance":    b.Importance,
+		"confidence":    b.Confidence,
+		"beliefId":      id,
+		"sourceSeedIds": b.SourceSeedIDs,
+	})
+	seedID, err := insertSeed(ctx, tx, b.Content, metadata, vec, b.AppID, b.ExternalUserID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE beliefs SET seed_id = $2 WHERE id = $1`, id, seedID); err != nil {
+		return nil, err
+	}
+	if err := linkDerivedFrom(ctx, tx, seedID, b.SourceSeedIDs); err != nil {
+		return nil, err
+	}
+	if err := s.indexSeed(ctx, tx, seedID, b.Content, vec, b.AppID, b.ExternalUserID); err != nil {
+		return nil, err
+	}
+
+	rev := &BeliefRevision{Action: BeliefCreated, BeliefID: id}
+	if similar {
+		rev.RelatedID, rev.Similarity = old.ID, sim
+		if b.Confidence >= old.Confidence {
+			rev.Action = BeliefSuperseded
+			_, err = tx.Exec(ctx,
+				`UPDATE beliefs SET status = 'superseded', superseded_by = $2, updated_at = NOW() WHERE id = $1`,
+				old.ID, id,
+			)
+			if err == nil {
+				err = syncBeliefSeed(ctx, tx, old.SeedID, map[string]interface{}{"status": "superseded", "supersededBy": id})
+			}
+		} else {
+			rev.Action = BeliefContested
+			weakened := old.Confidence * (1 - b.Confidence)
+			_, err = tx.Exec(ctx, `UPDATE beliefs SET confidence = $2, updated_at = NOW() WHERE id = $1`, old.ID, weakened)
+			if err == nil {
+				err = syncBeliefSeed(ctx, tx, old.SeedID, map[string]interface{}{"confidence": weakened, "contestedBy": id})
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rev, tx.Commit(ctx)
+}
+
+// syncBeliefSeed merges patch into the metadata of a belief's mirror seed, if it still exists.
+func syncBeliefSeed(ctx context.Context, tx pgx.Tx, seedID *int64, patch map[string]interface{}) error {
+	if seedID == nil {
+		return nil
+	}
+	b, _ := json.Marshal(patch)
+	_, err := tx.Exec(ctx, `UPDATE seeds SET metadata = COALESCE(metadata, '{}'::jsonb) || $1 WHERE id = $2`, b, *seedID)
+	return err
+}
+
+// ListBeliefs returns beliefs ordered by confidence, optionally filtered by status.
+func (s *Store) ListBeliefs(ctx context.Context, status, appID, externalUserID string) ([]Belief, error) {
+	baseQuery := `SELECT id, content, confidence, importance, source_seed_ids, status, superseded_by, seed_id,
+				        app_id, external_user_id, created_at, updated_at
+				 FROM beliefs WHERE 1=1`
+	args := []interface{}{}
+	argIdx := 1
+	if status != "" {
+		baseQuery += ` AND status = $` + strconv.Itoa(argIdx)
+		args = append(args, status)
+		argIdx++
+	}
+	if appID != "" {
+		baseQuery += ` AND app_id = $` + strconv.Itoa(argIdx)
+		args = append(args, appID)
+		argIdx++
+	}
+	if externalUserID != "" {
+		baseQuery += ` AND external_user_id = $` + strconv.Itoa(argIdx)
+		args = append(args, externalUserID)
+		argIdx++
+	}
+	baseQuery += ` ORDER BY confidence DESC, created_at DESC`
+
+	rows, err := s.pool.Query(ctx, baseQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []Belief
+	for rows.Next() {
+		var b Belief
+		var appID, externalUserID *string
+		var createdAt, updatedAt time.Time
+		if err := rows.Scan(&b.ID, &b.Content, &b.Confidence, &b.Importance, &b.SourceSeedIDs, &b.Status, &b.SupersededBy,
+			&b.SeedID, &appID, &externalUserID, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		if appID != nil {
+			b.AppID = *appID
+		}
+		if externalUserID != nil {
+			b.ExternalUserID = *externalUserID
+		}
+		b.CreatedAt = createdAt.Format(time.RFC3339)
+		b.UpdatedAt = updatedAt.Format(time.RFC3339)
+		list = append(list, b)
+	}
+	return list, rows.Err()
+}
+
+// RecentDigest returns seeds of exactly this tenant (empty fields match untenanted seeds) created since the
+// given time, newest first, with embeddings loaded and seeds whose metadata.type is in excludeTypes left out
+// (system-generated metrics, learnings, ...).
+func (s *Store) RecentDigest(ctx context.Context, since time.Time, excludeTypes []string, limit int, appID, externalUserID string) ([]Seed, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if excludeTypes == nil {
+		excludeTypes = []string{}
+	}
+	baseQuery := `SELECT ` + seedColumns + `, 0 AS score, embedding FROM seeds
+				 WHERE created_at >= $1 AND NOT (COALESCE(metadata->>'type', '') = ANY($2))
+				   AND COALESCE(app_id, '') = $4 AND COALESCE(external_user_id, '') = $5
+				 ORDER BY created_at DESC LIMIT $3`
+	args := []interface{}{since, excludeTypes, limit, appID, externalUserID}
+
+	rows, err := s.pool.Query(ctx, baseQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var seeds []Seed
+	for rows.Next() {
+		var se Seed
+		var emb pgvector.Vector
+		if err := scanSeed(rows, &se, &emb); err != nil {
+			return nil, err
+		}
+		se.Embedding = emb.Slice()
+		seeds = append(seeds, se)
+	}
+	return seeds, rows.Err()
+}
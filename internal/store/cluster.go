@@ -0,0 +1,182 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+)
+
+// MaxClusterSeeds caps how many seeds (newest first) a clustering run considers.
+const MaxClusterSeeds = 20000
+
+// Cluster is a topic: a centroid with its member count, keywords and the seeds nearest to the centroid.
+type Cluster struct {
+	ID              int64    `json:"id"`
+	Label           int      `json:"label"`
+	Size            int64    `json:"size"`
+	Keywords        []string `json:"keywords"`
+	Representatives []Seed   `json:"representatives"`
+	AppID           string   `json:"appId,omitempty"`
+	ExternalUserID  string   `json:"externalUserId,omitempty"`
+	ComputedAt      string   `json:"computedAt"`
+}
+
+// NewCluster is one cluster of a clustering run, as passed to ReplaceClusters.
+type NewCluster struct {
+	Centroid          []float32
+	Keywords          []string
+	RepresentativeIDs []int64
+	SeedIDs           []int64
+	Similarities      []float64 // cosine similarity of each seed to the centroid
+}
+
+// ClusterCandidates returns a tenant's seeds (exact tenant match; empty means untenanted) with embeddings, newest first.
+func (s *Store) ClusterCandidates(ctx context.Context, appID, externalUserID string) ([]Seed, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+seedColumns+`, 0 AS score, embedding FROM seeds
+		 WHERE COALESCE(app_id, '') = $1 AND COALESCE(external_user_id, '') = $2
+		 ORDER BY created_at DESC LIMIT $3`,
+		appID, externalUserID, MaxClusterSeeds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var seeds []Seed
+	for rows.Next() {
+		var se Seed
+		var emb pgvector.Vector
+		if err := scanSeed(rows, &se, &emb); err != nil {
+			return nil, err
+		}
+		se.Embedding = emb.Slice()
+		seeds = append(seeds, se)
+	}
+	return seeds, rows.Err()
+}
+
+// ReplaceClusters atomically replaces a tenant's clusters and seed assignments with the result of a new run.
+func (s *Store) ReplaceClusters(ctx context.Context, appID, externalUserID string, clusters []NewCluster) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM clusters WHERE app_id = $1 AND external_user_id = $2`, appID, externalUserID); err != nil {
+		return err
+	}
+	for label, c := range clusters {
+		var id int64
+		err := tx.QueryRow(ctx,
+			`INSERT INTO clusters (app_id, external_user_id, label, centroid, keywords, representative_ids)
+			 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			appID, externalUserID, label, pgvector.NewVector(c.Centroid), c.Keywords, c.RepresentativeIDs,
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO seed_clusters (seed_id, cluster_id, similarity)
+			 SELECT u.seed_id, $1, u.sim FROM unnest($2::bigint[], $3::float8[]) AS u(seed_id, sim)
+			 ON CONFLICT (seed_id) DO UPDATE SET cluster_id = EXCLUDED.cluster_id, similarity = EXCLUDED.similarity`,
+			id, c.SeedIDs, c.Similarities,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// assignCluster puts a new or changed seed into the tenant's nearest existing cluster, if the tenant has clusters.
+func assignCluster(ctx context.Context, tx pgx.Tx, seedID int64, vec pgvector.Vector, appID, externalUserID string) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO seed_clusters (seed_id, cluster_id, similarity)
+		 SELECT $1, id, 1 - (centroid <=> $2) FROM clusters
+		 WHERE app_id = $3 AND external_user_id = $4
+		 ORDER BY centroid <=> $2 LIMIT 1
+		 ON CONFLICT (seed_id) DO UPDATE SET cluster_id = EXCLUDED.cluster_id, similarity = EXCLUDED.similarity`,
+		seedID, vec, appID, externalUserID,
+	)
+	return err
+}
+
+// ListClusters returns a tenant's clusters, largest first, with representative seeds.
+func (s *Store) ListClusters(ctx context.Context, appID, externalUserID string) ([]Cluster, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT c.id, c.label, c.keywords, c.representative_ids, c.app_id, c.external_user_id, c.computed_at,
+		        (SELECT COUNT(*) FROM seed_clusters sc WHERE sc.cluster_id = c.id) AS size
+		 FROM clusters c WHERE c.app_id = $1 AND c.external_user_id = $2
+		 ORDER BY size DESC, c.label`,
+		appID, externalUserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var clusters []Cluster
+	var repIDs [][]int64
+	for rows.Next() {
+		var c Cluster
+		var ids []int64
+		var computedAt time.Time
+		if err := rows.Scan(&c.ID, &c.Label, &c.Keywords, &ids, &c.AppID, &c.ExternalUserID, &computedAt, &c.Size); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		c.ComputedAt = computedAt.Format(time.RFC3339)
+		clusters = append(clusters, c)
+		repIDs = append(repIDs, ids)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range clusters {
+		clusters[i].Representatives = []Seed{}
+		for _, id := range repIDs[i] {
+			se, err := s.GetSeed(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if se != nil { // representative may have been deleted since
+				clusters[i].Representatives = append(clusters[i].Representatives, *se)
+			}
+		}
+	}
+	return clusters, nil
+}
+
+// ClusterSeeds returns the seeds assigned to a cluster, most central first.
+func (s *Store) ClusterSeeds(ctx context.Context, clusterID int64, limit int) ([]Seed, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+seedColumns+`, sc.similarity FROM seeds JOIN seed_clusters sc ON sc.seed_id = seeds.id
+		 WHERE sc.cluster_id = $1 ORDER BY sc.similarity DESC LIMIT $2`,
+		clusterID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var seeds []Seed
+	for rows.Next() {
+		var se Seed
+		if err := scanSeed(rows, &se); err != nil {
+			return nil, err
+		}
+		seeds = append(seeds, se)
+	}
+	return seeds, rows.Err()
+}
+
+// ClusterExists reports whether a cluster with this ID exists.
+func (s *Store) ClusterExists(ctx context.Context, id int64) (bool, error) {
+	var exists bool
+	err := s.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM clusters WHERE id = $1)`, id).Scan(&exists)
+	return exists, err
+}
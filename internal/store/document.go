@@ -0,0 +1,225 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+
+	"github.com/cabroe/neural-brain/internal/chunk"
+)
+
+// Document is a long text stored as a parent row; its chunks are regular seeds.
+type Document struct {
+	ID             int64           `json:"id"`
+	Title          string          `json:"title"`
+	MimeType       string          `json:"mimeType"`
+	Metadata       json.RawMessage `json:"metadata"`
+	AppID          string          `json:"appId,omitempty"`
+	ExternalUserID string          `json:"externalUserId,omitempty"`
+	ChunkCount     int             `json:"chunkCount"`
+	CreatedAt      string          `json:"createdAt,omitempty"`
+	Content        string          `json:"content,omitempty"` // only set by GetDocument
+}
+
+// DocumentContext is attached to a chunk seed in search results: the parent title and the text around the chunk.
+type DocumentContext struct {
+	DocumentID int64  `json:"documentId"`
+	Title      string `json:"title"`
+	ChunkIndex int    `json:"chunkIndex"`
+	ChunkCount int    `json:"chunkCount"`
+	Context    string `json:"context"`
+}
+
+// InsertDocument stores a document and one seed per chunk in a single transaction.
+// Chunk seeds bypass semantic dedup: overlapping chunks are expected to be similar.
+// Chunk offsets are stored in characters (not bytes) so they line up with SQL substring().
+func (s *Store) InsertDocument(ctx context.Context, doc Document, chunks []chunk.Chunk, embeddings [][]float32) (int64, []int64, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var docID int64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO documents (title, content, mime_type, metadata, app_id, external_user_id)
+		 VALUES ($1, $2, $3, COALESCE($4::jsonb, '{}'), $5, $6) RETURNING id`,
+		doc.Title, doc.Content, doc.MimeType, doc.Metadata, doc.AppID, doc.ExternalUserID,
+	).Scan(&docID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	offsets := runeOffsets(doc.Content, chunks)
+	seedIDs := make([]int64, 0, len(chunks))
+	for i, c := range chunks {
+		metadata, _ := json.Marshal(map[string]interface{}{
+			"source":     "document",
+			"title":      doc.Title,
+			"documentId": docID,
+			"chunkIndex": c.Index,
+		})
+		vec := pgvector.NewVector(embeddings[i])
+		seedID, err := insertSeed(ctx, tx, c.Text, metadata, vec, doc.AppID, doc.ExternalUserID)
+		if err != nil {
+			return 0, nil, err
+		}
+		_, err = tx.Exec(ctx,
+			`INSERT INTO document_chunks (document_id, chunk_index, seed_id, start_offset, end_offset) VALUES ($1, $2, $3, $4, $5)`,
+			docID, c.Index, seedID, offsets[i][0], offsets[i][1],
+		)
+		if err != nil {
+			return 0, nil, err
+		}
+		if err := s.indexSeed(ctx, tx, seedID, c.Text, vec, doc.AppID, doc.ExternalUserID); err != nil {
+			return 0, nil, err
+		}
+		seedIDs = append(seedIDs, seedID)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, nil, err
+	}
+	return docID, seedIDs, nil
+}
+
+// runeOffsets converts the chunks' byte offsets into character offsets in one pass: chunk starts ascend,
+// so each step only counts the bytes since the previous start plus the chunk itself.
+func runeOffsets(content string, chunks []chunk.Chunk) [][2]int {
+	offsets := make([][2]int, len(chunks))
+	pos, runes := 0, 0
+	for i, c := range chunks {
+		runes += utf8.RuneCountInString(content[pos:c.Start])
+		pos = c.Start
+		offsets[i] = [2]int{runes, runes + utf8.RuneCountInString(content[c.Start:c.End])}
+	}
+	return offsets
+}
+
+// GetDocument returns a document with its full content, or nil if not found.
+func (s *Store) GetDocument(ctx context.Context, id int64) (*Document, error) {
+	var d Document
+	var createdAt time.Time
+	var appID, externalUserID *string
+	err := s.pool.QueryRow(ctx,
+		`SELECT d.id, d.title, d.mime_type, d.metadata, d.app_id, d.external_user_id, d.created_at, d.content,
+		        (SELECT COUNT(*) FROM document_chunks c WHERE c.document_id = d.id)
+		 FROM documents d WHERE d.id = $1`,
+		id,
+	).Scan(&d.ID, &d.Title, &d.MimeType, &d.Metadata, &appID, &externalUserID, &createdAt, &d.Content, &d.ChunkCount)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if appID != nil {
+		d.AppID = *appID
+	}
+	if externalUserID != nil {
+		d.ExternalUserID = *externalUserID
+	}
+	d.CreatedAt = createdAt.Format(time.RFC3339)
+	return &d, nil
+}
+
+// ListDocuments returns documents (without content), newest first.
+func (s *Store) ListDocuments(ctx context.Context, limit int, appID, externalUserID string) ([]Document, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	baseQuery := `SELECT d.id, d.title, d.mime_type, d.metadata, d.app_id, d.external_user_id, d.created_at,
+				        (SELECT COUNT(*) FROM document_chunks c WHERE c.document_id = d.id)
+				 FROM documents d WHERE 1=1`
+	args := []interface{}{limit}
+	argIdx := 2
+	if appID != "" {
+		baseQuery += ` AND d.app_id = $` + strconv.Itoa(argIdx)
+		args = append(args, appID)
+		argIdx++
+	}
+	if externalUserID != "" {
+		baseQuery += ` AND d.external_user_id = $` + strconv.Itoa(argIdx)
+		args = append(args, externalUserID)
+		argIdx++
+	}
+	baseQuery += ` ORDER BY d.created_at DESC LIMIT $1`
+
+	rows, err := s.pool.Query(ctx, baseQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []Document
+	for rows.Next() {
+		var d Document
+		var createdAt time.Time
+		var appID, externalUserID *string
+		if err := rows.Scan(&d.ID, &d.Title, &d.MimeType, &d.Metadata, &appID, &externalUserID, &createdAt, &d.ChunkCount); err != nil {
+			return nil, err
+		}
+		if appID != nil {
+			d.AppID = *appID
+		}
+		if externalUserID != nil {
+			d.ExternalUserID = *externalUserID
+		}
+		d.CreatedAt = createdAt.Format(time.RFC3339)
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
+
+// AttachDocumentContext sets Seed.Document for every chunk seed in seeds: the parent title plus the
+// document text spanning window chunks before and after the hit. Non-chunk seeds are left untouched.
+func (s *Store) AttachDocumentContext(ctx context.Context, seeds []Seed, window int) error {
+	if len(seeds) == 0 {
+		return nil
+	}
+	if window < 0 {
+		window = 0
+	}
+	ids := make([]int64, len(seeds))
+	for i, se := range seeds {
+		ids[i] = se.ID
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT c.seed_id, d.id, d.title, c.chunk_index,
+		        (SELECT COUNT(*) FROM document_chunks n WHERE n.document_id = d.id),
+		        substring(d.content FROM lo.start_offset + 1 FOR hi.end_offset - lo.start_offset)
+		 FROM document_chunks c
+		 JOIN documents d ON d.id = c.document_id
+		 JOIN LATERAL (SELECT start_offset FROM document_chunks WHERE document_id = c.document_id
+		               AND chunk_index >= c.chunk_index - $2 ORDER BY chunk_index LIMIT 1) lo ON true
+		 JOIN LATERAL (SELECT end_offset FROM document_chunks WHERE document_id = c.document_id
+		               AND chunk_index <= c.chunk_index + $2 ORDER BY chunk_index DESC LIMIT 1) hi ON true
+		 WHERE c.seed_id = ANY($1)`,
+		ids, window,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	byID := make(map[int64]*DocumentContext)
+	for rows.Next() {
+		var seedID int64
+		var dc DocumentContext
+		if err := rows.Scan(&seedID, &dc.DocumentID, &dc.Title, &dc.ChunkIndex, &dc.ChunkCount, &dc.Context); err != nil {
+			return err
+		}
+		byID[seedID] = &dc
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for i := range seeds {
+		if dc, ok := byID[seeds[i].ID]; ok {
+			seeds[i].Document = dc
+		}
+	}
+	return nil
+}
@@ -0,0 +1,429 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// duplicateBatch is how many seeds are probed per self-join query.
+	duplicateBatch = 500
+	// duplicateNeighbors is how many nearest neighbours are checked per seed.
+	duplicateNeighbors = 10
+	// duplicateExactLimit is the tenant size up to which seeds are compared exactly instead of over the index.
+	duplicateExactLimit = 20000
+)
+
+// ErrTenantMismatch is returned when seeds to merge belong to different tenants.
+var ErrTenantMismatch = errors.New("seeds belong to different tenants")
+
+// DuplicatePair is two seeds whose similarity reached the threshold.
+type DuplicatePair struct {
+	A          int64   `json:"a"`
+	B          int64   `json:"b"`
+	Similarity float64 `json:"similarity"`
+}
+
+// DuplicateGroup is a connected set of near-duplicate seeds.
+type DuplicateGroup struct {
+	SeedIDs       []int64         `json:"seedIds"`
+	MaxSimilarity float64         `json:"maxSimilarity"`
+	Pairs         []DuplicatePair `json:"pairs"`
+	Seeds         []Seed          `json:"seeds"`
+}
+
+// FindDuplicates compares every seed (optionally of one tenant) with its nearest neighbours of the same tenant,
+// in batches, and returns groups of seeds connected by a similarity >= threshold, largest first. At most
+// maxGroups groups are returned. See duplicateScan for how the neighbours are searched.
+func (s *Store) FindDuplicates(ctx context.Context, threshold float64, appID, externalUserID string, maxGroups int) ([]DuplicateGroup, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	if err := duplicateScan(ctx, tx, appID, externalUserID); err != nil {
+		return nil, err
+	}
+
+	var pairs []DuplicatePair
+	var cursor int64
+	for {
+		batch, err := duplicateBatchIDs(ctx, tx, cursor, appID, externalUserID)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		cursor = batch[len(batch)-1]
+
+		rows, err := tx.Query(ctx,
+			`SELECT a.id, b.id, b.sim FROM seeds a
+			 CROSS JOIN LATERAL (
+				SELECT n.id, 1 - (n.embedding <=> a.embedding) AS sim FROM seeds n
+				WHERE n.id <> a.id
+				  AND COALESCE(n.app_id, '') = COALESCE(a.app_id, '')
+				  AND COALESCE(n.external_user_id, '') = COALESCE(a.external_user_id, '')
+				ORDER BY n.embedding <=> a.embedding LIMIT $2
+			 ) b
+			 WHERE a.id = ANY($1) AND b.sim >= $3`,
+			batch, duplicateNeighbors, threshold,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var p DuplicatePair
+			if err := rows.Scan(&p.A, &p.B, &p.Similarity); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if p.A > p.B {
+				p.A, p.B = p.B, p.A
+			}
+			pairs = append(pairs, p)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	groups := groupPairs(pairs)
+	if maxGroups > 0 && len(groups) > maxGroups {
+		groups = groups[:maxGroups]
+	}
+	for i := range groups {
+		for _, id := range groups[i].SeedIDs {
+			se, err := s.GetSeed(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if se != nil {
+				groups[i].Seeds = append(groups[i].Seeds, *se)
+			}
+		}
+	}
+	return groups, nil
+}
+
+// duplicateScan configures the transaction's neighbour search. The HNSW index returns the nearest seeds of all
+// tenants and the tenant condition only filters them afterwards, so a small tenant's duplicates can fall
+// outside the candidates the index yields. A filtered tenant of at most duplicateExactLimit seeds is therefore
+// compared exactly, without the index. Otherwise pgvector 0.8+ keeps scanning the index until enough rows pass
+// the filter (hnsw.iterative_scan); older versions stay approximate.
+func duplicateScan(ctx context.Context, tx pgx.Tx, appID, externalUserID string) error {
+	if appID != "" || externalUserID != "" {
+		var n int
+		if err := tx.QueryRow(ctx,
+			`SELECT COUNT(*) FROM (SELECT 1 FROM seeds WHERE ($1 = '' OR app_id = $1) AND ($2 = '' OR external_user_id = $2) LIMIT $3) t`,
+			appID, externalUserID, duplicateExactLimit+1,
+		).Scan(&n); err != nil {
+			return err
+		}
+		if n <= duplicateExactLimit {
+			_, err := tx.Exec(ctx, `SET LOCAL enable_indexscan = off`)
+			return err
+		}
+	}
+	var iterative bool
+	if err := tx.QueryRow(ctx,
+		`SELECT COALESCE((SELECT string_to_array(extversion, '.')::int[] >= '{0,8}' FROM pg_extension WHERE extname = 'vector'), false)`,
+	).Scan(&iterative); err != nil {
+		return err
+	}
+	if iterative {
+		_, err := tx.Exec(ctx, `SET LOCAL hnsw.iterative_scan = strict_order`)
+		return err
+	}
+	return nil
+}
+
+// duplicateBatchIDs returns the next seed IDs after cursor, optionally restricted to one tenant.
+func duplicateBatchIDs(ctx context.Context, tx pgx.Tx, cursor int64, appID, externalUserID string) ([]int64, error) {
+	baseQuery := `SELECT id FROM seeds WHERE id > $1`
+	args := []interface{}{cursor}
+	argIdx := 2
+	if appID != "" {
+		baseQuery += ` AND app_id = $` + strconv.Itoa(argIdx)
+		args = append(args, appID)
+		argIdx++
+	}
+	if externalUserID != "" {
+		baseQuery += ` AND external_user_id = $` + strconv.Itoa(argIdx)
+		args = append(args, externalUserID)
+		argIdx++
+	}
+	baseQuery += ` ORDER BY id LIMIT $` + strconv.Itoa(argIdx)
+	args = append(args, duplicateBatch)
+
+	rows, err := tx.Query(ctx, baseQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// groupPairs joins pairs into connected components (union-find), dropping the mirrored duplicates
+// that arise because each seed finds the other.
+func groupPairs(pairs []DuplicatePair) []DuplicateGroup {
+	parent := map[int64]int64{}
+	var find func(int64) int64
+	find = func(x int64) int64 {
+		if p, ok := parent[x]; ok && p != x {
+			parent[x] = find(p)
+			return parent[x]
+		}
+		parent[x] = x
+		return x
+	}
+	seen := map[[2]int64]bool{}
+	var unique []DuplicatePair
+	for _, p := range pairs {
+		key := [2]int64{p.A, p.B}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, p)
+		ra, rb := find(p.A), find(p.B)
+		if ra != rb {
+			parent[rb] = ra
+		}
+	}
+
+	byRoot := map[int64]*DuplicateGroup{}
+	var roots []int64
+	for _, p := range unique {
+		r := find(p.A)
+		g, ok := byRoot[r]
+		if !ok {
+			g = &DuplicateGroup{}
+			byRoot[r] = g
+			roots = append(roots, r)
+		}
+		g.Pairs = append(g.Pairs, p)
+		if p.Similarity > g.MaxSimilarity {
+			g.MaxSimilarity = p.Similarity
+		}
+	}
+	groups := make([]DuplicateGroup, 0, len(roots))
+	for _, r := range roots {
+		g := byRoot[r]
+		ids := map[int64]bool{}
+		for _, p := range g.Pairs {
+			ids[p.A], ids[p.B] = true, true
+		}
+		for id := range ids {
+			g.SeedIDs = append(g.SeedIDs, id)
+		}
+		sort.Slice(g.SeedIDs, func(i, j int) bool { return g.SeedIDs[i] < g.SeedIDs[j] })
+		g.Seeds = []Seed{}
+		groups = append(groups, *g)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].SeedIDs) != len(groups[j].SeedIDs) {
+			return len(groups[i].SeedIDs) > len(groups[j].SeedIDs)
+		}
+		return groups[i].MaxSimilarity > groups[j].MaxSimilarity
+	})
+	return groups
+}
+
+// MergeSeeds folds the other seeds into the survivor and deletes them. The survivor keeps its content and
+// metadata; tags are unioned, missing metadata keys are taken from the others, update_count is summed and
+// the merged IDs are appended to metadata.mergedIds. Access counts are summed, edges and entity links are
+// moved to the survivor and a seed.merged event is recorded. All seeds must exist and belong to the same tenant.
+func (s *Store) MergeSeeds(ctx context.Context, survivorID int64, otherIDs []int64) (*Seed, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	all := append([]int64{survivorID}, otherIDs...)
+	rows, err := tx.Query(ctx,
+		`SELECT id, metadata, COALESCE(app_id, ''), COALESCE(external_user_id, ''), access_count, last_accessed_at
+		 FROM seeds WHERE id = ANY($1) ORDER BY id FOR UPDATE`,
+		all,
+	)
+	if err != nil {
+		return nil, err
+	}
+	type row struct {
+		metadata     json.RawMessage
+		tenant       [2]string
+		accessCount  int64
+		lastAccessed *time.Time
+	}
+	found := map[int64]row{}
+	for rows.Next() {
+		var id int64
+		var r row
+		if err := rows.Scan(&id, &r.metadata, &r.tenant[0], &r.tenant[1], &r.accessCount, &r.lastAccessed); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		found[id] = r
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, id := range all {
+		if _, ok := found[id]; !ok {
+			return nil, ErrSeedNotFound
+		}
+	}
+
+	survivor := found[survivorID]
+	merged := decodeMetadata(survivor.metadata)
+	tags := stringList(merged["tags"])
+	updateCount := number(merged["update_count"])
+	mergedIDs := int64List(merged["mergedIds"])
+	accessCount := survivor.accessCount
+	lastAccessed := survivor.lastAccessed
+	for _, id := range otherIDs {
+		r := found[id]
+		if r.tenant != survivor.tenant {
+			return nil, ErrTenantMismatch
+		}
+		m := decodeMetadata(r.metadata)
+		for k, v := range m {
+			if _, ok := merged[k]; !ok {
+				merged[k] = v
+			}
+		}
+		tags = unionStrings(tags, stringList(m["tags"]))
+		updateCount += number(m["update_count"])
+		mergedIDs = append(mergedIDs, int64List(m["mergedIds"])...)
+		mergedIDs = append(mergedIDs, id)
+		accessCount += r.accessCount
+		if r.lastAccessed != nil && (lastAccessed == nil || r.lastAccessed.After(*lastAccessed)) {
+			lastAccessed = r.lastAccessed
+		}
+	}
+	if len(tags) > 0 {
+		merged["tags"] = tags
+	}
+	if updateCount > 0 {
+		merged["update_count"] = updateCount
+	}
+	merged["mergedIds"] = mergedIDs
+	metadata, _ := json.Marshal(merged)
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE seeds SET metadata = $2, access_count = $3, last_accessed_at = $4 WHERE id = $1`,
+		survivorID, metadata, accessCount, lastAccessed,
+	); err != nil {
+		return nil, err
+	}
+	// Move relations to the survivor; edges that would become self-loops or duplicates are dropped with the seeds.
+	for _, stmt := range []string{
+		`INSERT INTO seed_edges (source_id, target_id, type, weight)
+		 SELECT $1, target_id, type, weight FROM seed_edges WHERE source_id = ANY($2) AND target_id <> $1 AND NOT target_id = ANY($2)
+		 ON CONFLICT (source_id, target_id, type) DO NOTHING`,
+		`INSERT INTO seed_edges (source_id, target_id, type, weight)
+		 SELECT source_id, $1, type, weight FROM seed_edges WHERE target_id = ANY($2) AND source_id <> $1 AND NOT source_id = ANY($2)
+		 ON CONFLICT (source_id, target_id, type) DO NOTHING`,
+		`INSERT INTO seed_entities (seed_id, entity_id, mention)
+		 SELECT $1, entity_id, mention FROM seed_entities WHERE seed_id = ANY($2)
+		 ON CONFLICT DO NOTHING`,
+		`UPDATE beliefs SET seed_id = $1 WHERE seed_id = ANY($2)`,
+		`DELETE FROM seeds WHERE id = ANY($2)`,
+	} {
+		if _, err := tx.Exec(ctx, stmt, survivorID, otherIDs); err != nil {
+			return nil, err
+		}
+	}
+	if err := recordEvent(ctx, tx, EventSeedMerged, survivorID, survivor.tenant[0], survivor.tenant[1],
+		map[string]interface{}{"id": survivorID, "mergedIds": otherIDs}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return s.GetSeed(ctx, survivorID)
+}
+
+func decodeMetadata(raw json.RawMessage) map[string]interface{} {
+	m := map[string]interface{}{}
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &m)
+	}
+	if m == nil { // metadata was JSON null
+		m = map[string]interface{}{}
+	}
+	return m
+}
+
+func stringList(v interface{}) []string {
+	var out []string
+	switch t := v.(type) {
+	case []interface{}:
+		for _, x := range t {
+			if s, ok := x.(string); ok {
+				out = append(out, s)
+			}
+		}
+	case string:
+		out = append(out, t)
+	}
+	return out
+}
+
+func int64List(v interface{}) []int64 {
+	var out []int64
+	if list, ok := v.([]interface{}); ok {
+		for _, x := range list {
+			if f, ok := x.(float64); ok {
+				out = append(out, int64(f))
+			}
+		}
+	}
+	return out
+}
+
+func number(v interface{}) float64 {
+	switch t := v.(type) {
+	case float64:
+		return t
+	case string:
+		f, _ := strconv.ParseFloat(t, 64)
+		return f
+	}
+	return 0
+}
+
+func unionStrings(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	for _, s := range a {
+		seen[s] = true
+	}
+	for _, s := range b {
+		if !seen[s] {
+			seen[s] = true
+			a = append(a, s)
+		}
+	}
+	return a
+}
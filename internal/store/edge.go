@@ -0,0 +1,218 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Edge types between seeds. Edges are directed: source <type> target, e.g. belief derived_from observation.
+const (
+	EdgeDerivedFrom = "derived_from"
+	EdgeContradicts = "contradicts"
+	EdgeSupports    = "supports"
+	EdgeParentOf    = "parent_of"
+	EdgeSameEntity  = "same_entity"
+)
+
+// MaxNeighborDepth bounds graph traversal in Neighbors.
+const MaxNeighborDepth = 3
+
+// ErrSeedNotFound is returned when an edge references a seed that does not exist.
+var ErrSeedNotFound = errors.New("seed not found")
+
+var edgeTypes = map[string]bool{
+	EdgeDerivedFrom: true, EdgeContradicts: true, EdgeSupports: true, EdgeParentOf: true, EdgeSameEntity: true,
+}
+
+// ValidEdgeType reports whether t is a known edge type.
+func ValidEdgeType(t string) bool {
+	return edgeTypes[t]
+}
+
+// Edge is a directed, weighted relation between two seeds.
+type Edge struct {
+	ID        int64   `json:"id"`
+	SourceID  int64   `json:"sourceId"`
+	TargetID  int64   `json:"targetId"`
+	Type      string  `json:"type"`
+	Weight    float64 `json:"weight"`
+	CreatedAt string  `json:"createdAt,omitempty"`
+}
+
+// EdgeRef describes how a seed was reached in a graph traversal.
+type EdgeRef struct {
+	SeedID    int64   `json:"seedId"`    // the seed it was reached from
+	Type      string  `json:"type"`      // edge type
+	Direction string  `json:"direction"` // "out": SeedID -> this seed, "in": this seed -> SeedID
+	Weight    float64 `json:"weight"`
+	Depth     int     `json:"depth"` // hops from the start
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+// edgeInTenant restricts a query over seed_edges e to edges whose endpoints both belong to the tenant in $T1/$T2
+// exactly (empty fields match untenanted seeds).
+const edgeInTenant = `EXISTS (SELECT 1 FROM seeds src, seeds tgt WHERE src.id = e.source_id AND tgt.id = e.target_id
+	AND COALESCE(src.app_id, '') = $T1 AND COALESCE(src.external_user_id, '') = $T2
+	AND COALESCE(tgt.app_id, '') = $T1 AND COALESCE(tgt.external_user_id, '') = $T2)`
+
+func inTenant(firstArg int) string {
+	return strings.NewReplacer("$T1", "$"+strconv.Itoa(firstArg), "$T2", "$"+strconv.Itoa(firstArg+1)).Replace(edgeInTenant)
+}
+
+// InsertEdge creates an edge, or updates the weight if the same typed edge already exists. It returns the edge ID,
+// or ErrSeedNotFound unless both seeds exist and belong to the given tenant.
+func (s *Store) InsertEdge(ctx context.Context, e Edge, appID, externalUserID string) (int64, error) {
+	return insertEdge(ctx, s.pool, e, appID, externalUserID)
+}
+
+func insertEdge(ctx context.Context, q querier, e Edge, appID, externalUserID string) (int64, error) {
+	var id int64
+	err := q.QueryRow(ctx,
+		`INSERT INTO seed_edges (source_id, target_id, type, weight)
+		 SELECT $1, $2, $3, $4 WHERE (SELECT count(*) FROM seeds WHERE id IN ($1, $2)
+		   AND COALESCE(app_id, '') = $5 AND COALESCE(external_user_id, '') = $6) = 2
+		 ON CONFLICT (source_id, target_id, type) DO UPDATE SET weight = EXCLUDED.weight
+		 RETURNING id`,
+		e.SourceID, e.TargetID, e.Type, e.Weight, appID, externalUserID,
+	).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrSeedNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation: a seed was deleted meanwhile
+		return 0, ErrSeedNotFound
+	}
+	return id, err
+}
+
+// LinkDerivedFrom adds derived_from edges from seedID to each existing seed in sourceIDs of the same tenant;
+// missing sources and sources of other tenants are skipped.
+func (s *Store) LinkDerivedFrom(ctx context.Context, seedID int64, sourceIDs []int64) error {
+	return linkDerivedFrom(ctx, s.pool, seedID, sourceIDs)
+}
+
+func linkDerivedFrom(ctx context.Context, q execer, seedID int64, sourceIDs []int64) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+	_, err := q.Exec(ctx,
+		`INSERT INTO seed_edges (source_id, target_id, type)
+		 SELECT $1, src.id, 'derived_from' FROM seeds src JOIN seeds self ON self.id = $1
+		 WHERE src.id = ANY($2) AND src.id <> $1
+		   AND COALESCE(src.app_id, '') = COALESCE(self.app_id, '')
+		   AND COALESCE(src.external_user_id, '') = COALESCE(self.external_user_id, '')
+		 ON CONFLICT (source_id, target_id, type) DO NOTHING`,
+		seedID, sourceIDs,
+	)
+	return err
+}
+
+// DeleteEdges removes edges from sourceID to targetID within the tenant, of the given type or of all types if
+// edgeType is empty.
+func (s *Store) DeleteEdges(ctx context.Context, sourceID, targetID int64, edgeType, appID, externalUserID string) (int64, error) {
+	tag, err := s.pool.Exec(ctx,
+		`DELETE FROM seed_edges e WHERE e.source_id = $1 AND e.target_id = $2 AND ($3 = '' OR e.type = $3) AND `+inTenant(4),
+		sourceID, targetID, edgeType, appID, externalUserID,
+	)
+	return tag.RowsAffected(), err
+}
+
+// ListEdges returns all edges touching a seed, in either direction, whose endpoints both belong to the tenant.
+func (s *Store) ListEdges(ctx context.Context, seedID int64, appID, externalUserID string) ([]Edge, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT e.id, e.source_id, e.target_id, e.type, e.weight, e.created_at FROM seed_edges e
+		 WHERE (e.source_id = $1 OR e.target_id = $1) AND `+inTenant(2)+` ORDER BY e.id`,
+		seedID, appID, externalUserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var edges []Edge
+	for rows.Next() {
+		var e Edge
+		var createdAt time.Time
+		if err := rows.Scan(&e.ID, &e.SourceID, &e.TargetID, &e.Type, &e.Weight, &createdAt); err != nil {
+			return nil, err
+		}
+		e.CreatedAt = createdAt.Format(time.RFC3339)
+		edges = append(edges, e)
+	}
+	return edges, rows.Err()
+}
+
+// Neighbors walks edges in both directions from the start seeds up to depth hops (capped at MaxNeighborDepth)
+// and returns each reachable seed once, at its shortest distance, with Seed.Via describing the edge used.
+// The start seeds themselves are excluded. edgeTypes restricts the walk to those types (nil: all).
+// Only edges whose endpoints both belong to the tenant exactly are followed, so the walk never passes through
+// another tenant's seeds; empty appID/externalUserID match untenanted seeds.
+func (s *Store) Neighbors(ctx context.Context, start []int64, depth int, edgeTypes []string, appID, externalUserID string) ([]Seed, error) {
+	if len(start) == 0 {
+		return nil, nil
+	}
+	if depth < 1 {
+		depth = 1
+	}
+	if depth > MaxNeighborDepth {
+		depth = MaxNeighborDepth
+	}
+	if edgeTypes == nil {
+		edgeTypes = []string{}
+	}
+	query := `WITH RECURSIVE walk(seed_id, depth, via_id, edge_type, direction, weight, path) AS (
+		SELECT CASE WHEN e.source_id = ANY($1) THEN e.target_id ELSE e.source_id END, 1,
+		       CASE WHEN e.source_id = ANY($1) THEN e.source_id ELSE e.target_id END, e.type,
+		       CASE WHEN e.source_id = ANY($1) THEN 'out' ELSE 'in' END, e.weight,
+		       ARRAY[e.source_id, e.target_id]
+		FROM seed_edges e
+		WHERE (e.source_id = ANY($1) OR e.target_id = ANY($1)) AND (cardinality($3::text[]) = 0 OR e.type = ANY($3))
+		  AND ` + inTenant(4) + `
+		UNION ALL
+		SELECT CASE WHEN e.source_id = w.seed_id THEN e.target_id ELSE e.source_id END, w.depth + 1, w.seed_id, e.type,
+		       CASE WHEN e.source_id = w.seed_id THEN 'out' ELSE 'in' END, e.weight,
+		       w.path || CASE WHEN e.source_id = w.seed_id THEN e.target_id ELSE e.source_id END
+		FROM walk w JOIN seed_edges e ON e.source_id = w.seed_id OR e.target_id = w.seed_id
+		WHERE w.depth < $2 AND (cardinality($3::text[]) = 0 OR e.type = ANY($3)) AND ` + inTenant(4) + `
+		  AND NOT (CASE WHEN e.source_id = w.seed_id THEN e.target_id ELSE e.source_id END) = ANY(w.path)
+	)
+	SELECT DISTINCT ON (w.seed_id) ` + seedColumns + `, 0 AS score, w.via_id, w.edge_type, w.direction, w.weight, w.depth
+	FROM walk w JOIN seeds ON seeds.id = w.seed_id
+	WHERE NOT w.seed_id = ANY($1)
+	ORDER BY w.seed_id, w.depth, w.weight DESC`
+
+	rows, err := s.pool.Query(ctx, query, start, depth, edgeTypes, appID, externalUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var seeds []Seed
+	for rows.Next() {
+		var se Seed
+		ref := &EdgeRef{}
+		if err := scanSeed(rows, &se, &ref.SeedID, &ref.Type, &ref.Direction, &ref.Weight, &ref.Depth); err != nil {
+			return nil, err
+		}
+		se.Via = ref
+		seeds = append(seeds, se)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(seeds, func(i, j int) bool {
+		if seeds[i].Via.Depth != seeds[j].Via.Depth {
+			return seeds[i].Via.Depth < seeds[j].Via.Depth
+		}
+		return seeds[i].Via.Weight > seeds[j].Via.Weight
+	})
+	return seeds, nil
+}
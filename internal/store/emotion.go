@@ -0,0 +1,258 @@
+package store
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// VAD bounds and defaults. The emotion skill has always used a 0-10 scale with 5 as neutral.
+const (
+	VADMin                 = 0.0
+	VADMax                 = 10.0
+	DefaultEmotionHalfLife = 6 * time.Hour
+)
+
+// DefaultBaseline is the neutral affect state an agent decays toward unless configured otherwise.
+var DefaultBaseline = VAD{Valence: 5, Arousal: 5, Dominance: 5}
+
+// VAD is a valence/arousal/dominance triple.
+type VAD struct {
+	Valence   float64 `json:"valence"`
+	Arousal   float64 `json:"arousal"`
+	Dominance float64 `json:"dominance"`
+}
+
+// InRange reports whether all components lie within [VADMin, VADMax].
+func (v VAD) InRange() bool {
+	for _, c := range []float64{v.Valence, v.Arousal, v.Dominance} {
+		if c < VADMin || c > VADMax || math.IsNaN(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (v VAD) clamp() VAD {
+	c := func(x float64) float64 { return math.Max(VADMin, math.Min(VADMax, x)) }
+	return VAD{Valence: c(v.Valence), Arousal: c(v.Arousal), Dominance: c(v.Dominance)}
+}
+
+// Emotion is an agent's current affect state, already decayed to the time it was read.
+type Emotion struct {
+	AgentID            string  `json:"agentId"`
+	VAD                        // valence, arousal, dominance
+	Baseline           VAD     `json:"baseline"`
+	DecayHalfLifeHours float64 `json:"decayHalfLifeHours"`
+	Reason             string  `json:"reason"`
+	UpdatedAt          string  `json:"updatedAt,omitempty"` // last explicit change; empty for the default state
+}
+
+// EmotionEvent is one entry of an agent's affect history: the change applied and the resulting state.
+type EmotionEvent struct {
+	ID        int64  `json:"id"`
+	Kind      string `json:"kind"` // set or event
+	Delta     VAD    `json:"delta"`
+	State     VAD    `json:"state"`
+	Reason    string `json:"reason"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// EmotionSettings optionally changes an agent's decay baseline and half-life.
+type EmotionSettings struct {
+	Baseline *VAD
+	HalfLife *time.Duration
+}
+
+// DecayVAD moves state exponentially toward baseline: halfway after one half-life.
+func DecayVAD(state, baseline VAD, elapsed, halfLife time.Duration) VAD {
+	if elapsed <= 0 || halfLife <= 0 {
+		return state
+	}
+	f := math.Pow(0.5, float64(elapsed)/float64(halfLife))
+	return VAD{
+		Valence:   baseline.Valence + (state.Valence-baseline.Valence)*f,
+		Arousal:   baseline.Arousal + (state.Arousal-baseline.Arousal)*f,
+		Dominance: baseline.Dominance + (state.Dominance-baseline.Dominance)*f,
+	}
+}
+
+type emotionRow struct {
+	state     VAD
+	baseline  VAD
+	halfLife  time.Duration
+	reason    string
+	updatedAt time.Time
+	found     bool
+}
+
+func (r emotionRow) decayed(now time.Time) VAD {
+	if !r.found {
+		return r.baseline
+	}
+	return DecayVAD(r.state, r.baseline, now.Sub(r.updatedAt), r.halfLife)
+}
+
+func (r emotionRow) emotion(agentID string, now time.Time) *Emotion {
+	e := &Emotion{
+		AgentID:            agentID,
+		VAD:                r.decayed(now),
+		Baseline:           r.baseline,
+		DecayHalfLifeHours: r.halfLife.Hours(),
+		Reason:             r.reason,
+	}
+	if r.found {
+		e.UpdatedAt = r.updatedAt.Format(time.RFC3339)
+	} else {
+		e.Reason = "baseline"
+	}
+	return e
+}
+
+func (s *Store) loadEmotion(ctx context.Context, q pgx.Tx, agentID, appID, externalUserID string, forUpdate bool) (emotionRow, error) {
+	sql := `SELECT valence, arousal, dominance, baseline_valence, baseline_arousal, baseline_dominance,
+	               half_life_seconds, reason, updated_at
+	        FROM agent_emotions WHERE agent_id = $1 AND app_id = $2 AND external_user_id = $3`
+	if forUpdate {
+		sql += ` FOR UPDATE`
+	}
+	var row pgx.Row
+	if q != nil {
+		row = q.QueryRow(ctx, sql, agentID, appID, externalUserID)
+	} else {
+		row = s.pool.QueryRow(ctx, sql, agentID, appID, externalUserID)
+	}
+	r := emotionRow{baseline: DefaultBaseline, halfLife: DefaultEmotionHalfLife}
+	var halfLifeSeconds float64
+	err := row.Scan(&r.state.Valence, &r.state.Arousal, &r.state.Dominance,
+		&r.baseline.Valence, &r.baseline.Arousal, &r.baseline.Dominance,
+		&halfLifeSeconds, &r.reason, &r.updatedAt)
+	if err == pgx.ErrNoRows {
+		return r, nil
+	}
+	if err != nil {
+		return r, err
+	}
+	r.found = true
+	r.halfLife = time.Duration(halfLifeSeconds * float64(time.Second))
+	return r, nil
+}
+
+// GetEmotion returns the agent's current affect state with decay applied; the baseline if nothing was recorded yet.
+func (s *Store) GetEmotion(ctx context.Context, agentID, appID, externalUserID string) (*Emotion, error) {
+	r, err := s.loadEmotion(ctx, nil, agentID, appID, externalUserID, false)
+	if err != nil {
+		return nil, err
+	}
+	return r.emotion(agentID, time.Now()), nil
+}
+
+// SetEmotion sets an absolute state (callers validate the range) and optionally the decay settings.
+func (s *Store) SetEmotion(ctx context.Context, agentID, appID, externalUserID string, state VAD, settings EmotionSettings, reason string) (*Emotion, error) {
+	return s.changeEmotion(ctx, agentID, appID, externalUserID, "set", reason, settings, func(VAD) VAD { return state })
+}
+
+// ApplyEmotionEvent adds delta to the decayed current state, clamping each component to [VADMin, VADMax].
+func (s *Store) ApplyEmotionEvent(ctx context.Context, agentID, appID, externalUserID string, delta VAD, reason string) (*Emotion, error) {
+	return s.changeEmotion(ctx, agentID, appID, externalUserID, "event", reason, EmotionSettings{}, func(cur VAD) VAD {
+		return VAD{
+			Valence:   cur.Valence + delta.Valence,
+			Arousal:   cur.Arousal + delta.Arousal,
+			Dominance: cur.Dominance + delta.Dominance,
+		}.clamp()
+	})
+}
+
+func (s *Store) changeEmotion(ctx context.Context, agentID, appID, externalUserID, kind, reason string, settings EmotionSettings, apply func(VAD) VAD) (*Emotion, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// FOR UPDATE in loadEmotion locks nothing before the agent's first change. The transaction lock serializes
+	// concurrent first events too, so neither starts from the baseline and loses the other's delta.
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`,
+		advisoryKey("emotion:"+agentID+"\x00"+appID+"\x00"+externalUserID)); err != nil {
+		return nil, err
+	}
+	r, err := s.loadEmotion(ctx, tx, agentID, appID, externalUserID, true)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	current := r.decayed(now)
+	next := apply(current)
+	if settings.Baseline != nil {
+		r.baseline = *settings.Baseline
+	}
+	if settings.HalfLife != nil {
+		r.halfLife = *settings.HalfLife
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO agent_emotions (agent_id, app_id, external_user_id, valence, arousal, dominance,
+		                             baseline_valence, baseline_arousal, baseline_dominance, half_life_seconds, reason, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		 ON CONFLICT (agent_id, app_id, external_user_id) DO UPDATE SET
+		   valence = EXCLUDED.valence, arousal = EXCLUDED.arousal, dominance = EXCLUDED.dominance,
+		   baseline_valence = EXCLUDED.baseline_valence, baseline_arousal = EXCLUDED.baseline_arousal,
+		   baseline_dominance = EXCLUDED.baseline_dominance, half_life_seconds = EXCLUDED.half_life_seconds,
+		   reason = EXCLUDED.reason, updated_at = EXCLUDED.updated_at`,
+		agentID, appID, externalUserID, next.Valence, next.Arousal, next.Dominance,
+		r.baseline.Valence, r.baseline.Arousal, r.baseline.Dominance, r.halfLife.Seconds(), reason, now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO agent_emotion_events (agent_id, app_id, external_user_id, kind,
+		                                   delta_valence, delta_arousal, delta_dominance, valence, arousal, dominance, reason, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		agentID, appID, externalUserID, kind,
+		next.Valence-current.Valence, next.Arousal-current.Arousal, next.Dominance-current.Dominance,
+		next.Valence, next.Arousal, next.Dominance, reason, now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	r.state, r.reason, r.updatedAt, r.found = next, reason, now, true
+	return r.emotion(agentID, now), nil
+}
+
+// EmotionHistory returns an agent's affect events in [from, to), oldest first, at most limit rows.
+func (s *Store) EmotionHistory(ctx context.Context, agentID, appID, externalUserID string, from, to time.Time, limit int) ([]EmotionEvent, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, kind, delta_valence, delta_arousal, delta_dominance, valence, arousal, dominance, reason, created_at
+		 FROM (
+		   SELECT * FROM agent_emotion_events
+		   WHERE agent_id = $1 AND app_id = $2 AND external_user_id = $3 AND created_at >= $4 AND created_at < $5
+		   ORDER BY created_at DESC LIMIT $6
+		 ) recent ORDER BY created_at`,
+		agentID, appID, externalUserID, from, to, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var events []EmotionEvent
+	for rows.Next() {
+		var e EmotionEvent
+		var createdAt time.Time
+		if err := rows.Scan(&e.ID, &e.Kind, &e.Delta.Valence, &e.Delta.Arousal, &e.Delta.Dominance,
+			&e.State.Valence, &e.State.Arousal, &e.State.Dominance, &e.Reason, &createdAt); err != nil {
+			return nil, err
+		}
+		e.CreatedAt = createdAt.Format(time.RFC3339)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,261 @@
+package store
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/cabroe/neural-brain/internal/entity"
+)
+
+// Entity is a named thing (person, organisation, topic, ...) mentioned by seeds.
+type Entity struct {
+	ID             int64    `json:"id"`
+	Name           string   `json:"name"`
+	Kind           string   `json:"kind"`
+	Aliases        []string `json:"aliases"`
+	AppID          string   `json:"appId,omitempty"`
+	ExternalUserID string   `json:"externalUserId,omitempty"`
+	SeedCount      int64    `json:"seedCount"`
+	CreatedAt      string   `json:"createdAt"`
+}
+
+const entityColumns = `e.id, e.name, e.kind, e.aliases, e.app_id, e.external_user_id, e.created_at,
+	(SELECT COUNT(*) FROM seed_entities se WHERE se.entity_id = e.id)`
+
+func scanEntity(row pgx.Row) (Entity, error) {
+	var en Entity
+	var createdAt time.Time
+	err := row.Scan(&en.ID, &en.Name, &en.Kind, &en.Aliases, &en.AppID, &en.ExternalUserID, &createdAt, &en.SeedCount)
+	en.CreatedAt = createdAt.Format(time.RFC3339)
+	return en, err
+}
+
+// SetEntityExtractor enables entity extraction on seed insert and update. nil disables it.
+func (s *Store) SetEntityExtractor(ex entity.Extractor) {
+	s.extractor = ex
+}
+
+// upsertEntity returns the ID of the tenant's entity with this name, creating it if needed and merging aliases.
+// A more specific kind replaces "other". manual marks entities created through the API; once set it stays.
+func upsertEntity(ctx context.Context, q querier, name, kind string, aliases []string, manual bool, appID, externalUserID string) (int64, error) {
+	if kind == "" {
+		kind = entity.KindOther
+	}
+	if aliases == nil {
+		aliases = []string{}
+	}
+	var id int64
+	err := q.QueryRow(ctx,
+		`INSERT INTO entities (name, kind, aliases, manual, app_id, external_user_id) VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT ((lower(name)), app_id, external_user_id) DO UPDATE SET
+		   kind = CASE WHEN entities.kind = 'other' THEN EXCLUDED.kind ELSE entities.kind END,
+		   aliases = ARRAY(SELECT DISTINCT a FROM unnest(entities.aliases || EXCLUDED.aliases) a),
+		   manual = entities.manual OR EXCLUDED.manual
+		 RETURNING id`,
+		name, kind, aliases, manual, appID, externalUserID,
+	).Scan(&id)
+	return id, err
+}
+
+// linkEntities extracts entities from content, using the extractor plus the names the tenant registered, and
+// links them to the seed. It is a no-op without an extractor.
+func (s *Store) linkEntities(ctx context.Context, tx pgx.Tx, seedID int64, content, appID, externalUserID string) error {
+	if s.extractor == nil {
+		return nil
+	}
+	ex := s.extractor
+	if g := s.learned.Lookup(appID, externalUserID); g != nil {
+		ex = entity.Multi{g, s.extractor}
+	}
+	for _, m := range ex.Extract(content) {
+		entityID, err := upsertEntity(ctx, tx, m.Name, m.Kind, nil, false, appID, externalUserID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO seed_entities (seed_id, entity_id, mention) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+			seedID, entityID, m.Text,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateEntity creates (or merges aliases into) an entity, links every existing seed of the tenant that mentions
+// its name or an alias as a whole word, and teaches the names to the tenant's gazetteer for later inserts.
+func (s *Store) CreateEntity(ctx context.Context, name, kind string, aliases []string, appID, externalUserID string) (*Entity, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	id, err := upsertEntity(ctx, tx, name, kind, aliases, true, appID, externalUserID)
+	if err != nil {
+		return nil, err
+	}
+	forms := make([]string, 0, len(aliases)+1)
+	for _, f := range append([]string{name}, aliases...) {
+		if f = strings.TrimSpace(f); f != "" {
+			forms = append(forms, regexp.QuoteMeta(f))
+		}
+	}
+	// \m and \M are word boundaries in Postgres regular expressions; ~* is case-insensitive.
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO seed_entities (seed_id, entity_id)
+		 SELECT id, $1 FROM seeds
+		 WHERE COALESCE(app_id, '') = $2 AND COALESCE(external_user_id, '') = $3 AND content ~* $4
+		 ON CONFLICT DO NOTHING`,
+		id, appID, externalUserID, `\m(`+strings.Join(forms, "|")+`)\M`,
+	); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.learned.For(appID, externalUserID).Learn(name, kind, aliases)
+	return s.GetEntity(ctx, id)
+}
+
+// GetEntity returns a single entity, or nil if not found.
+func (s *Store) GetEntity(ctx context.Context, id int64) (*Entity, error) {
+	en, err := scanEntity(s.pool.QueryRow(ctx, `SELECT `+entityColumns+` FROM entities e WHERE e.id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &en, nil
+}
+
+// ListEntities returns entities ordered by number of linked seeds. query matches a prefix of the name or an alias.
+func (s *Store) ListEntities(ctx context.Context, query, kind string, limit int, appID, externalUserID string) ([]Entity, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	baseQuery := `SELECT ` + entityColumns + ` FROM entities e WHERE 1=1`
+	args := []interface{}{limit}
+	argIdx := 2
+	if query != "" {
+		baseQuery += ` AND (e.name ILIKE $` + strconv.Itoa(argIdx) + ` OR EXISTS (SELECT 1 FROM unnest(e.aliases) a WHERE a ILIKE $` + strconv.Itoa(argIdx) + `))`
+		args = append(args, escapeLike(query)+"%")
+		argIdx++
+	}
+	if kind != "" {
+		baseQuery += ` AND e.kind = $` + strconv.Itoa(argIdx)
+		args = append(args, kind)
+		argIdx++
+	}
+	if appID != "" {
+		baseQuery += ` AND e.app_id = $` + strconv.Itoa(argIdx)
+		args = append(args, appID)
+		argIdx++
+	}
+	if externalUserID != "" {
+		baseQuery += ` AND e.external_user_id = $` + strconv.Itoa(argIdx)
+		args = append(args, externalUserID)
+		argIdx++
+	}
+	baseQuery += ` ORDER BY 8 DESC, e.name LIMIT $1`
+
+	rows, err := s.pool.Query(ctx, baseQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []Entity
+	for rows.Next() {
+		en, err := scanEntity(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, en)
+	}
+	return list, rows.Err()
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// EntitySeeds returns the seeds linked to an entity, newest first.
+func (s *Store) EntitySeeds(ctx context.Context, entityID int64, limit int) ([]Seed, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+seedColumns+`, 0 AS score FROM seeds
+		 WHERE id IN (SELECT seed_id FROM seed_entities WHERE entity_id = $1)
+		 ORDER BY created_at DESC LIMIT $2`,
+		entityID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var seeds []Seed
+	for rows.Next() {
+		var se Seed
+		if err := scanSeed(rows, &se); err != nil {
+			return nil, err
+		}
+		seeds = append(seeds, se)
+	}
+	return seeds, rows.Err()
+}
+
+// EntitySeedIDs resolves ref (an entity ID, name or alias, case-insensitive) within the tenant and returns
+// the IDs of all seeds linked to any matching entity.
+func (s *Store) EntitySeedIDs(ctx context.Context, ref, appID, externalUserID string) ([]int64, error) {
+	var entityID int64
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		entityID = id
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT DISTINCT se.seed_id FROM seed_entities se JOIN entities e ON e.id = se.entity_id
+		 WHERE (e.id = $1 OR lower(e.name) = lower($2) OR EXISTS (SELECT 1 FROM unnest(e.aliases) a WHERE lower(a) = lower($2)))
+		   AND ($3 = '' OR e.app_id = $3) AND ($4 = '' OR e.external_user_id = $4)`,
+		entityID, ref, appID, externalUserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// LoadKnownEntities teaches the manually created entities to their tenants' gazetteers (see CreateEntity), for
+// use at startup. Entities the extractor found are left out, so they are not matched as registered names.
+// It returns the number of entities loaded.
+func (s *Store) LoadKnownEntities(ctx context.Context) (int, error) {
+	rows, err := s.pool.Query(ctx, `SELECT name, kind, aliases, app_id, external_user_id FROM entities WHERE manual ORDER BY id`)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	n := 0
+	for rows.Next() {
+		var en Entity
+		if err := rows.Scan(&en.Name, &en.Kind, &en.Aliases, &en.AppID, &en.ExternalUserID); err != nil {
+			return n, err
+		}
+		s.learned.For(en.AppID, en.ExternalUserID).Learn(en.Name, en.Kind, en.Aliases)
+		n++
+	}
+	return n, rows.Err()
+}
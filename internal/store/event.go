@@ -0,0 +1,208 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// EventChannel is the Postgres NOTIFY channel carrying the IDs of newly recorded events.
+const EventChannel = "neural_brain_events"
+
+// Event types recorded in the events table. Seed, context and goal events are written by triggers
+// (migrations 015 and 016); seed.merged is recorded by MergeSeeds.
+const (
+	EventSeedCreated    = "seed.created"
+	EventSeedUpdated    = "seed.updated"
+	EventSeedMerged     = "seed.merged"
+	EventSeedDeleted    = "seed.deleted"
+	EventContextCreated = "context.created"
+	EventGoalCompleted  = "goal.completed"
+)
+
+// EventTypes lists all event types in a stable order.
+var EventTypes = []string{EventSeedCreated, EventSeedUpdated, EventSeedMerged, EventSeedDeleted, EventContextCreated, EventGoalCompleted}
+
+// ValidEventType reports whether t is a known event type.
+func ValidEventType(t string) bool {
+	for _, et := range EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is an entry of the persisted change log.
+type Event struct {
+	ID             int64           `json:"id"`
+	Type           string          `json:"type"`
+	SeedID         *int64          `json:"seedId,omitempty"`
+	ContextID      string          `json:"contextId,omitempty"`
+	AppID          string          `json:"appId,omitempty"`
+	ExternalUserID string          `json:"externalUserId,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	TxID           int64           `json:"-"` // writing transaction, see EventCursor
+}
+
+// EventCursor is a position in the event log. Events are read in (TxID, ID) order and only once their
+// transaction is older than every transaction still running, so no event can later appear before a cursor
+// that has passed it. IDs alone do not give that guarantee: a transaction may commit after one that took a
+// higher ID.
+type EventCursor struct {
+	TxID int64
+	ID   int64
+}
+
+// Before reports whether c is an earlier position than o.
+func (c EventCursor) Before(o EventCursor) bool {
+	return c.TxID < o.TxID || c.TxID == o.TxID && c.ID < o.ID
+}
+
+// Cursor returns the position of the event.
+func (e *Event) Cursor() EventCursor {
+	return EventCursor{TxID: e.TxID, ID: e.ID}
+}
+
+// Matches reports whether the event passes the type and tenant filters. Empty filters match everything.
+func (e *Event) Matches(types []string, appID, externalUserID string) bool {
+	if appID != "" && e.AppID != appID {
+		return false
+	}
+	if externalUserID != "" && e.ExternalUserID != externalUserID {
+		return false
+	}
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == e.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// recordEvent inserts an event inside tx and notifies listeners once the transaction commits.
+func recordEvent(ctx context.Context, tx pgx.Tx, eventType string, seedID int64, appID, externalUserID string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	var id int64
+	if err := tx.QueryRow(ctx,
+		`INSERT INTO events (type, seed_id, app_id, external_user_id, payload) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		eventType, seedID, appID, externalUserID, data,
+	).Scan(&id); err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `SELECT pg_notify($1, $2)`, EventChannel, strconv.FormatInt(id, 10))
+	return err
+}
+
+// eventHorizon is the oldest transaction still running: events of older transactions are final.
+const eventHorizon = `pg_snapshot_xmin(pg_current_snapshot())::text::bigint`
+
+// EventsAfter returns up to limit final events after the cursor, in cursor order, filtered by type and tenant.
+// Empty filters match everything.
+func (s *Store) EventsAfter(ctx context.Context, after EventCursor, types []string, appID, externalUserID string, limit int) ([]Event, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	baseQuery := `SELECT id, type, seed_id, COALESCE(context_id, ''), app_id, external_user_id, payload, created_at, tx_id
+		FROM events WHERE (tx_id, id) > ($1, $2) AND tx_id < ` + eventHorizon
+	args := []interface{}{after.TxID, after.ID}
+	argIdx := 3
+	if len(types) > 0 {
+		baseQuery += " AND type = ANY($" + strconv.Itoa(argIdx) + ")"
+		args = append(args, types)
+		argIdx++
+	}
+	if appID != "" {
+		baseQuery += " AND app_id = $" + strconv.Itoa(argIdx)
+		args = append(args, appID)
+		argIdx++
+	}
+	if externalUserID != "" {
+		baseQuery += " AND external_user_id = $" + strconv.Itoa(argIdx)
+		args = append(args, externalUserID)
+		argIdx++
+	}
+	baseQuery += " ORDER BY tx_id, id LIMIT $" + strconv.Itoa(argIdx)
+	args = append(args, limit)
+
+	rows, err := s.pool.Query(ctx, baseQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Event
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e.ID, &e.Type, &e.SeedID, &e.ContextID, &e.AppID, &e.ExternalUserID, &e.Payload, &e.CreatedAt, &e.TxID); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// LatestEventCursor returns a cursor after every event that is final now; events of transactions still
+// running come after it.
+func (s *Store) LatestEventCursor(ctx context.Context) (EventCursor, error) {
+	var c EventCursor
+	err := s.pool.QueryRow(ctx, `SELECT `+eventHorizon+` - 1`).Scan(&c.TxID)
+	c.ID = math.MaxInt64
+	return c, err
+}
+
+// EventCursorAt returns the cursor of the event with this ID, for resuming from a Last-Event-ID. If that event
+// no longer exists (purged), the closest older one is used; 0 or an empty log yield the start of the log.
+func (s *Store) EventCursorAt(ctx context.Context, id int64) (EventCursor, error) {
+	var c EventCursor
+	if id <= 0 {
+		return c, nil
+	}
+	err := s.pool.QueryRow(ctx,
+		`SELECT tx_id, id FROM events WHERE id <= $1 ORDER BY id DESC LIMIT 1`, id,
+	).Scan(&c.TxID, &c.ID)
+	if err == pgx.ErrNoRows {
+		return EventCursor{}, nil
+	}
+	return c, err
+}
+
+// PurgeEvents deletes events recorded before the cutoff.
+func (s *Store) PurgeEvents(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM events WHERE created_at < $1`, before)
+	return tag.RowsAffected(), err
+}
+
+// ListenEvents holds a dedicated connection listening on EventChannel and calls notify for every
+// notification until ctx is cancelled or the connection fails. The error is returned so the caller
+// can reconnect.
+func (s *Store) ListenEvents(ctx context.Context, notify func()) error {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection is left in LISTEN state; take it out of the pool and close it when done.
+	listener := conn.Hijack()
+	defer listener.Close(context.Background())
+	if _, err := listener.Exec(ctx, "LISTEN "+EventChannel); err != nil {
+		return err
+	}
+	// Catch up with anything recorded before LISTEN took effect.
+	notify()
+	for {
+		if _, err := listener.WaitForNotification(ctx); err != nil {
+			return err
+		}
+		notify()
+	}
+}
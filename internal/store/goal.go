@@ -0,0 +1,305 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+)
+
+// Goal statuses.
+const (
+	GoalActive    = "active"
+	GoalPaused    = "paused"
+	GoalCompleted = "completed"
+	GoalAbandoned = "abandoned"
+)
+
+// goalTransitions lists the statuses a goal may move to from each status.
+var goalTransitions = map[string]map[string]bool{
+	GoalActive:    {GoalPaused: true, GoalCompleted: true, GoalAbandoned: true},
+	GoalPaused:    {GoalActive: true, GoalCompleted: true, GoalAbandoned: true},
+	GoalCompleted: {GoalActive: true},
+	GoalAbandoned: {GoalActive: true},
+}
+
+// ErrInvalidTransition is returned when a goal status change is not allowed by the lifecycle.
+var ErrInvalidTransition = errors.New("invalid goal status transition")
+
+// ErrParentNotFound is returned when a goal references a parent that does not exist or belongs to another tenant.
+var ErrParentNotFound = errors.New("parent goal not found")
+
+// ValidGoalStatus reports whether status is a known goal status.
+func ValidGoalStatus(status string) bool {
+	_, ok := goalTransitions[status]
+	return ok
+}
+
+// Goal is a node in an agent's goal hierarchy.
+type Goal struct {
+	ID             int64   `json:"id"`
+	ParentID       *int64  `json:"parentId,omitempty"`
+	Title          string  `json:"title"`
+	Description    string  `json:"description"`
+	Status         string  `json:"status"`
+	Priority       int     `json:"priority"`
+	Deadline       string  `json:"deadline,omitempty"`
+	AppID          string  `json:"appId,omitempty"`
+	ExternalUserID string  `json:"externalUserId,omitempty"`
+	CreatedAt      string  `json:"createdAt,omitempty"`
+	UpdatedAt      string  `json:"updatedAt,omitempty"`
+	CompletedAt    string  `json:"completedAt,omitempty"`
+	Children       []*Goal `json:"children,omitempty"` // only set by GoalTree
+}
+
+// GoalUpdate holds the optional fields of a goal update; nil means unchanged.
+type GoalUpdate struct {
+	Title         *string
+	Description   *string
+	Priority      *int
+	Deadline      *time.Time
+	ClearDeadline bool
+	Status        *string
+	Embedding     []float32 // required when Title or Description changes
+}
+
+// GoalScore is the similarity of an action to one active goal.
+type GoalScore struct {
+	GoalID     int64   `json:"goalId"`
+	Title      string  `json:"title"`
+	Priority   int     `json:"priority"`
+	Similarity float64 `json:"similarity"`
+}
+
+const goalColumns = `id, parent_id, title, description, status, priority, deadline, app_id, external_user_id, created_at, updated_at, completed_at`
+
+func scanGoal(row pgx.Row, g *Goal) error {
+	var deadline, completedAt *time.Time
+	var createdAt, updatedAt time.Time
+	var appID, externalUserID *string
+	if err := row.Scan(&g.ID, &g.ParentID, &g.Title, &g.Description, &g.Status, &g.Priority, &deadline,
+		&appID, &externalUserID, &createdAt, &updatedAt, &completedAt); err != nil {
+		return err
+	}
+	if appID != nil {
+		g.AppID = *appID
+	}
+	if externalUserID != nil {
+		g.ExternalUserID = *externalUserID
+	}
+	if deadline != nil {
+		g.Deadline = deadline.Format(time.RFC3339)
+	}
+	if completedAt != nil {
+		g.CompletedAt = completedAt.Format(time.RFC3339)
+	}
+	g.CreatedAt = createdAt.Format(time.RFC3339)
+	g.UpdatedAt = updatedAt.Format(time.RFC3339)
+	return nil
+}
+
+// InsertGoal creates an active goal and returns its ID. The parent must belong to the same tenant.
+func (s *Store) InsertGoal(ctx context.Context, g Goal, deadline *time.Time, embedding []float32) (int64, error) {
+	if g.ParentID != nil {
+		parent, err := s.GetGoal(ctx, *g.ParentID)
+		if err != nil {
+			return 0, err
+		}
+		if parent == nil || parent.AppID != g.AppID || parent.ExternalUserID != g.ExternalUserID {
+			return 0, ErrParentNotFound
+		}
+	}
+	var id int64
+	err := s.pool.QueryRow(ctx,
+		`INSERT INTO goals (parent_id, title, description, priority, deadline, embedding, app_id, external_user_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		g.ParentID, g.Title, g.Description, g.Priority, deadline, pgvector.NewVector(embedding), g.AppID, g.ExternalUserID,
+	).Scan(&id)
+	return id, err
+}
+
+// GetGoal returns a single goal, or nil if not found.
+func (s *Store) GetGoal(ctx context.Context, id int64) (*Goal, error) {
+	var g Goal
+	err := scanGoal(s.pool.QueryRow(ctx, `SELECT `+goalColumns+` FROM goals WHERE id = $1`, id), &g)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &g, nil
+}
+
+// ListGoals returns goals ordered by priority (desc) then creation, optionally filtered by status.
+func (s *Store) ListGoals(ctx context.Context, status, appID, externalUserID string) ([]Goal, error) {
+	baseQuery := `SELECT ` + goalColumns + ` FROM goals WHERE 1=1`
+	args := []interface{}{}
+	argIdx := 1
+	if status != "" {
+		baseQuery += ` AND status = $` + strconv.Itoa(argIdx)
+		args = append(args, status)
+		argIdx++
+	}
+	if appID != "" {
+		baseQuery += ` AND app_id = $` + strconv.Itoa(argIdx)
+		args = append(args, appID)
+		argIdx++
+	}
+	if externalUserID != "" {
+		baseQuery += ` AND external_user_id = $` + strconv.Itoa(argIdx)
+		args = append(args, externalUserID)
+		argIdx++
+	}
+	baseQuery += ` ORDER BY priority DESC, created_at`
+
+	rows, err := s.pool.Query(ctx, baseQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []Goal
+	for rows.Next() {
+		var g Goal
+		if err := scanGoal(rows, &g); err != nil {
+			return nil, err
+		}
+		list = append(list, g)
+	}
+	return list, rows.Err()
+}
+
+// GoalTree returns the goal forest: root goals with nested children. A goal whose parent is filtered
+// out (e.g. by status) is returned as a root.
+func (s *Store) GoalTree(ctx context.Context, status, appID, externalUserID string) ([]*Goal, error) {
+	list, err := s.ListGoals(ctx, status, appID, externalUserID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*Goal, len(list))
+	for i := range list {
+		byID[list[i].ID] = &list[i]
+	}
+	roots := []*Goal{}
+	for i := range list {
+		g := &list[i]
+		if g.ParentID != nil {
+			if parent, ok := byID[*g.ParentID]; ok {
+				parent.Children = append(parent.Children, g)
+				continue
+			}
+		}
+		roots = append(roots, g)
+	}
+	return roots, nil
+}
+
+// UpdateGoal applies u to a goal. Completing a goal cascades to all its active or paused descendants;
+// the returned slice lists the IDs of every goal that was completed (including id).
+func (s *Store) UpdateGoal(ctx context.Context, id int64, u GoalUpdate) ([]int64, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var current string
+	if err := tx.QueryRow(ctx, `SELECT status FROM goals WHERE id = $1 FOR UPDATE`, id).Scan(&current); err != nil {
+		return nil, err
+	}
+	if u.Status != nil && *u.Status != current && !goalTransitions[current][*u.Status] {
+		return nil, ErrInvalidTransition
+	}
+
+	var vec *pgvector.Vector
+	if u.Embedding != nil {
+		v := pgvector.NewVector(u.Embedding)
+		vec = &v
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE goals SET
+			title = COALESCE($2, title),
+			description = COALESCE($3, description),
+			priority = COALESCE($4, priority),
+			deadline = CASE WHEN $5 THEN NULL ELSE COALESCE($6, deadline) END,
+			embedding = COALESCE($7, embedding),
+			status = COALESCE($8, status),
+			completed_at = CASE WHEN $8 = 'completed' AND status <> 'completed' THEN NOW()
+			                    WHEN $8 IS NOT NULL AND $8 <> 'completed' THEN NULL
+			                    ELSE completed_at END,
+			updated_at = NOW()
+		 WHERE id = $1`,
+		id, u.Title, u.Description, u.Priority, u.ClearDeadline, u.Deadline, vec, u.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var completed []int64
+	if u.Status != nil && *u.Status == GoalCompleted && current != GoalCompleted {
+		completed = append(completed, id)
+		rows, err := tx.Query(ctx,
+			`WITH RECURSIVE descendants AS (
+				SELECT id FROM goals WHERE parent_id = $1
+				UNION ALL
+				SELECT g.id FROM goals g JOIN descendants d ON g.parent_id = d.id
+			)
+			UPDATE goals SET status = 'completed', completed_at = NOW(), updated_at = NOW()
+			WHERE id IN (SELECT id FROM descendants) AND status IN ('active', 'paused')
+			RETURNING id`,
+			id,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var child int64
+			if err := rows.Scan(&child); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			completed = append(completed, child)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return completed, tx.Commit(ctx)
+}
+
+// EvaluateGoals scores an action embedding against every active goal in a single query, most similar first.
+func (s *Store) EvaluateGoals(ctx context.Context, actionEmbedding []float32, appID, externalUserID string) ([]GoalScore, error) {
+	baseQuery := `SELECT id, title, priority, 1 - (embedding <=> $1) AS similarity FROM goals WHERE status = 'active'`
+	args := []interface{}{pgvector.NewVector(actionEmbedding)}
+	argIdx := 2
+	if appID != "" {
+		baseQuery += ` AND app_id = $` + strconv.Itoa(argIdx)
+		args = append(args, appID)
+		argIdx++
+	}
+	if externalUserID != "" {
+		baseQuery += ` AND external_user_id = $` + strconv.Itoa(argIdx)
+		args = append(args, externalUserID)
+		argIdx++
+	}
+	baseQuery += ` ORDER BY embedding <=> $1`
+
+	rows, err := s.pool.Query(ctx, baseQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var scores []GoalScore
+	for rows.Next() {
+		var gs GoalScore
+		if err := rows.Scan(&gs.GoalID, &gs.Title, &gs.Priority, &gs.Similarity); err != nil {
+			return nil, err
+		}
+		scores = append(scores, gs)
+	}
+	return scores, rows.Err()
+}
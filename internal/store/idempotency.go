@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrIdempotencyMismatch is returned by ClaimIdempotencyKey when the key was used for a different request.
+	ErrIdempotencyMismatch = errors.New("idempotency key was used with a different request")
+	// ErrIdempotencyInProgress is returned by ClaimIdempotencyKey while the first request with the key still runs.
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// IdempotencyKey identifies a keyed write request: the client's key, scoped to tenant and route, and a
+// fingerprint of the request it was first used with.
+type IdempotencyKey struct {
+	Key            string
+	Route          string
+	AppID          string
+	ExternalUserID string
+	Fingerprint    string
+}
+
+// StoredResponse is the recorded response of a completed keyed request.
+type StoredResponse struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// ClaimIdempotencyKey claims k for a new request, valid for window. It returns nil if the caller owns the key
+// and must run the request, the stored response if an identical request already completed, or
+// ErrIdempotencyMismatch / ErrIdempotencyInProgress. An unfinished claim older than stale (a crashed
+// request) and an expired key are taken over.
+func (s *Store) ClaimIdempotencyKey(ctx context.Context, k IdempotencyKey, window, stale time.Duration) (*StoredResponse, error) {
+	var claimed bool
+	err := s.pool.QueryRow(ctx,
+		`INSERT INTO idempotency_keys (app_id, external_user_id, route, key, fingerprint, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, now() + $6 * interval '1 second')
+		 ON CONFLICT (app_id, external_user_id, route, key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint, status_code = NULL, content_type = '', response = NULL,
+			created_at = now(), expires_at = EXCLUDED.expires_at
+		 WHERE idempotency_keys.expires_at <= now()
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < now() - $7 * interval '1 second')
+		 RETURNING true`,
+		k.AppID, k.ExternalUserID, k.Route, k.Key, k.Fingerprint, window.Seconds(), stale.Seconds(),
+	).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if err != pgx.ErrNoRows {
+		return nil, err
+	}
+
+	// The key is held by a live request or response.
+	var fingerprint string
+	var resp StoredResponse
+	var statusCode *int
+	err = s.pool.QueryRow(ctx,
+		`SELECT fingerprint, status_code, content_type, COALESCE(response, ''::bytea) FROM idempotency_keys
+		 WHERE app_id = $1 AND external_user_id = $2 AND route = $3 AND key = $4`,
+		k.AppID, k.ExternalUserID, k.Route, k.Key,
+	).Scan(&fingerprint, &statusCode, &resp.ContentType, &resp.Body)
+	if err == pgx.ErrNoRows {
+		// Released in the meantime; the client may retry.
+		return nil, ErrIdempotencyInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+	if fingerprint != k.Fingerprint {
+		return nil, ErrIdempotencyMismatch
+	}
+	if statusCode == nil {
+		return nil, ErrIdempotencyInProgress
+	}
+	resp.StatusCode = *statusCode
+	return &resp, nil
+}
+
+// CompleteIdempotencyKey stores the response of the request that claimed k.
+func (s *Store) CompleteIdempotencyKey(ctx context.Context, k IdempotencyKey, resp StoredResponse) error {
+	_, err := s.pool.Exec(ctx,
+		`UPDATE idempotency_keys SET status_code = $5, content_type = $6, response = $7
+		 WHERE app_id = $1 AND external_user_id = $2 AND route = $3 AND key = $4 AND fingerprint = $8`,
+		k.AppID, k.ExternalUserID, k.Route, k.Key, resp.StatusCode, resp.ContentType, resp.Body, k.Fingerprint,
+	)
+	return err
+}
+
+// ReleaseIdempotencyKey drops an unfinished claim so the request can be retried with the same key.
+func (s *Store) ReleaseIdempotencyKey(ctx context.Context, k IdempotencyKey) error {
+	_, err := s.pool.Exec(ctx,
+		`DELETE FROM idempotency_keys
+		 WHERE app_id = $1 AND external_user_id = $2 AND route = $3 AND key = $4 AND status_code IS NULL`,
+		k.AppID, k.ExternalUserID, k.Route, k.Key,
+	)
+	return err
+}
+
+// PurgeIdempotencyKeys deletes keys that expired before the cutoff.
+func (s *Store) PurgeIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < $1`, before)
+	return tag.RowsAffected(), err
+}
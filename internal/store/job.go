@@ -0,0 +1,188 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Job run statuses and triggers.
+const (
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+)
+
+// JobRun is one execution of a scheduled job.
+type JobRun struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Trigger    string `json:"trigger"`
+	Status     string `json:"status"`
+	Output     string `json:"output,omitempty"`
+	Error      string `json:"error,omitempty"`
+	StartedAt  string `json:"startedAt"`
+	FinishedAt string `json:"finishedAt,omitempty"`
+}
+
+const jobRunColumns = "id, name, trigger, status, output, error, started_at, finished_at"
+
+func scanJobRun(row pgx.Row) (JobRun, error) {
+	var jr JobRun
+	var started time.Time
+	var finished *time.Time
+	if err := row.Scan(&jr.ID, &jr.Name, &jr.Trigger, &jr.Status, &jr.Output, &jr.Error, &started, &finished); err != nil {
+		return jr, err
+	}
+	jr.StartedAt = started.Format(time.RFC3339)
+	if finished != nil {
+		jr.FinishedAt = finished.Format(time.RFC3339)
+	}
+	return jr, nil
+}
+
+// TryAdvisoryLock takes a session-level Postgres advisory lock derived from name without waiting.
+// ok is false if another session (e.g. another server instance) holds it. release must be called when ok is true;
+// it unlocks and returns the dedicated connection to the pool.
+func (s *Store) TryAdvisoryLock(ctx context.Context, name string) (release func(), ok bool, err error) {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	key := advisoryKey(name)
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&ok); err != nil {
+		conn.Release()
+		return nil, false, err
+	}
+	if !ok {
+		conn.Release()
+		return nil, false, nil
+	}
+	release = func() {
+		// The request context may be gone by now; the unlock must still happen on this connection.
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, key); err != nil {
+			// Closing the session drops all of its advisory locks.
+			conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}
+	return release, true, nil
+}
+
+func advisoryKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("neural-brain:" + name))
+	return int64(h.Sum64())
+}
+
+// ErrSlotClaimed is returned by StartJobRun when another instance already started the scheduled activation.
+var ErrSlotClaimed = errors.New("scheduled slot already claimed")
+
+// StartJobRun records a new running execution of the job and returns its id. A non-zero slot is the
+// scheduled activation the run belongs to; each (name, slot) can be claimed once, later claims get
+// ErrSlotClaimed.
+func (s *Store) StartJobRun(ctx context.Context, name, trigger string, slot time.Time) (int64, error) {
+	var slotArg *time.Time
+	if !slot.IsZero() {
+		slotArg = &slot
+	}
+	var id int64
+	err := s.pool.QueryRow(ctx,
+		`INSERT INTO job_runs (name, trigger, slot) VALUES ($1, $2, $3)
+		 ON CONFLICT (name, slot) WHERE slot IS NOT NULL DO NOTHING RETURNING id`,
+		name, trigger, slotArg,
+	).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrSlotClaimed
+	}
+	return id, err
+}
+
+// FinishJobRun stores the outcome of a run.
+func (s *Store) FinishJobRun(ctx context.Context, id int64, status, output, errMsg string) error {
+	_, err := s.pool.Exec(ctx,
+		`UPDATE job_runs SET status = $2, output = $3, error = $4, finished_at = now() WHERE id = $1`,
+		id, status, output, errMsg,
+	)
+	return err
+}
+
+// ListJobRuns returns the most recent runs of a job, newest first.
+func (s *Store) ListJobRuns(ctx context.Context, name string, limit int) ([]JobRun, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+jobRunColumns+` FROM job_runs WHERE name = $1 ORDER BY started_at DESC, id DESC LIMIT $2`,
+		name, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var runs []JobRun
+	for rows.Next() {
+		jr, err := scanJobRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, jr)
+	}
+	return runs, rows.Err()
+}
+
+// LatestJobRuns returns the most recent run of every job that has run at least once, keyed by name.
+func (s *Store) LatestJobRuns(ctx context.Context) (map[string]JobRun, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT DISTINCT ON (name) `+jobRunColumns+` FROM job_runs ORDER BY name, started_at DESC, id DESC`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	latest := make(map[string]JobRun)
+	for rows.Next() {
+		jr, err := scanJobRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		latest[jr.Name] = jr
+	}
+	return latest, rows.Err()
+}
+
+// FailStaleJobRuns marks runs that have been "running" for longer than maxAge as failed,
+// e.g. after the instance executing them crashed.
+func (s *Store) FailStaleJobRuns(ctx context.Context, maxAge time.Duration) (int64, error) {
+	tag, err := s.pool.Exec(ctx,
+		`UPDATE job_runs SET status = 'failed', error = 'abandoned (no result after ' || $1::text || ')', finished_at = now()
+		 WHERE status = 'running' AND started_at < now() - $2::interval`,
+		maxAge.String(), maxAge,
+	)
+	return tag.RowsAffected(), err
+}
+
+// PurgeJobRuns deletes finished runs started before the cutoff.
+func (s *Store) PurgeJobRuns(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM job_runs WHERE status <> 'running' AND started_at < $1`, before)
+	return tag.RowsAffected(), err
+}
+
+// PurgeMetricPoints deletes metric points recorded before the cutoff.
+func (s *Store) PurgeMetricPoints(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM metric_points WHERE recorded_at < $1`, before)
+	return tag.RowsAffected(), err
+}
+
+// DeleteWorkingContexts deletes agent contexts of memory type "working" created before the cutoff.
+// Working memory is scratch state for a single session and is not meant to outlive it.
+func (s *Store) DeleteWorkingContexts(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM agent_contexts WHERE memory_type = 'working' AND created_at < $1`, before)
+	return tag.RowsAffected(), err
+}
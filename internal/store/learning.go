@@ -0,0 +1,242 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pgvector/pgvector-go"
+)
+
+// Learning rule kinds and actions.
+const (
+	RuleSemantic = "semantic" // cosine similarity to a prototype phrase
+	RuleLexical  = "lexical"  // regular expression over seed content
+
+	ActionLearningSeed = "learning_seed"
+	ActionTag          = "tag"
+	ActionEmotion      = "emotion"
+)
+
+// ErrRuleNameTaken is returned when a learning rule name is already in use by the same tenant.
+var ErrRuleNameTaken = errors.New("learning rule name already exists")
+
+// LearningRule matches recent seeds and triggers an action once enough of them match within the window.
+type LearningRule struct {
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Kind           string          `json:"kind"`
+	Pattern        string          `json:"pattern"`   // prototype phrase or regex
+	Threshold      float64         `json:"threshold"` // semantic only
+	WindowHours    float64         `json:"windowHours"`
+	MinCount       int             `json:"minCount"`
+	Action         string          `json:"action"`
+	ActionParams   json.RawMessage `json:"actionParams"`
+	AppID          string          `json:"appId,omitempty"`
+	ExternalUserID string          `json:"externalUserId,omitempty"`
+	Enabled        bool            `json:"enabled"`
+	LastFiredAt    string          `json:"lastFiredAt,omitempty"`
+	CreatedAt      string          `json:"createdAt"`
+	UpdatedAt      string          `json:"updatedAt"`
+	Embedding      []float32       `json:"-"`
+}
+
+// LearningEvaluation is one logged evaluation of a rule.
+type LearningEvaluation struct {
+	ID             int64   `json:"id"`
+	RuleID         int64   `json:"ruleId"`
+	MatchCount     int     `json:"matchCount"`
+	MatchedSeedIDs []int64 `json:"matchedSeedIds"`
+	Fired          bool    `json:"fired"`
+	Result         string  `json:"result,omitempty"`
+	Error          string  `json:"error,omitempty"`
+	EvaluatedAt    string  `json:"evaluatedAt"`
+}
+
+const learningRuleColumns = `id, name, kind, pattern, embedding, threshold, window_hours, min_count, action, action_params,
+	app_id, external_user_id, enabled, last_fired_at, created_at, updated_at`
+
+func scanLearningRule(row pgx.Row) (LearningRule, error) {
+	var r LearningRule
+	var emb *pgvector.Vector
+	var lastFired *time.Time
+	var createdAt, updatedAt time.Time
+	err := row.Scan(&r.ID, &r.Name, &r.Kind, &r.Pattern, &emb, &r.Threshold, &r.WindowHours, &r.MinCount, &r.Action, &r.ActionParams,
+		&r.AppID, &r.ExternalUserID, &r.Enabled, &lastFired, &createdAt, &updatedAt)
+	if err != nil {
+		return r, err
+	}
+	if emb != nil {
+		r.Embedding = emb.Slice()
+	}
+	if lastFired != nil {
+		r.LastFiredAt = lastFired.Format(time.RFC3339)
+	}
+	r.CreatedAt = createdAt.Format(time.RFC3339)
+	r.UpdatedAt = updatedAt.Format(time.RFC3339)
+	return r, nil
+}
+
+// ruleEmbedding stores the prototype embedding for semantic rules and NULL for lexical ones.
+func ruleEmbedding(r LearningRule) *pgvector.Vector {
+	if r.Kind != RuleSemantic || len(r.Embedding) == 0 {
+		return nil
+	}
+	v := pgvector.NewVector(r.Embedding)
+	return &v
+}
+
+func ruleParams(r LearningRule) json.RawMessage {
+	if len(r.ActionParams) == 0 {
+		return json.RawMessage("{}")
+	}
+	return r.ActionParams
+}
+
+func uniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// InsertLearningRule creates a rule and returns its ID.
+func (s *Store) InsertLearningRule(ctx context.Context, r LearningRule) (int64, error) {
+	var id int64
+	err := s.pool.QueryRow(ctx,
+		`INSERT INTO learning_rules (name, kind, pattern, embedding, threshold, window_hours, min_count, action, action_params, app_id, external_user_id, enabled)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`,
+		r.Name, r.Kind, r.Pattern, ruleEmbedding(r), r.Threshold, r.WindowHours, r.MinCount, r.Action, ruleParams(r), r.AppID, r.ExternalUserID, r.Enabled,
+	).Scan(&id)
+	if uniqueViolation(err) {
+		return 0, ErrRuleNameTaken
+	}
+	return id, err
+}
+
+// UpdateLearningRule replaces all editable fields of a rule. It returns pgx.ErrNoRows if the rule does not exist.
+func (s *Store) UpdateLearningRule(ctx context.Context, r LearningRule) error {
+	tag, err := s.pool.Exec(ctx,
+		`UPDATE learning_rules SET name = $2, kind = $3, pattern = $4, embedding = $5, threshold = $6, window_hours = $7, min_count = $8,
+		 action = $9, action_params = $10, app_id = $11, external_user_id = $12, enabled = $13, updated_at = now()
+		 WHERE id = $1`,
+		r.ID, r.Name, r.Kind, r.Pattern, ruleEmbedding(r), r.Threshold, r.WindowHours, r.MinCount, r.Action, ruleParams(r), r.AppID, r.ExternalUserID, r.Enabled,
+	)
+	if uniqueViolation(err) {
+		return ErrRuleNameTaken
+	}
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteLearningRule deletes a rule and its evaluation log. It returns pgx.ErrNoRows if the rule does not exist.
+func (s *Store) DeleteLearningRule(ctx context.Context, id int64) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM learning_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetLearningRule returns a single rule, or nil if not found.
+func (s *Store) GetLearningRule(ctx context.Context, id int64) (*LearningRule, error) {
+	r, err := scanLearningRule(s.pool.QueryRow(ctx, `SELECT `+learningRuleColumns+` FROM learning_rules WHERE id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListLearningRules returns the rules of a tenant (or only enabled ones), ordered by ID. Empty tenant fields are not filtered.
+func (s *Store) ListLearningRules(ctx context.Context, enabledOnly bool, appID, externalUserID string) ([]LearningRule, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+learningRuleColumns+` FROM learning_rules
+		 WHERE (enabled OR NOT $1) AND ($2 = '' OR app_id = $2) AND ($3 = '' OR external_user_id = $3) ORDER BY id`,
+		enabledOnly, appID, externalUserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var rules []LearningRule
+	for rows.Next() {
+		r, err := scanLearningRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// MarkLearningRuleFired records when a rule last triggered its action; later evaluations only count newer seeds.
+func (s *Store) MarkLearningRuleFired(ctx context.Context, id int64, at time.Time) error {
+	_, err := s.pool.Exec(ctx, `UPDATE learning_rules SET last_fired_at = $2 WHERE id = $1`, id, at)
+	return err
+}
+
+// InsertLearningEvaluation logs one rule evaluation.
+func (s *Store) InsertLearningEvaluation(ctx context.Context, e LearningEvaluation) error {
+	ids := e.MatchedSeedIDs
+	if ids == nil {
+		ids = []int64{}
+	}
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO learning_evaluations (rule_id, match_count, matched_seed_ids, fired, result, error) VALUES ($1, $2, $3, $4, $5, $6)`,
+		e.RuleID, e.MatchCount, ids, e.Fired, e.Result, e.Error,
+	)
+	return err
+}
+
+// ListLearningEvaluations returns the evaluation log, newest first, optionally for a single rule (ruleID > 0).
+func (s *Store) ListLearningEvaluations(ctx context.Context, ruleID int64, limit int) ([]LearningEvaluation, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, rule_id, match_count, matched_seed_ids, fired, result, error, evaluated_at
+		 FROM learning_evaluations WHERE $1 = 0 OR rule_id = $1 ORDER BY evaluated_at DESC, id DESC LIMIT $2`,
+		ruleID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var evals []LearningEvaluation
+	for rows.Next() {
+		var e LearningEvaluation
+		var at time.Time
+		if err := rows.Scan(&e.ID, &e.RuleID, &e.MatchCount, &e.MatchedSeedIDs, &e.Fired, &e.Result, &e.Error, &at); err != nil {
+			return nil, err
+		}
+		e.EvaluatedAt = at.Format(time.RFC3339)
+		evals = append(evals, e)
+	}
+	return evals, rows.Err()
+}
+
+// AddSeedTag appends tag to metadata.tags of each seed that does not have it yet and returns how many changed.
+func (s *Store) AddSeedTag(ctx context.Context, ids []int64, tag string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	res, err := s.pool.Exec(ctx,
+		`UPDATE seeds SET metadata = jsonb_set(COALESCE(metadata, '{}'), '{tags}',
+		   CASE WHEN jsonb_typeof(metadata->'tags') = 'array' THEN metadata->'tags' ELSE '[]'::jsonb END || to_jsonb($2::text))
+		 WHERE id = ANY($1) AND NOT COALESCE(metadata->'tags', '[]'::jsonb) ? $2`,
+		ids, tag,
+	)
+	return res.RowsAffected(), err
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Metric names written by the built-in sampler.
+const (
+	MetricSeedsCount         = "seeds_count"          // per tenant
+	MetricAgentContextsCount = "agent_contexts_count" // per tenant
+	MetricSeedsTotal         = "seeds_total"          // all tenants
+	MetricAgentContextsTotal = "agent_contexts_total" // all tenants
+)
+
+// metricAggregates maps the agg parameter of MetricSeries to SQL.
+var metricAggregates = map[string]string{
+	"avg":   `AVG(value)`,
+	"min":   `MIN(value)`,
+	"max":   `MAX(value)`,
+	"sum":   `SUM(value)`,
+	"count": `COUNT(*)::double precision`,
+	"last":  `(array_agg(value ORDER BY recorded_at DESC))[1]`,
+}
+
+// ValidMetricAggregate reports whether agg is supported by MetricSeries.
+func ValidMetricAggregate(agg string) bool {
+	_, ok := metricAggregates[agg]
+	return ok
+}
+
+// MetricPoint is a single observation. A zero RecordedAt means now.
+type MetricPoint struct {
+	Name           string          `json:"name"`
+	Value          float64         `json:"value"`
+	Labels         json.RawMessage `json:"labels,omitempty"`
+	AppID          string          `json:"appId,omitempty"`
+	ExternalUserID string          `json:"externalUserId,omitempty"`
+	RecordedAt     time.Time       `json:"recordedAt"`
+}
+
+// SeriesPoint is one downsampled bucket of a metric series.
+type SeriesPoint struct {
+	Time  string  `json:"t"`
+	Value float64 `json:"v"`
+	Count int64   `json:"n"` // raw points in the bucket
+}
+
+// TenantCount is a row count for one (appId, externalUserId) pair.
+type TenantCount struct {
+	AppID          string
+	ExternalUserID string
+	Count          int64
+}
+
+// InsertMetricPoints stores points in one batch.
+func (s *Store) InsertMetricPoints(ctx context.Context, points []MetricPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	now := time.Now()
+	for _, p := range points {
+		at := p.RecordedAt
+		if at.IsZero() {
+			at = now
+		}
+		labels := p.Labels
+		if len(labels) == 0 {
+			labels = []byte("{}")
+		}
+		batch.Queue(
+			`INSERT INTO metric_points (name, value, labels, app_id, external_user_id, recorded_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+			p.Name, p.Value, labels, p.AppID, p.ExternalUserID, at,
+		)
+	}
+	return s.pool.SendBatch(ctx, batch).Close()
+}
+
+// MetricSeries returns the metric downsampled into step-wide buckets between from and to, aggregated with agg.
+// The tenant must match exactly; empty appID/externalUserID select untenanted or global metrics.
+func (s *Store) MetricSeries(ctx context.Context, name string, from, to time.Time, step time.Duration, agg, appID, externalUserID string) ([]SeriesPoint, error) {
+	expr, ok := metricAggregates[agg]
+	if !ok {
+		expr = metricAggregates["avg"]
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT date_bin($1::interval, recorded_at, $2) AS bucket, `+expr+`, COUNT(*)
+		 FROM metric_points
+		 WHERE name = $3 AND app_id = $4 AND external_user_id = $5 AND recorded_at >= $2 AND recorded_at < $6
+		 GROUP BY bucket ORDER BY bucket`,
+		step, from, name, appID, externalUserID, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var series []SeriesPoint
+	for rows.Next() {
+		var p SeriesPoint
+		var bucket time.Time
+		if err := rows.Scan(&bucket, &p.Value, &p.Count); err != nil {
+			return nil, err
+		}
+		p.Time = bucket.Format(time.RFC3339)
+		series = append(series, p)
+	}
+	return series, rows.Err()
+}
+
+// MetricNames lists the distinct metric names recorded for a tenant.
+func (s *Store) MetricNames(ctx context.Context, appID, externalUserID string) ([]string, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT DISTINCT name FROM metric_points WHERE app_id = $1 AND external_user_id = $2 ORDER BY name`,
+		appID, externalUserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var n string
+		if err := rows.Scan(&n); err != nil {
+			return nil, err
+		}
+		names = append(names, n)
+	}
+	return names, rows.Err()
+}
+
+// SeedsCountByTenant returns the number of seeds per tenant.
+func (s *Store) SeedsCountByTenant(ctx context.Context) ([]TenantCount, error) {
+	return s.countByTenant(ctx, `SELECT COALESCE(app_id, ''), COALESCE(external_user_id, ''), COUNT(*) FROM seeds GROUP BY 1, 2`)
+}
+
+// AgentContextsCountByTenant returns the number of agent contexts per tenant.
+func (s *Store) AgentContextsCountByTenant(ctx context.Context) ([]TenantCount, error) {
+	return s.countByTenant(ctx, `SELECT COALESCE(app_id, ''), COALESCE(external_user_id, ''), COUNT(*) FROM agent_contexts GROUP BY 1, 2`)
+}
+
+// LastSampleTenants returns the tenants with a non-zero value in the most recent sample of a metric, i.e.
+// among the points sharing its latest recorded_at.
+func (s *Store) LastSampleTenants(ctx context.Context, name string) ([]TenantCount, error) {
+	return s.countByTenant(ctx,
+		`SELECT app_id, external_user_id, value::bigint FROM metric_points
+		 WHERE name = $1 AND value <> 0
+		   AND recorded_at = (SELECT MAX(recorded_at) FROM metric_points WHERE name = $1)`,
+		name,
+	)
+}
+
+func (s *Store) countByTenant(ctx context.Context, sql string, args ...interface{}) ([]TenantCount, error) {
+	rows, err := s.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var counts []TenantCount
+	for rows.Next() {
+		var c TenantCount
+		if err := rows.Scan(&c.AppID, &c.ExternalUserID, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,66 @@
+package store
+
+// DefaultMMRLambda balances relevance (1) against diversity (0) for MMR selection.
+const DefaultMMRLambda = 0.7
+
+// MMR picks up to k seeds by maximal marginal relevance: each step takes the candidate maximizing
+// lambda*relevance - (1-lambda)*max similarity to the already selected seeds.
+// Relevance is the hybrid total when present, otherwise the cosine score. Candidates must carry
+// embeddings (see SearchWithEmbeddings); GTE embeddings are L2-normalized, so the dot product is cosine.
+func MMR(candidates []Seed, lambda float64, k int) []Seed {
+	if k <= 0 || len(candidates) <= 1 {
+		return candidates
+	}
+	if k > len(candidates) {
+		k = len(candidates)
+	}
+	lambda = clamp01(lambda)
+
+	selected := make([]Seed, 0, k)
+	used := make([]bool, len(candidates))
+	// maxSim[i] is candidate i's highest similarity to any selected seed.
+	maxSim := make([]float64, len(candidates))
+	for len(selected) < k {
+		best, bestScore := -1, 0.0
+		for i := range candidates {
+			if used[i] {
+				continue
+			}
+			score := lambda*relevance(&candidates[i]) - (1-lambda)*maxSim[i]
+			if best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		used[best] = true
+		selected = append(selected, candidates[best])
+		for i := range candidates {
+			if used[i] {
+				continue
+			}
+			if sim := Cosine(candidates[i].Embedding, candidates[best].Embedding); sim > maxSim[i] {
+				maxSim[i] = sim
+			}
+		}
+	}
+	return selected
+}
+
+func relevance(se *Seed) float64 {
+	if se.ScoreBreakdown != nil {
+		return se.ScoreBreakdown.Total
+	}
+	return se.Score
+}
+
+// Cosine returns the cosine similarity of two L2-normalized embeddings (their dot product).
+func Cosine(a, b []float32) float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	var sum float64
+	for i := 0; i < n; i++ {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
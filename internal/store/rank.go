@@ -0,0 +1,134 @@
+package store
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+)
+
+// Rank modes accepted by RankParams.Mode.
+const (
+	RankSimilarity = "similarity" // pure cosine order (default)
+	RankHybrid     = "hybrid"     // similarity + recency + importance + access frequency
+)
+
+const defaultHalfLife = 72 * time.Hour
+
+// RankParams controls how search candidates are scored. Weights are relative and need not sum to 1.
+type RankParams struct {
+	Mode             string
+	HalfLife         time.Duration
+	WeightSimilarity float64
+	WeightRecency    float64
+	WeightImportance float64
+	WeightAccess     float64
+}
+
+// ScoreBreakdown exposes the normalized (0..1) components of a hybrid score.
+type ScoreBreakdown struct {
+	Similarity float64 `json:"similarity"`
+	Recency    float64 `json:"recency"`
+	Importance float64 `json:"importance"`
+	Access     float64 `json:"access"`
+	Total      float64 `json:"total"`
+}
+
+// RankParams returns the default ranking parameters, using the store's configured half-life.
+func (s *Store) RankParams() RankParams {
+	return RankParams{
+		Mode:             RankSimilarity,
+		HalfLife:         s.halfLife,
+		WeightSimilarity: 1,
+		WeightRecency:    1,
+		WeightImportance: 1,
+		WeightAccess:     0.5,
+	}
+}
+
+// Rank scores seeds by p and returns them sorted by total score (descending).
+// Seed.Score keeps the raw similarity; the combined score is in Seed.ScoreBreakdown.Total.
+// In similarity mode the input is returned unchanged.
+func Rank(seeds []Seed, p RankParams, now time.Time) []Seed {
+	if p.Mode != RankHybrid || len(seeds) == 0 {
+		return seeds
+	}
+	halfLife := p.HalfLife
+	if halfLife <= 0 {
+		halfLife = defaultHalfLife
+	}
+	var maxAccess int64
+	for _, se := range seeds {
+		if se.AccessCount > maxAccess {
+			maxAccess = se.AccessCount
+		}
+	}
+	weightSum := p.WeightSimilarity + p.WeightRecency + p.WeightImportance + p.WeightAccess
+	if weightSum <= 0 {
+		weightSum = 1
+	}
+
+	for i := range seeds {
+		se := &seeds[i]
+		b := &ScoreBreakdown{
+			Similarity: clamp01(se.Score),
+			Recency:    recency(se, now, halfLife),
+			Importance: importance(se.Metadata),
+		}
+		if maxAccess > 0 {
+			b.Access = math.Log1p(float64(se.AccessCount)) / math.Log1p(float64(maxAccess))
+		}
+		b.Total = (p.WeightSimilarity*b.Similarity +
+			p.WeightRecency*b.Recency +
+			p.WeightImportance*b.Importance +
+			p.WeightAccess*b.Access) / weightSum
+		se.ScoreBreakdown = b
+	}
+	sort.SliceStable(seeds, func(i, j int) bool {
+		return seeds[i].ScoreBreakdown.Total > seeds[j].ScoreBreakdown.Total
+	})
+	return seeds
+}
+
+// recency decays exponentially from the last access (or creation) time: 1 now, 0.5 after one half-life.
+func recency(se *Seed, now time.Time, halfLife time.Duration) float64 {
+	ref := se.LastAccessedAt
+	if ref == "" {
+		ref = se.CreatedAt
+	}
+	t, err := time.Parse(time.RFC3339, ref)
+	if err != nil {
+		return 0
+	}
+	age := now.Sub(t)
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, float64(age)/float64(halfLife))
+}
+
+// importance reads metadata.importance. The reflection skill writes 1-10, so values above 1 are scaled by 1/10.
+// Seeds without an importance are treated as neutral (0.5).
+func importance(metadata json.RawMessage) float64 {
+	var m struct {
+		Importance *float64 `json:"importance"`
+	}
+	if len(metadata) == 0 || json.Unmarshal(metadata, &m) != nil || m.Importance == nil {
+		return 0.5
+	}
+	v := *m.Importance
+	if v > 1 {
+		v /= 10
+	}
+	return clamp01(v)
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -3,6 +3,8 @@ package store
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"slices"
 	"strconv"
 	"time"
 
@@ -10,17 +12,28 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pgvector/pgvector-go"
 	pgxvec "github.com/pgvector/pgvector-go/pgx"
+
+	"github.com/cabroe/neural-brain/internal/entity"
 )
 
 // Seed is a stored item with content, embedding, and optional metadata.
+// JSON names are camelCase like the rest of the API; created_at keeps its original snake_case name because the
+// dashboard, the Go client and scripts already read it.
 type Seed struct {
-	ID             int64           `json:"id"`
-	Content        string          `json:"content"`
-	Metadata       json.RawMessage `json:"metadata"`
-	AppID          string          `json:"appId,omitempty"`
-	ExternalUserID string          `json:"externalUserId,omitempty"`
-	CreatedAt      string          `json:"created_at,omitempty"`
-	Score          float64         `json:"score,omitempty"` // similarity score for search results
+	ID             int64            `json:"id"`
+	Content        string           `json:"content"`
+	Metadata       json.RawMessage  `json:"metadata"`
+	AppID          string           `json:"appId,omitempty"`
+	ExternalUserID string           `json:"externalUserId,omitempty"`
+	CreatedAt      string           `json:"created_at,omitempty"`
+	AccessCount    int64            `json:"accessCount"`
+	LastAccessedAt string           `json:"lastAccessedAt,omitempty"`
+	Version        int64            `json:"version"`                  // bumped on every content or metadata change; the ETag
+	Score          float64          `json:"score,omitempty"`          // similarity score for search results
+	ScoreBreakdown *ScoreBreakdown  `json:"scoreBreakdown,omitempty"` // set when a non-similarity rank mode is used
+	Embedding      []float32        `json:"-"`                        // only filled by SearchWithEmbeddings
+	Document       *DocumentContext `json:"document,omitempty"`       // set for document chunks by AttachDocumentContext
+	Via            *EdgeRef         `json:"via,omitempty"`            // set for seeds reached through the relation graph
 }
 
 // AgentContext is a session-scoped context for an agent (episodic, semantic, procedural, working).
@@ -34,31 +47,107 @@ type AgentContext struct {
 	CreatedAt      string          `json:"createdAt,omitempty"`
 }
 
+// ErrVersionConflict is returned by version-checked writes when the seed has changed since the given version.
+var ErrVersionConflict = errors.New("version conflict")
+
+// seedColumns is the column list scanned by scanSeed, followed by the score expression.
+const seedColumns = `id, content, metadata, created_at, app_id, external_user_id, access_count, last_accessed_at, version`
+
 // Store provides database operations for seeds.
 type Store struct {
 	pool           *pgxpool.Pool
-	dedupThreshold float64 // 0 = disabled; e.g. 0.92 = skip if cosine sim > 0.92
+	dedupThreshold float64       // 0 = disabled; e.g. 0.92 = skip if cosine sim > 0.92
+	halfLife       time.Duration // default recency half-life for hybrid ranking
+	extractor      entity.Extractor
+	learned        entity.TenantGazetteers // entities registered through CreateEntity, per tenant
 }
 
 // NewStore creates a Store using the given pool. AfterConnect must register pgvector types.
 func NewStore(pool *pgxpool.Pool, dedupThreshold float64) *Store {
-	return &Store{pool: pool, dedupThreshold: dedupThreshold}
+	return &Store{pool: pool, dedupThreshold: dedupThreshold, halfLife: defaultHalfLife}
+}
+
+// SetRecencyHalfLife sets the default recency half-life used by hybrid ranking. Non-positive values are ignored.
+func (s *Store) SetRecencyHalfLife(d time.Duration) {
+	if d > 0 {
+		s.halfLife = d
+	}
+}
+
+// scanSeed scans a row selected with seedColumns plus a trailing score column and any extra columns.
+func scanSeed(row pgx.Row, se *Seed, extra ...interface{}) error {
+	var createdAt time.Time
+	var lastAccessedAt *time.Time
+	var appID, externalUserID *string
+	dest := append([]interface{}{&se.ID, &se.Content, &se.Metadata, &createdAt, &appID, &externalUserID, &se.AccessCount, &lastAccessedAt, &se.Version, &se.Score}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	if appID != nil {
+		se.AppID = *appID
+	}
+	if externalUserID != nil {
+		se.ExternalUserID = *externalUserID
+	}
+	se.CreatedAt = createdAt.Format(time.RFC3339)
+	if lastAccessedAt != nil {
+		se.LastAccessedAt = lastAccessedAt.Format(time.RFC3339)
+	}
+	return nil
 }
 
 // Insert adds a seed: embed content, optionally dedupe, then INSERT. Returns id or 0 if skipped (duplicate).
 func (s *Store) Insert(ctx context.Context, content string, metadata json.RawMessage, embedding []float32, appID, externalUserID string) (int64, error) {
-	vec := pgvector.NewVector(embedding)
+	ids, err := s.InsertSeeds(ctx, []NewSeed{{
+		Content: content, Metadata: metadata, Embedding: embedding, AppID: appID, ExternalUserID: externalUserID,
+	}})
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// NewSeed is one seed to store with InsertSeeds.
+type NewSeed struct {
+	Content        string
+	Metadata       json.RawMessage
+	Embedding      []float32
+	AppID          string
+	ExternalUserID string
+}
+
+// InsertSeeds stores several seeds in one transaction, with the same dedupe as Insert: either every seed is
+// stored (or merged into its near-duplicate) or none is. It returns the IDs in input order.
+func (s *Store) InsertSeeds(ctx context.Context, seeds []NewSeed) ([]int64, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	ids := make([]int64, 0, len(seeds))
+	for _, n := range seeds {
+		id, err := s.insertTx(ctx, tx, n)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, tx.Commit(ctx)
+}
+
+func (s *Store) insertTx(ctx context.Context, tx pgx.Tx, n NewSeed) (int64, error) {
+	vec := pgvector.NewVector(n.Embedding)
 
 	if s.dedupThreshold > 0 {
 		var similarity float64
 		var id int64
-		err := s.pool.QueryRow(ctx,
+		err := tx.QueryRow(ctx,
 			`SELECT id, (1 - (embedding <=> $1)) AS sim FROM seeds ORDER BY embedding <=> $1 LIMIT 1`,
 			vec,
 		).Scan(&id, &similarity)
 		if err == nil && similarity >= s.dedupThreshold {
 			// Semantic Upsert: Update timestamp and increment update_count in metadata
-			_, err = s.pool.Exec(ctx,
+			_, err = tx.Exec(ctx,
 				`UPDATE seeds SET 
 					created_at = NOW(),
 					metadata = jsonb_set(
@@ -76,8 +165,34 @@ func (s *Store) Insert(ctx context.Context, content string, metadata json.RawMes
 		}
 	}
 
+	id, err := insertSeed(ctx, tx, n.Content, n.Metadata, vec, n.AppID, n.ExternalUserID)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.indexSeed(ctx, tx, id, n.Content, vec, n.AppID, n.ExternalUserID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// indexSeed derives the secondary data of a new or rewritten seed inside its transaction:
+// entity links and the nearest topic cluster.
+func (s *Store) indexSeed(ctx context.Context, tx pgx.Tx, id int64, content string, vec pgvector.Vector, appID, externalUserID string) error {
+	if err := s.linkEntities(ctx, tx, id, content, appID, externalUserID); err != nil {
+		return err
+	}
+	return assignCluster(ctx, tx, id, vec, appID, externalUserID)
+}
+
+// querier is the subset of *pgxpool.Pool and pgx.Tx used by helpers that run inside or outside a transaction.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// insertSeed INSERTs a seed without dedup and returns its ID.
+func insertSeed(ctx context.Context, q querier, content string, metadata json.RawMessage, vec pgvector.Vector, appID, externalUserID string) (int64, error) {
 	var id int64
-	err := s.pool.QueryRow(ctx,
+	err := q.QueryRow(ctx,
 		`INSERT INTO seeds (content, embedding, metadata, app_id, external_user_id) 
 		 VALUES ($1, $2, COALESCE($3::jsonb, '{}'), $4, $5) RETURNING id`,
 		content, vec, metadata, appID, externalUserID,
@@ -88,17 +203,93 @@ func (s *Store) Insert(ctx context.Context, content string, metadata json.RawMes
 	return id, nil
 }
 
-// UpdateSeedMetadata updates metadata on an existing seed by shallow merging the given JSON.
-func (s *Store) UpdateSeedMetadata(ctx context.Context, id int64, patch json.RawMessage) error {
-	cmdTag, err := s.pool.Exec(ctx,
-		`UPDATE seeds SET metadata = COALESCE(metadata, '{}'::jsonb) || $1 WHERE id = $2`,
-		patch, id,
-	)
+// UpdateSeedMetadata updates metadata on an existing seed by shallow merging the given JSON and returns
+// the new version. Non-empty ifVersions make the update conditional (ErrVersionConflict on mismatch).
+func (s *Store) UpdateSeedMetadata(ctx context.Context, id int64, patch json.RawMessage, ifVersions []int64) (int64, error) {
+	var version int64
+	err := s.pool.QueryRow(ctx,
+		`UPDATE seeds SET metadata = COALESCE(metadata, '{}'::jsonb) || $1 WHERE id = $2 AND `+versionIn(3)+`
+		 RETURNING version`,
+		patch, id, ifVersions,
+	).Scan(&version)
+	if err == pgx.ErrNoRows {
+		return 0, s.versionMiss(ctx, s.pool, id)
+	}
+	return version, err
+}
+
+// ErrMetadataNotObject is returned by PatchSeedMetadata when the patched metadata is not a JSON object.
+var ErrMetadataNotObject = errors.New("metadata must be a JSON object")
+
+// PatchSeedMetadata replaces the metadata of a seed with apply(current metadata) and returns the new
+// version. The seed row stays locked between read and write, so concurrent patches apply one after the
+// other; an error from apply leaves the seed unchanged and is returned as is. Non-empty ifVersions make
+// the update conditional (ErrVersionConflict on mismatch).
+func (s *Store) PatchSeedMetadata(ctx context.Context, id int64, ifVersions []int64, apply func(json.RawMessage) (json.RawMessage, error)) (int64, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var current json.RawMessage
+	var version int64
+	err = tx.QueryRow(ctx,
+		`SELECT COALESCE(metadata, '{}'::jsonb), version FROM seeds WHERE id = $1 FOR UPDATE`, id,
+	).Scan(&current, &version)
+	if err != nil {
+		return 0, err
+	}
+	if len(ifVersions) > 0 && !slices.Contains(ifVersions, version) {
+		return 0, ErrVersionConflict
+	}
+
+	patched, err := apply(current)
+	if err != nil {
+		return 0, err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(patched, &obj); err != nil || obj == nil {
+		return 0, ErrMetadataNotObject
+	}
+
+	if err := tx.QueryRow(ctx,
+		`UPDATE seeds SET metadata = $1 WHERE id = $2 RETURNING version`, patched, id,
+	).Scan(&version); err != nil {
+		return 0, err
+	}
+	return version, tx.Commit(ctx)
+}
+
+// versionIn is the condition of a version-checked write with the accepted versions in parameter n:
+// an empty (or NULL) list accepts any version.
+func versionIn(n int) string {
+	p := "$" + strconv.Itoa(n)
+	return `(COALESCE(cardinality(` + p + `::bigint[]), 0) = 0 OR version = ANY(` + p + `))`
+}
+
+// versionMiss explains why a version-checked write on seed id matched no row:
+// pgx.ErrNoRows if the seed does not exist, ErrVersionConflict otherwise.
+func (s *Store) versionMiss(ctx context.Context, q querier, id int64) error {
+	var exists bool
+	if err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM seeds WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return ErrVersionConflict
+	}
+	return pgx.ErrNoRows
+}
+
+// DeleteSeed deletes a seed; edges, entity links and cluster assignments go with it. Non-empty ifVersions
+// make the delete conditional (ErrVersionConflict on mismatch). It returns pgx.ErrNoRows if the seed does not exist.
+func (s *Store) DeleteSeed(ctx context.Context, id int64, ifVersions []int64) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM seeds WHERE id = $1 AND `+versionIn(2), id, ifVersions)
 	if err != nil {
 		return err
 	}
-	if cmdTag.RowsAffected() == 0 {
-		return pgx.ErrNoRows
+	if tag.RowsAffected() == 0 {
+		return s.versionMiss(ctx, s.pool, id)
 	}
 	return nil
 }
@@ -106,40 +297,62 @@ func (s *Store) UpdateSeedMetadata(ctx context.Context, id int64, patch json.Raw
 // GetSeed retrieves a single seed by its ID.
 func (s *Store) GetSeed(ctx context.Context, id int64) (*Seed, error) {
 	var se Seed
-	var createdAt time.Time
-	err := s.pool.QueryRow(ctx,
-		`SELECT id, content, metadata, created_at, app_id, external_user_id FROM seeds WHERE id = $1`,
+	err := scanSeed(s.pool.QueryRow(ctx,
+		`SELECT `+seedColumns+`, 0 AS score FROM seeds WHERE id = $1`,
 		id,
-	).Scan(&se.ID, &se.Content, &se.Metadata, &createdAt, &se.AppID, &se.ExternalUserID)
+	), &se)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil // Not found
 		}
 		return nil, err
 	}
-	se.CreatedAt = createdAt.Format(time.RFC3339)
 	return &se, nil
 }
 
-// UpdateSeed fully overwrites a seed's content, metadata, and recalculates its embedding.
-func (s *Store) UpdateSeed(ctx context.Context, id int64, content string, metadata json.RawMessage, embedding []float32, appID, externalUserID string) error {
+// UpdateSeed fully overwrites a seed's content, metadata, and recalculates its embedding. It returns the new
+// version; non-empty ifVersions make the update conditional (ErrVersionConflict on mismatch).
+func (s *Store) UpdateSeed(ctx context.Context, id int64, content string, metadata json.RawMessage, embedding []float32, appID, externalUserID string, ifVersions []int64) (int64, error) {
 	vec := pgvector.NewVector(embedding)
-	cmdTag, err := s.pool.Exec(ctx,
-		`UPDATE seeds SET content = $1, metadata = COALESCE($2::jsonb, '{}'), embedding = $3, app_id = $4, external_user_id = $5 WHERE id = $6`,
-		content, metadata, vec, appID, externalUserID, id,
-	)
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	if cmdTag.RowsAffected() == 0 {
-		return pgx.ErrNoRows
+	defer tx.Rollback(ctx)
+	var version int64
+	err = tx.QueryRow(ctx,
+		`UPDATE seeds SET content = $1, metadata = COALESCE($2::jsonb, '{}'), embedding = $3, app_id = $4, external_user_id = $5
+		 WHERE id = $6 AND `+versionIn(7)+` RETURNING version`,
+		content, metadata, vec, appID, externalUserID, id, ifVersions,
+	).Scan(&version)
+	if err == pgx.ErrNoRows {
+		return 0, s.versionMiss(ctx, tx, id)
 	}
-	return nil
+	if err != nil {
+		return 0, err
+	}
+	// Re-derive entities and cluster from the new content.
+	if _, err := tx.Exec(ctx, `DELETE FROM seed_entities WHERE seed_id = $1`, id); err != nil {
+		return 0, err
+	}
+	if err := s.indexSeed(ctx, tx, id, content, vec, appID, externalUserID); err != nil {
+		return 0, err
+	}
+	return version, tx.Commit(ctx)
 }
 
 // Search returns seeds nearest to the query embedding (cosine), limit rows.
 // If seedIDs is not empty, limits search to those specific IDs.
 func (s *Store) Search(ctx context.Context, queryEmbedding []float32, limit int, seedIDs []int64, appID, externalUserID string) ([]Seed, error) {
+	return s.search(ctx, queryEmbedding, limit, seedIDs, appID, externalUserID, false)
+}
+
+// SearchWithEmbeddings is Search but also loads each seed's embedding (e.g. for MMR re-ranking in Go).
+func (s *Store) SearchWithEmbeddings(ctx context.Context, queryEmbedding []float32, limit int, seedIDs []int64, appID, externalUserID string) ([]Seed, error) {
+	return s.search(ctx, queryEmbedding, limit, seedIDs, appID, externalUserID, true)
+}
+
+func (s *Store) search(ctx context.Context, queryEmbedding []float32, limit int, seedIDs []int64, appID, externalUserID string, withEmbedding bool) ([]Seed, error) {
 	if limit <= 0 {
 		limit = 10
 	}
@@ -148,7 +361,11 @@ func (s *Store) Search(ctx context.Context, queryEmbedding []float32, limit int,
 	var rows pgx.Rows
 	var err error
 
-	baseQuery := `SELECT id, content, metadata, created_at, app_id, external_user_id, 1 - (embedding <=> $1) AS score
+	baseQuery := `SELECT ` + seedColumns + `, 1 - (embedding <=> $1) AS score`
+	if withEmbedding {
+		baseQuery += `, embedding`
+	}
+	baseQuery += `
 				 FROM seeds 
 				 WHERE 1=1 `
 	args := []interface{}{vec, limit}
@@ -180,24 +397,39 @@ func (s *Store) Search(ctx context.Context, queryEmbedding []float32, limit int,
 	var seeds []Seed
 	for rows.Next() {
 		var se Seed
-		var createdAt time.Time
-		err := rows.Scan(&se.ID, &se.Content, &se.Metadata, &createdAt, &se.AppID, &se.ExternalUserID, &se.Score)
-		if err != nil {
+		if withEmbedding {
+			var emb pgvector.Vector
+			if err := scanSeed(rows, &se, &emb); err != nil {
+				return nil, err
+			}
+			se.Embedding = emb.Slice()
+		} else if err := scanSeed(rows, &se); err != nil {
 			return nil, err
 		}
-		se.CreatedAt = createdAt.Format(time.RFC3339)
 		seeds = append(seeds, se)
 	}
 	return seeds, rows.Err()
 }
 
+// RecordAccess bumps access_count and last_accessed_at for seeds returned to a client.
+func (s *Store) RecordAccess(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.pool.Exec(ctx,
+		`UPDATE seeds SET access_count = access_count + 1, last_accessed_at = NOW() WHERE id = ANY($1)`,
+		ids,
+	)
+	return err
+}
+
 // GetRecent returns the most recently created seeds, purely chronological, without vector search.
 func (s *Store) GetRecent(ctx context.Context, limit int, appID, externalUserID string) ([]Seed, error) {
 	if limit <= 0 {
 		limit = 10
 	}
 
-	baseQuery := `SELECT id, content, metadata, created_at, app_id, external_user_id, 0 AS score
+	baseQuery := `SELECT ` + seedColumns + `, 0 AS score
 				 FROM seeds 
 				 WHERE 1=1 `
 	args := []interface{}{limit}
@@ -223,12 +455,9 @@ func (s *Store) GetRecent(ctx context.Context, limit int, appID, externalUserID
 	var seeds []Seed
 	for rows.Next() {
 		var se Seed
-		var createdAt time.Time
-		err := rows.Scan(&se.ID, &se.Content, &se.Metadata, &createdAt, &se.AppID, &se.ExternalUserID, &se.Score)
-		if err != nil {
+		if err := scanSeed(rows, &se); err != nil {
 			return nil, err
 		}
-		se.CreatedAt = createdAt.Format(time.RFC3339)
 		seeds = append(seeds, se)
 	}
 	return seeds, rows.Err()
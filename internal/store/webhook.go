@@ -0,0 +1,263 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Webhook delivery states. A delivery stays pending until it succeeds or runs out of attempts (dead).
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead"
+)
+
+// ValidDeliveryStatus reports whether s is a known delivery state.
+func ValidDeliveryStatus(s string) bool {
+	return s == DeliveryPending || s == DeliverySucceeded || s == DeliveryDead
+}
+
+// Webhook is a URL that receives the events of a tenant (or of all tenants when no tenant is set).
+type Webhook struct {
+	ID             int64    `json:"id"`
+	URL            string   `json:"url"`
+	Secret         string   `json:"secret,omitempty"` // only returned when the webhook is created
+	EventTypes     []string `json:"eventTypes"`       // empty: all event types
+	Description    string   `json:"description,omitempty"`
+	Active         bool     `json:"active"`
+	AppID          string   `json:"appId,omitempty"`
+	ExternalUserID string   `json:"externalUserId,omitempty"`
+	AllTenants     bool     `json:"allTenants"` // no tenant: receives the events of every tenant
+	CreatedAt      string   `json:"createdAt"`
+}
+
+// WebhookDelivery is one queued event for one webhook.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhookId"`
+	EventID        int64           `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  string          `json:"nextAttemptAt,omitempty"` // pending only
+	LastStatusCode *int            `json:"lastStatusCode,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	CreatedAt      string          `json:"createdAt"`
+	DeliveredAt    string          `json:"deliveredAt,omitempty"`
+}
+
+// ClaimedDelivery is a delivery leased to a worker, together with the target it is sent to.
+type ClaimedDelivery struct {
+	ID        int64
+	EventID   int64
+	EventType string
+	Payload   json.RawMessage
+	Attempts  int // including the current attempt
+	URL       string
+	Secret    string
+}
+
+const webhookColumns = `id, url, secret, event_types, description, active, app_id, external_user_id, created_at`
+
+func scanWebhook(row pgx.Row) (Webhook, error) {
+	var w Webhook
+	var createdAt time.Time
+	err := row.Scan(&w.ID, &w.URL, &w.Secret, &w.EventTypes, &w.Description, &w.Active, &w.AppID, &w.ExternalUserID, &createdAt)
+	if err != nil {
+		return w, err
+	}
+	if w.EventTypes == nil {
+		w.EventTypes = []string{}
+	}
+	w.AllTenants = w.AppID == "" && w.ExternalUserID == ""
+	w.CreatedAt = createdAt.Format(time.RFC3339)
+	return w, nil
+}
+
+// InsertWebhook registers a webhook and returns its ID.
+func (s *Store) InsertWebhook(ctx context.Context, w Webhook) (int64, error) {
+	types := w.EventTypes
+	if types == nil {
+		types = []string{}
+	}
+	var id int64
+	err := s.pool.QueryRow(ctx,
+		`INSERT INTO webhooks (url, secret, event_types, description, active, app_id, external_user_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		w.URL, w.Secret, types, w.Description, w.Active, w.AppID, w.ExternalUserID,
+	).Scan(&id)
+	return id, err
+}
+
+// GetWebhook returns a single webhook including its secret, or nil if not found.
+func (s *Store) GetWebhook(ctx context.Context, id int64) (*Webhook, error) {
+	w, err := scanWebhook(s.pool.QueryRow(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// ListWebhooks returns the webhooks of a tenant, ordered by ID. Empty tenant fields are not filtered.
+func (s *Store) ListWebhooks(ctx context.Context, appID, externalUserID string) ([]Webhook, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks
+		 WHERE ($1 = '' OR app_id = $1) AND ($2 = '' OR external_user_id = $2) ORDER BY id`,
+		appID, externalUserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+// DeleteWebhook deletes a webhook and its deliveries. It returns pgx.ErrNoRows if the webhook does not exist.
+func (s *Store) DeleteWebhook(ctx context.Context, id int64) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the newest deliveries of a webhook, optionally only those in status.
+func (s *Store) ListWebhookDeliveries(ctx context.Context, webhookID int64, status string, limit int) ([]WebhookDelivery, error) {
+	baseQuery := `SELECT id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+		last_status_code, last_error, created_at, delivered_at
+		FROM webhook_deliveries WHERE webhook_id = $1`
+	args := []interface{}{webhookID}
+	argIdx := 2
+	if status != "" {
+		baseQuery += " AND status = $" + strconv.Itoa(argIdx)
+		args = append(args, status)
+		argIdx++
+	}
+	baseQuery += " ORDER BY id DESC LIMIT $" + strconv.Itoa(argIdx)
+	args = append(args, limit)
+
+	rows, err := s.pool.Query(ctx, baseQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []WebhookDelivery
+	for rows.Next() {
+		var d WebhookDelivery
+		var nextAttempt, createdAt time.Time
+		var deliveredAt *time.Time
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &nextAttempt,
+			&d.LastStatusCode, &d.LastError, &createdAt, &deliveredAt); err != nil {
+			return nil, err
+		}
+		if d.Status == DeliveryPending {
+			d.NextAttemptAt = nextAttempt.Format(time.RFC3339)
+		}
+		d.CreatedAt = createdAt.Format(time.RFC3339)
+		if deliveredAt != nil {
+			d.DeliveredAt = deliveredAt.Format(time.RFC3339)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// ClaimWebhookDeliveries leases up to limit due deliveries of active webhooks to the caller and counts the
+// attempt. The lease pushes next_attempt_at past the lease duration, so a delivery abandoned by a crashed
+// worker becomes due again on its own; SKIP LOCKED keeps concurrent workers on other instances apart.
+func (s *Store) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]ClaimedDelivery, error) {
+	rows, err := s.pool.Query(ctx,
+		`WITH due AS (
+			SELECT d.id FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= now() AND w.active
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1, next_attempt_at = now() + $2 * interval '1 second'
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING d.id, d.event_id, d.event_type, d.payload, d.attempts, w.url, w.secret`,
+		limit, lease.Seconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ClaimedDelivery
+	for rows.Next() {
+		var d ClaimedDelivery
+		if err := rows.Scan(&d.ID, &d.EventID, &d.EventType, &d.Payload, &d.Attempts, &d.URL, &d.Secret); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// CompleteWebhookDelivery marks a delivery as succeeded.
+func (s *Store) CompleteWebhookDelivery(ctx context.Context, id int64, statusCode int) error {
+	_, err := s.pool.Exec(ctx,
+		`UPDATE webhook_deliveries SET status = 'succeeded', last_status_code = $2, last_error = '', delivered_at = now()
+		 WHERE id = $1`,
+		id, statusCode,
+	)
+	return err
+}
+
+// FailWebhookDelivery records a failed attempt. The delivery is retried at retryAt, or moved to the dead
+// state when retryAt is nil. statusCode is nil when no HTTP response was received.
+func (s *Store) FailWebhookDelivery(ctx context.Context, id int64, statusCode *int, errMsg string, retryAt *time.Time) error {
+	_, err := s.pool.Exec(ctx,
+		`UPDATE webhook_deliveries SET
+			status = CASE WHEN $4::timestamptz IS NULL THEN 'dead' ELSE 'pending' END,
+			next_attempt_at = COALESCE($4, next_attempt_at),
+			last_status_code = $2, last_error = $3
+		 WHERE id = $1`,
+		id, statusCode, errMsg, retryAt,
+	)
+	return err
+}
+
+// RetryWebhookDelivery puts a dead delivery back into the queue with a fresh attempt budget.
+// It returns pgx.ErrNoRows if the webhook has no dead delivery with that ID.
+func (s *Store) RetryWebhookDelivery(ctx context.Context, webhookID, deliveryID int64) error {
+	tag, err := s.pool.Exec(ctx,
+		`UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = now()
+		 WHERE id = $1 AND webhook_id = $2 AND status = 'dead'`,
+		deliveryID, webhookID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// PurgeWebhookDeliveries deletes finished (succeeded or dead) deliveries created before the cutoff.
+func (s *Store) PurgeWebhookDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < $1`, before)
+	return tag.RowsAffected(), err
+}
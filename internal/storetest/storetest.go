@@ -0,0 +1,152 @@
+// Package storetest provides stores for tests.
+//
+// Open needs a scratch Postgres with pgvector in TEST_DATABASE_URL: it empties every table and applies the
+// migrations, so never point it at a database whose data you want to keep. Without the variable the
+// calling test is skipped. Offline returns a store that never reaches a database, for request validation
+// paths that fail before the first query.
+package storetest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cabroe/neural-brain/internal/model"
+	"github.com/cabroe/neural-brain/internal/store"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockKey serialises tests of different packages (go test runs them in parallel) on the shared database.
+const lockKey = 0x6e627374
+
+var modelOnce struct {
+	sync.Once
+	err error
+}
+
+// Open returns a store on TEST_DATABASE_URL with every table emptied (identities restarted, so ids are
+// predictable) and the migrations applied. The database is held exclusively until the test ends.
+func Open(t testing.TB) *store.Store {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+
+	lock, err := pgx.Connect(ctx, url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if _, err := lock.Exec(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		t.Fatalf("lock test database: %v", err)
+	}
+	t.Cleanup(func() { lock.Close(context.Background()) })
+
+	truncate(t, lock)
+	migrate(t, lock)
+
+	config, err := store.PoolConfig(url)
+	if err != nil {
+		t.Fatalf("pool config: %v", err)
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return store.NewStore(pool, 0.95)
+}
+
+// Offline returns a store whose pool points at an unreachable address. Pools connect lazily, so handlers
+// that reject a request before querying work normally; anything that does query fails with an error.
+func Offline(t testing.TB) *store.Store {
+	t.Helper()
+	config, err := pgxpool.ParseConfig("postgres://storetest@127.0.0.1:1/offline?connect_timeout=1")
+	if err != nil {
+		t.Fatalf("pool config: %v", err)
+	}
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		t.Fatalf("pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return store.NewStore(pool, 0.95)
+}
+
+// LoadModel loads the embedding model from GTE_MODEL_PATH once per test binary and skips the test when the
+// variable is not set.
+func LoadModel(t testing.TB) {
+	t.Helper()
+	path := os.Getenv("GTE_MODEL_PATH")
+	if path == "" {
+		t.Skip("GTE_MODEL_PATH not set")
+	}
+	modelOnce.Do(func() { _, modelOnce.err = model.LoadModel(path) })
+	if modelOnce.err != nil {
+		t.Fatalf("load model: %v", modelOnce.err)
+	}
+}
+
+// migrate applies migrations/*.sql in order, like the server does at startup.
+func migrate(t testing.TB, conn *pgx.Conn) {
+	t.Helper()
+	dir := filepath.Join(moduleRoot(t), "migrations")
+	names, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil || len(names) == 0 {
+		t.Fatalf("no migrations in %s", dir)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sql, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if _, err := conn.Exec(context.Background(), string(sql)); err != nil {
+			t.Fatalf("migrate %s: %v", filepath.Base(name), err)
+		}
+	}
+}
+
+func truncate(t testing.TB, conn *pgx.Conn) {
+	t.Helper()
+	ctx := context.Background()
+	rows, err := conn.Query(ctx, "SELECT quote_ident(tablename) FROM pg_tables WHERE schemaname = current_schema()")
+	if err != nil {
+		t.Fatalf("list tables: %v", err)
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		t.Fatalf("list tables: %v", err)
+	}
+	if len(tables) == 0 {
+		return
+	}
+	if _, err := conn.Exec(ctx, "TRUNCATE "+strings.Join(tables, ", ")+" RESTART IDENTITY CASCADE"); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+}
+
+// moduleRoot walks up from the package directory to the directory holding go.mod.
+func moduleRoot(t testing.TB) string {
+	t.Helper()
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			t.Fatal("go.mod not found")
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenTarget is returned for webhook URLs that point into the server's own network.
+var ErrForbiddenTarget = errors.New("webhook target is a loopback, private, link-local or otherwise internal address")
+
+// sharedAddressSpace is 100.64.0.0/10 (carrier-grade NAT), which netip does not count as private.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// publicAddr reports whether a webhook may be delivered to ip. Loopback, private, link-local (including
+// the 169.254.169.254 cloud metadata endpoint), unspecified and multicast addresses are refused.
+func publicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsValid() && !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() &&
+		!ip.IsUnspecified() && !sharedAddressSpace.Contains(ip) && !(ip.Is4() && ip.As4()[0] == 0)
+}
+
+// CheckURL validates a webhook URL at registration: it must be absolute http(s) and, unless allowPrivate,
+// every address its host resolves to must be public. Delivery checks the dialled address again, so a DNS
+// answer that changes later does not get around this.
+func CheckURL(ctx context.Context, raw string, allowPrivate bool) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("url must be an absolute http(s) URL")
+	}
+	if allowPrivate {
+		return nil
+	}
+	if ip, err := netip.ParseAddr(u.Hostname()); err == nil {
+		if !publicAddr(ip) {
+			return ErrForbiddenTarget
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("url host cannot be resolved: %w", err)
+	}
+	for _, ip := range addrs {
+		if !publicAddr(ip) {
+			return ErrForbiddenTarget
+		}
+	}
+	return nil
+}
+
+// NewClient returns the HTTP client used for deliveries. It does not follow redirects (a 3xx counts as a
+// failed attempt), ignores proxy settings and, unless allowPrivate, refuses to connect to non-public
+// addresses.
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !publicAddr(ap.Addr()) {
+				return ErrForbiddenTarget
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,184 @@
+// Package webhook delivers queued events to registered webhook URLs.
+//
+// Deliveries are queued in Postgres by a trigger on the events table (migration 016). Workers on every
+// server instance lease due deliveries with SKIP LOCKED, POST them with an HMAC signature and schedule
+// retries with exponential backoff; after MaxAttempts failures a delivery is dead and only comes back
+// through POST /webhooks/{id}/deliveries/{deliveryId}/retry.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cabroe/neural-brain/internal/store"
+)
+
+// Request headers sent with every delivery.
+const (
+	SignatureHeader = "X-Neural-Brain-Signature" // t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">
+	EventHeader     = "X-Neural-Brain-Event"
+	DeliveryHeader  = "X-Neural-Brain-Delivery"
+)
+
+const (
+	defaultInterval    = 2 * time.Second
+	defaultBatch       = 20
+	defaultMaxAttempts = 10
+	defaultTimeout     = 10 * time.Second
+	retryBase          = 30 * time.Second
+	retryMax           = 6 * time.Hour
+	maxErrorBody       = 256
+)
+
+// Sign returns the signature header value for body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Worker sends due deliveries. The zero values of the optional fields select the defaults.
+type Worker struct {
+	Store        *store.Store
+	Client       *http.Client  // default: NewClient with a 10s timeout
+	AllowPrivate bool          // let the default client reach loopback and private addresses
+	Interval     time.Duration // poll interval, default 2s
+	Batch        int           // deliveries leased per poll, default 20
+	MaxAttempts  int           // attempts before a delivery is dead, default 10
+}
+
+// Run polls for due deliveries until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	client := w.Client
+	if client == nil {
+		client = NewClient(defaultTimeout, w.AllowPrivate)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// Keep draining while full batches come back.
+		for w.poll(ctx, client) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll sends one batch and reports whether the batch was full.
+func (w *Worker) poll(ctx context.Context, client *http.Client) bool {
+	batch := w.Batch
+	if batch <= 0 {
+		batch = defaultBatch
+	}
+	// The lease must outlast the request so no other worker picks the delivery up meanwhile.
+	lease := client.Timeout + time.Minute
+	deliveries, err := w.Store.ClaimWebhookDeliveries(ctx, batch, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("webhook: claim: %v", err)
+		}
+		return false
+	}
+	var wg sync.WaitGroup
+	for _, d := range deliveries {
+		wg.Add(1)
+		go func(d store.ClaimedDelivery) {
+			defer wg.Done()
+			w.deliver(ctx, client, d)
+		}(d)
+	}
+	wg.Wait()
+	return len(deliveries) == batch && ctx.Err() == nil
+}
+
+func (w *Worker) deliver(ctx context.Context, client *http.Client, d store.ClaimedDelivery) {
+	statusCode, err := send(ctx, client, d)
+	if ctx.Err() != nil {
+		// Shutting down; the lease expires and another attempt follows.
+		return
+	}
+	if err == nil {
+		if err := w.Store.CompleteWebhookDelivery(context.Background(), d.ID, statusCode); err != nil {
+			log.Printf("webhook: delivery %d: %v", d.ID, err)
+		}
+		return
+	}
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+	maxAttempts := w.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	var retryAt *time.Time
+	if d.Attempts < maxAttempts {
+		t := time.Now().Add(retryDelay(d.Attempts))
+		retryAt = &t
+	} else {
+		log.Printf("webhook: delivery %d to %s is dead after %d attempts: %v", d.ID, d.URL, d.Attempts, err)
+	}
+	if err := w.Store.FailWebhookDelivery(context.Background(), d.ID, code, err.Error(), retryAt); err != nil {
+		log.Printf("webhook: delivery %d: %v", d.ID, err)
+	}
+}
+
+// send POSTs the delivery and returns the response status (0 if none) and an error unless it was 2xx.
+func send(ctx context.Context, client *http.Client, d store.ClaimedDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "neural-brain-webhook/1")
+	req.Header.Set(SignatureHeader, Sign(d.Secret, time.Now(), d.Payload))
+	req.Header.Set(EventHeader, d.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(d.ID, 10))
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+	msg := strings.TrimSpace(string(body))
+	if msg == "" {
+		return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, fmt.Errorf("HTTP %d: %s", resp.StatusCode, msg)
+}
+
+// retryDelay is the wait after the given number of failed attempts: 30s, 1m, 2m, … capped at 6h.
+func retryDelay(attempts int) time.Duration {
+	d := retryBase
+	for i := 1; i < attempts && d < retryMax; i++ {
+		d *= 2
+	}
+	if d > retryMax {
+		d = retryMax
+	}
+	return d
+}
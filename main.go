@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -19,9 +20,18 @@ import (
 	"io/fs"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
 	"github.com/cabroe/neural-brain/internal/api/handler"
+	"github.com/cabroe/neural-brain/internal/capture"
+	"github.com/cabroe/neural-brain/internal/entity"
+	"github.com/cabroe/neural-brain/internal/events"
+	"github.com/cabroe/neural-brain/internal/grpcapi"
+	"github.com/cabroe/neural-brain/internal/jobs"
+	"github.com/cabroe/neural-brain/internal/learning"
+	"github.com/cabroe/neural-brain/internal/mcp"
 	"github.com/cabroe/neural-brain/internal/model"
 	"github.com/cabroe/neural-brain/internal/store"
+	"github.com/cabroe/neural-brain/internal/webhook"
 )
 
 //go:embed backend/dist
@@ -38,16 +48,35 @@ func (w *responseWriter) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer (flushing, write deadlines for SSE).
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 type Config struct {
-	URL            string  `json:"url"`
-	AgentID        string  `json:"agent_id"`
-	ExternalUserID string  `json:"external_user_id"`
-	AutoRecall     bool    `json:"auto_recall"`
-	AutoCapture    bool    `json:"auto_capture"`
-	DatabaseURL    string  `json:"database_url"`
-	GTEModelPath   string  `json:"gte_model_path"`
-	Port           string  `json:"port"`
-	DedupThreshold float64 `json:"dedup_threshold"`
+	URL               string  `json:"url"`
+	AgentID           string  `json:"agent_id"`
+	ExternalUserID    string  `json:"external_user_id"`
+	AutoRecall        bool    `json:"auto_recall"`
+	AutoCapture       bool    `json:"auto_capture"`
+	DatabaseURL       string  `json:"database_url"`
+	GTEModelPath      string  `json:"gte_model_path"`
+	Port              string  `json:"port"`
+	DedupThreshold    float64 `json:"dedup_threshold"`
+	RankHalfLifeHours float64 `json:"rank_half_life_hours"`
+	CaptureLLMURL     string  `json:"capture_llm_url"`
+	CaptureLLMModel   string  `json:"capture_llm_model"`
+	MetricsSampleSecs int     `json:"metrics_sample_seconds"`
+	EntityGazetteer   string  `json:"entity_gazetteer"`
+	GRPCPort          string  `json:"grpc_port"`
+	// IdempotencyWindowHours is how long Idempotency-Key responses are replayed (default 24).
+	IdempotencyWindowHours float64 `json:"idempotency_window_hours"`
+	// WebhookAllowPrivate lets webhooks target loopback and private addresses (local development).
+	WebhookAllowPrivate bool `json:"webhook_allow_private"`
+	// WorkingContextTTLHours lets the reaper delete working contexts older than this; 0 keeps them.
+	WorkingContextTTLHours float64 `json:"working_context_ttl_hours"`
+	// Jobs maps a built-in job name (purge, reaper, stats) to a cron expression; "off" leaves it manual-only.
+	Jobs map[string]string `json:"jobs"`
 }
 
 func loadJSONConfig() *Config {
@@ -95,6 +124,15 @@ func main() {
 		port = "9124"
 	}
 
+	// gRPC is served on its own port only when one is configured; it has no authentication of its own.
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" && cfg != nil {
+		grpcPort = cfg.GRPCPort
+	}
+	if grpcPort == "off" {
+		grpcPort = ""
+	}
+
 	databaseURL := os.Getenv("DATABASE_URL")
 	if databaseURL == "" && cfg != nil {
 		databaseURL = cfg.DatabaseURL
@@ -112,6 +150,94 @@ func main() {
 		dedupThreshold = cfg.DedupThreshold
 	}
 
+	rankHalfLife := time.Duration(0)
+	if s := os.Getenv("RANK_HALF_LIFE_HOURS"); s != "" {
+		if v, err := strconv.ParseFloat(s, 64); err == nil && v > 0 {
+			rankHalfLife = time.Duration(v * float64(time.Hour))
+		}
+	} else if cfg != nil && cfg.RankHalfLifeHours > 0 {
+		rankHalfLife = time.Duration(cfg.RankHalfLifeHours * float64(time.Hour))
+	}
+
+	idempotencyWindow := 24 * time.Hour
+	if s := os.Getenv("IDEMPOTENCY_WINDOW"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			log.Fatalf("invalid IDEMPOTENCY_WINDOW %q: want a positive duration such as 24h", s)
+		}
+		idempotencyWindow = d
+	} else if cfg != nil && cfg.IdempotencyWindowHours > 0 {
+		idempotencyWindow = time.Duration(cfg.IdempotencyWindowHours * float64(time.Hour))
+	}
+
+	webhookAllowPrivate := cfg != nil && cfg.WebhookAllowPrivate
+	if s := os.Getenv("WEBHOOK_ALLOW_PRIVATE"); s != "" {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			log.Fatalf("invalid WEBHOOK_ALLOW_PRIVATE %q", s)
+		}
+		webhookAllowPrivate = v
+	}
+
+	var workingTTL time.Duration
+	if s := os.Getenv("WORKING_CONTEXT_TTL"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			log.Fatalf("invalid WORKING_CONTEXT_TTL %q", s)
+		}
+		workingTTL = d
+	} else if cfg != nil && cfg.WorkingContextTTLHours > 0 {
+		workingTTL = time.Duration(cfg.WorkingContextTTLHours * float64(time.Hour))
+	}
+
+	captureLLMURL := os.Getenv("CAPTURE_LLM_URL")
+	captureLLMModel := os.Getenv("CAPTURE_LLM_MODEL")
+	if captureLLMURL == "" && cfg != nil {
+		captureLLMURL = cfg.CaptureLLMURL
+		captureLLMModel = cfg.CaptureLLMModel
+	}
+	jobSchedules := make(map[string]string)
+	for name, spec := range jobs.DefaultSchedules {
+		jobSchedules[name] = spec
+	}
+	if s := os.Getenv("METRICS_SAMPLE_INTERVAL"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+			jobSchedules["stats"] = everySpec(d)
+		}
+	} else if cfg != nil && cfg.MetricsSampleSecs != 0 {
+		jobSchedules["stats"] = everySpec(time.Duration(cfg.MetricsSampleSecs) * time.Second)
+	}
+	if cfg != nil {
+		for name, spec := range cfg.Jobs {
+			jobSchedules[name] = spec
+		}
+	}
+	// JOB_SCHEDULES="purge=30 3 * * *;stats=@every 10m"
+	for _, pair := range strings.Split(os.Getenv("JOB_SCHEDULES"), ";") {
+		if name, spec, ok := strings.Cut(pair, "="); ok {
+			jobSchedules[strings.TrimSpace(name)] = strings.TrimSpace(spec)
+		}
+	}
+
+	gazetteer := entity.NewGazetteer()
+	gazetteerPath := os.Getenv("ENTITY_GAZETTEER")
+	if gazetteerPath == "" && cfg != nil {
+		gazetteerPath = cfg.EntityGazetteer
+	}
+	if gazetteerPath != "" {
+		n, err := gazetteer.LoadFile(gazetteerPath)
+		if err != nil {
+			log.Fatalf("load entity gazetteer: %v", err)
+		}
+		log.Printf("entities: loaded %d gazetteer entries from %s", n, gazetteerPath)
+	}
+
+	var extractor capture.Extractor = capture.Heuristic{}
+	if captureLLMURL != "" {
+		extractor = capture.Fallback{Primary: capture.NewLLM(captureLLMURL, captureLLMModel), Secondary: capture.Heuristic{}}
+		log.Printf("auto-capture: using LLM extractor at %s", captureLLMURL)
+	}
+
 	if _, err := model.LoadModel(modelPath); err != nil {
 		log.Fatalf("load model: %v", err)
 	}
@@ -189,25 +315,150 @@ func main() {
 	}
 
 	s := store.NewStore(pool, dedupThreshold)
+	s.SetRecencyHalfLife(rankHalfLife)
+	if _, err := s.LoadKnownEntities(context.Background()); err != nil {
+		log.Fatalf("load entities: %v", err)
+	}
+	s.SetEntityExtractor(entity.Multi{gazetteer, entity.Rules{}})
+
+	mcpServer := mcp.NewServer(s, model.Embed)
+	if len(os.Args) > 1 && os.Args[1] == "mcp" {
+		// MCP over stdio: stdout carries the protocol, logs stay on stderr. No HTTP server or scheduler.
+		sess := &mcp.Session{
+			AppID:          os.Getenv("NEURAL_BRAIN_AGENT_ID"),
+			ExternalUserID: os.Getenv("NEURAL_BRAIN_EXTERNAL_USER_ID"),
+		}
+		if sess.AppID == "" && cfg != nil {
+			sess.AppID = cfg.AgentID
+		}
+		if sess.ExternalUserID == "" && cfg != nil {
+			sess.ExternalUserID = cfg.ExternalUserID
+		}
+		log.Printf("mcp: serving on stdio (appId=%q externalUserId=%q)", sess.AppID, sess.ExternalUserID)
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		err := mcpServer.ServeStdio(ctx, sess, os.Stdin, os.Stdout)
+		stop()
+		if err != nil {
+			log.Printf("mcp: %v", err)
+		}
+		return
+	}
+
+	learningEngine := &learning.Engine{Store: s, Embed: model.Embed}
+	scheduler := jobs.NewScheduler(s)
+	for _, job := range []jobs.Job{jobs.Purge{Store: s}, jobs.Reaper{Store: s, WorkingTTL: workingTTL}, jobs.Stats{Store: s}, jobs.Learning{Engine: learningEngine}} {
+		spec := jobSchedules[job.Name()]
+		if spec == "off" {
+			spec = ""
+		}
+		if err := scheduler.Register(job, spec); err != nil {
+			log.Fatalf("register job: %v", err)
+		}
+	}
+	for name := range jobSchedules {
+		if !scheduler.Has(name) {
+			log.Printf("warning: schedule for unknown job %q ignored", name)
+		}
+	}
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	go func() {
+		scheduler.Run(schedulerCtx)
+		close(schedulerDone)
+	}()
+
+	// Change feed for GET /events: one LISTEN connection per instance, fanned out to local subscribers.
+	hub := events.NewHub(s)
+	hubCtx, stopHub := context.WithCancel(context.Background())
+	hubDone := make(chan struct{})
+	go func() {
+		hub.Run(hubCtx)
+		close(hubDone)
+	}()
+
+	// Webhook deliveries are queued in Postgres; every instance works the queue.
+	webhookCtx, stopWebhooks := context.WithCancel(context.Background())
+	webhooksDone := make(chan struct{})
+	go func() {
+		(&webhook.Worker{Store: s, AllowPrivate: webhookAllowPrivate}).Run(webhookCtx)
+		close(webhooksDone)
+	}()
+
 	mux := http.NewServeMux()
-	mux.HandleFunc("POST /seeds", handler.HandleStoreSeed(s))
+	mux.HandleFunc("POST /seeds", handler.Idempotent(s, idempotencyWindow, handler.MaxSeedBodyBytes, handler.HandleStoreSeed(s)))
+	mux.HandleFunc("POST /seeds/batch", handler.Idempotent(s, idempotencyWindow, handler.MaxSeedBodyBytes, handler.HandleStoreSeedsBatch(s)))
 	mux.HandleFunc("POST /seeds/query", handler.HandleSeedsQuery(s))
 	mux.HandleFunc("PATCH /seeds/{id}/metadata", handler.HandleUpdateSeedMetadata(s))
 	mux.HandleFunc("POST /seeds/{id}/tags", handler.HandleUpdateSeedTags(s))
 	mux.HandleFunc("GET /seeds/{id}", handler.HandleGetSeed(s))
 	mux.HandleFunc("PUT /seeds/{id}", handler.HandleUpdateSeed(s))
+	mux.HandleFunc("DELETE /seeds/{id}", handler.HandleDeleteSeed(s))
+	mux.HandleFunc("POST /seeds/{id}/edges", handler.HandleCreateEdge(s))
+	mux.HandleFunc("GET /seeds/{id}/edges", handler.HandleListEdges(s))
+	mux.HandleFunc("DELETE /seeds/{id}/edges", handler.HandleDeleteEdges(s))
+	mux.HandleFunc("GET /seeds/{id}/neighbors", handler.HandleNeighbors(s))
 	mux.HandleFunc("GET /search", handler.HandleSearch(s))
 	mux.HandleFunc("GET /seeds/recent", handler.HandleGetRecent(s))
+	mux.HandleFunc("POST /recall", handler.HandleRecall(s))
+	mux.HandleFunc("POST /capture", handler.HandleCapture(s, extractor))
+	mux.HandleFunc("POST /documents", handler.HandleCreateDocument(s))
+	mux.HandleFunc("GET /documents", handler.HandleListDocuments(s))
+	mux.HandleFunc("GET /documents/{id}", handler.HandleGetDocument(s))
+	mux.HandleFunc("POST /goals", handler.HandleCreateGoal(s))
+	mux.HandleFunc("POST /entities", handler.HandleCreateEntity(s))
+	mux.HandleFunc("GET /entities", handler.HandleListEntities(s))
+	mux.HandleFunc("GET /entities/{id}", handler.HandleGetEntity(s))
+	mux.HandleFunc("GET /entities/{id}/seeds", handler.HandleEntitySeeds(s))
+	mux.HandleFunc("POST /clusters/compute", handler.HandleComputeClusters(s))
+	mux.HandleFunc("GET /clusters", handler.HandleListClusters(s))
+	mux.HandleFunc("GET /clusters/{id}/seeds", handler.HandleClusterSeeds(s))
+	mux.HandleFunc("GET /duplicates", handler.HandleFindDuplicates(s))
+	mux.HandleFunc("POST /duplicates/merge", handler.HandleMergeDuplicates(s))
+	mux.HandleFunc("GET /goals", handler.HandleListGoals(s))
+	mux.HandleFunc("GET /goals/tree", handler.HandleGoalTree(s))
+	mux.HandleFunc("POST /goals/evaluate", handler.HandleEvaluateGoals(s))
+	mux.HandleFunc("GET /goals/{id}", handler.HandleGetGoal(s))
+	mux.HandleFunc("PATCH /goals/{id}", handler.HandleUpdateGoal(s))
+	mux.HandleFunc("GET /agents/{id}/emotion", handler.HandleGetEmotion(s))
+	mux.HandleFunc("PUT /agents/{id}/emotion", handler.HandleSetEmotion(s))
+	mux.HandleFunc("POST /agents/{id}/emotion/events", handler.HandleEmotionEvent(s))
+	mux.HandleFunc("GET /agents/{id}/emotion/history", handler.HandleEmotionHistory(s))
+	mux.HandleFunc("GET /reflection/digest", handler.HandleReflectionDigest(s))
+	mux.HandleFunc("POST /beliefs", handler.HandleCommitBelief(s))
+	mux.HandleFunc("GET /beliefs", handler.HandleListBeliefs(s))
+	mux.HandleFunc("POST /metrics/points", handler.HandleInsertMetricPoints(s))
+	mux.HandleFunc("GET /metrics/series", handler.HandleMetricSeries(s))
+	mux.HandleFunc("GET /metrics", handler.HandleMetricNames(s))
+	mux.HandleFunc("POST /learning/rules", handler.HandleCreateLearningRule(s))
+	mux.HandleFunc("GET /learning/rules", handler.HandleListLearningRules(s))
+	mux.HandleFunc("GET /learning/rules/{id}", handler.HandleGetLearningRule(s))
+	mux.HandleFunc("PUT /learning/rules/{id}", handler.HandleUpdateLearningRule(s))
+	mux.HandleFunc("DELETE /learning/rules/{id}", handler.HandleDeleteLearningRule(s))
+	mux.HandleFunc("POST /learning/evaluate", handler.HandleEvaluateLearning(s, learningEngine))
+	mux.HandleFunc("GET /learning/evaluations", handler.HandleListLearningEvaluations(s))
+	mux.HandleFunc("GET /jobs", handler.HandleListJobs(scheduler))
+	mux.HandleFunc("POST /jobs/{name}/run", handler.HandleRunJob(scheduler))
+	mux.HandleFunc("GET /jobs/{name}/runs", handler.HandleListJobRuns(s, scheduler))
 	mux.HandleFunc("GET /health", handler.HandleHealth(pool))
-	mux.HandleFunc("POST /agent-contexts", handler.HandleCreateContext(s))
+	mux.HandleFunc("POST /agent-contexts", handler.Idempotent(s, idempotencyWindow, handler.MaxContextBodyBytes, handler.HandleCreateContext(s)))
 	mux.HandleFunc("GET /agent-contexts", handler.HandleListContexts(s))
 	mux.HandleFunc("GET /agent-contexts/{id}", handler.HandleGetContext(s))
 	// Aliases for Neutron compatibility
-	mux.HandleFunc("POST /contexts", handler.HandleCreateContext(s))
+	mux.HandleFunc("POST /contexts", handler.Idempotent(s, idempotencyWindow, handler.MaxContextBodyBytes, handler.HandleCreateContext(s)))
 	mux.HandleFunc("GET /contexts", handler.HandleListContexts(s))
 	mux.HandleFunc("GET /contexts/{id}", handler.HandleGetContext(s))
 
 	mux.HandleFunc("GET /stats", handler.HandleGetStats(s))
+	mux.HandleFunc("GET /events", handler.HandleEvents(s, hub))
+	mux.HandleFunc("POST /webhooks", handler.HandleCreateWebhook(s, webhookAllowPrivate))
+	mux.HandleFunc("GET /webhooks", handler.HandleListWebhooks(s))
+	mux.HandleFunc("GET /webhooks/{id}", handler.HandleGetWebhook(s))
+	mux.HandleFunc("DELETE /webhooks/{id}", handler.HandleDeleteWebhook(s))
+	mux.HandleFunc("GET /webhooks/{id}/deliveries", handler.HandleListWebhookDeliveries(s))
+	mux.HandleFunc("POST /webhooks/{id}/deliveries/{deliveryId}/retry", handler.HandleRetryWebhookDelivery(s))
+	mux.Handle("/mcp", mcp.NewHTTPHandler(mcpServer))
+	mux.HandleFunc("GET /openapi.json", handler.HandleOpenAPI())
+	mux.HandleFunc("GET /docs", handler.HandleDocs())
 
 	distFS, err := fs.Sub(webDist, "backend/dist")
 	if err != nil {
@@ -250,8 +501,9 @@ func main() {
 	corsHandler := func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, If-None-Match, Last-Event-ID, "+handler.IdempotencyKeyHeader+", "+mcp.SessionHeader)
+			w.Header().Set("Access-Control-Expose-Headers", "ETag, "+handler.IdempotentReplayedHeader+", "+mcp.SessionHeader)
 			if r.Method == http.MethodOptions {
 				w.WriteHeader(http.StatusNoContent)
 				return
@@ -282,14 +534,56 @@ func main() {
 		}
 	}()
 
+	var grpcSrv *grpc.Server
+	if grpcPort != "" {
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			log.Fatalf("grpc listen: %v", err)
+		}
+		grpcSrv = grpcapi.NewServer(s, model.Embed)
+		go func() {
+			log.Printf("grpc listening on :%s", grpcPort)
+			if err := grpcSrv.Serve(lis); err != nil {
+				log.Fatalf("grpc server: %v", err)
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("shutting down...")
+	stopScheduler()
+	// Closing the hub ends open event streams, which would otherwise hold up Shutdown.
+	stopHub()
+	<-hubDone
+	stopWebhooks()
+	<-webhooksDone
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Printf("shutdown: %v", err)
 	}
+	if grpcSrv != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcSrv.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcSrv.Stop()
+		}
+	}
+	<-schedulerDone
 	log.Println("bye")
 }
+
+// everySpec turns an interval into a job schedule; 0 disables scheduled runs.
+func everySpec(d time.Duration) string {
+	if d <= 0 {
+		return "off"
+	}
+	return "@every " + d.String()
+}
@@ -0,0 +1,3 @@
+-- Access tracking for recency/frequency-aware ranking
+ALTER TABLE seeds ADD COLUMN IF NOT EXISTS access_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE seeds ADD COLUMN IF NOT EXISTS last_accessed_at TIMESTAMPTZ;
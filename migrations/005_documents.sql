@@ -0,0 +1,22 @@
+-- Long documents: parent rows plus the chunk seeds they were split into
+CREATE TABLE IF NOT EXISTS documents (
+  id               BIGSERIAL PRIMARY KEY,
+  title            TEXT NOT NULL DEFAULT '',
+  content          TEXT NOT NULL,
+  mime_type        TEXT NOT NULL DEFAULT 'text/plain',
+  metadata         JSONB DEFAULT '{}',
+  app_id           TEXT,
+  external_user_id TEXT,
+  created_at       TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_documents_multi_tenancy ON documents(app_id, external_user_id);
+
+CREATE TABLE IF NOT EXISTS document_chunks (
+  document_id  BIGINT NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+  chunk_index  INT NOT NULL,
+  seed_id      BIGINT NOT NULL UNIQUE REFERENCES seeds(id) ON DELETE CASCADE,
+  start_offset INT NOT NULL,
+  end_offset   INT NOT NULL,
+  PRIMARY KEY (document_id, chunk_index)
+);
@@ -0,0 +1,19 @@
+-- Goal hierarchy: native goals with parent/child relations, lifecycle, priority and deadlines
+CREATE TABLE IF NOT EXISTS goals (
+  id               BIGSERIAL PRIMARY KEY,
+  parent_id        BIGINT REFERENCES goals(id) ON DELETE CASCADE,
+  title            TEXT NOT NULL,
+  description      TEXT NOT NULL DEFAULT '',
+  status           TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'completed', 'abandoned')),
+  priority         INT NOT NULL DEFAULT 0,
+  deadline         TIMESTAMPTZ,
+  embedding        vector(384) NOT NULL,
+  app_id           TEXT,
+  external_user_id TEXT,
+  created_at       TIMESTAMPTZ DEFAULT now(),
+  updated_at       TIMESTAMPTZ DEFAULT now(),
+  completed_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_goals_parent ON goals(parent_id);
+CREATE INDEX IF NOT EXISTS idx_goals_multi_tenancy ON goals(app_id, external_user_id, status);
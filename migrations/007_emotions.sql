@@ -0,0 +1,34 @@
+-- Affect state (valence/arousal/dominance) per agent with decay parameters, plus an event log for charting
+CREATE TABLE IF NOT EXISTS agent_emotions (
+  agent_id           TEXT NOT NULL,
+  app_id             TEXT NOT NULL DEFAULT '',
+  external_user_id   TEXT NOT NULL DEFAULT '',
+  valence            DOUBLE PRECISION NOT NULL,
+  arousal            DOUBLE PRECISION NOT NULL,
+  dominance          DOUBLE PRECISION NOT NULL,
+  baseline_valence   DOUBLE PRECISION NOT NULL DEFAULT 5,
+  baseline_arousal   DOUBLE PRECISION NOT NULL DEFAULT 5,
+  baseline_dominance DOUBLE PRECISION NOT NULL DEFAULT 5,
+  half_life_seconds  DOUBLE PRECISION NOT NULL DEFAULT 21600,
+  reason             TEXT NOT NULL DEFAULT '',
+  updated_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (agent_id, app_id, external_user_id)
+);
+
+CREATE TABLE IF NOT EXISTS agent_emotion_events (
+  id               BIGSERIAL PRIMARY KEY,
+  agent_id         TEXT NOT NULL,
+  app_id           TEXT NOT NULL DEFAULT '',
+  external_user_id TEXT NOT NULL DEFAULT '',
+  kind             TEXT NOT NULL CHECK (kind IN ('set', 'event')),
+  delta_valence    DOUBLE PRECISION NOT NULL DEFAULT 0,
+  delta_arousal    DOUBLE PRECISION NOT NULL DEFAULT 0,
+  delta_dominance  DOUBLE PRECISION NOT NULL DEFAULT 0,
+  valence          DOUBLE PRECISION NOT NULL,
+  arousal          DOUBLE PRECISION NOT NULL,
+  dominance        DOUBLE PRECISION NOT NULL,
+  reason           TEXT NOT NULL DEFAULT '',
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_emotion_events_agent ON agent_emotion_events(agent_id, app_id, external_user_id, created_at);
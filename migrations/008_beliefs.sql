@@ -0,0 +1,18 @@
+-- Beliefs: insights committed by self-reflection, with provenance and revision state
+CREATE TABLE IF NOT EXISTS beliefs (
+  id               BIGSERIAL PRIMARY KEY,
+  content          TEXT NOT NULL,
+  embedding        vector(384) NOT NULL,
+  confidence       DOUBLE PRECISION NOT NULL CHECK (confidence >= 0 AND confidence <= 1),
+  importance       DOUBLE PRECISION NOT NULL DEFAULT 5,
+  source_seed_ids  BIGINT[] NOT NULL DEFAULT '{}',
+  status           TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'superseded')),
+  superseded_by    BIGINT REFERENCES beliefs(id) ON DELETE SET NULL,
+  seed_id          BIGINT REFERENCES seeds(id) ON DELETE SET NULL,
+  app_id           TEXT,
+  external_user_id TEXT,
+  created_at       TIMESTAMPTZ DEFAULT now(),
+  updated_at       TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_beliefs_multi_tenancy ON beliefs(app_id, external_user_id, status);
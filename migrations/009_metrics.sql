@@ -0,0 +1,12 @@
+-- Time-series metric points (kept out of the seeds table so they do not pollute semantic search)
+CREATE TABLE IF NOT EXISTS metric_points (
+  id               BIGSERIAL PRIMARY KEY,
+  name             TEXT NOT NULL,
+  value            DOUBLE PRECISION NOT NULL,
+  labels           JSONB NOT NULL DEFAULT '{}',
+  app_id           TEXT NOT NULL DEFAULT '',
+  external_user_id TEXT NOT NULL DEFAULT '',
+  recorded_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_metric_points_series ON metric_points(name, app_id, external_user_id, recorded_at);
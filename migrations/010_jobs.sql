@@ -0,0 +1,13 @@
+-- Run history for in-process scheduled jobs
+CREATE TABLE IF NOT EXISTS job_runs (
+  id          BIGSERIAL PRIMARY KEY,
+  name        TEXT NOT NULL,
+  trigger     TEXT NOT NULL CHECK (trigger IN ('schedule', 'manual')),
+  status      TEXT NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed')),
+  output      TEXT NOT NULL DEFAULT '',
+  error       TEXT NOT NULL DEFAULT '',
+  started_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_name_started ON job_runs(name, started_at DESC);
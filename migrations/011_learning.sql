@@ -0,0 +1,32 @@
+-- Auto-learning rules (semantic prototype or regex) and their evaluation log
+CREATE TABLE IF NOT EXISTS learning_rules (
+  id               BIGSERIAL PRIMARY KEY,
+  name             TEXT NOT NULL UNIQUE,
+  kind             TEXT NOT NULL CHECK (kind IN ('semantic', 'lexical')),
+  pattern          TEXT NOT NULL,
+  embedding        vector(384),
+  threshold        DOUBLE PRECISION NOT NULL DEFAULT 0.8,
+  window_hours     DOUBLE PRECISION NOT NULL DEFAULT 24,
+  min_count        INTEGER NOT NULL DEFAULT 5,
+  action           TEXT NOT NULL CHECK (action IN ('learning_seed', 'tag', 'emotion')),
+  action_params    JSONB NOT NULL DEFAULT '{}',
+  app_id           TEXT NOT NULL DEFAULT '',
+  external_user_id TEXT NOT NULL DEFAULT '',
+  enabled          BOOLEAN NOT NULL DEFAULT true,
+  last_fired_at    TIMESTAMPTZ,
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS learning_evaluations (
+  id               BIGSERIAL PRIMARY KEY,
+  rule_id          BIGINT NOT NULL REFERENCES learning_rules(id) ON DELETE CASCADE,
+  match_count      INTEGER NOT NULL,
+  matched_seed_ids BIGINT[] NOT NULL DEFAULT '{}',
+  fired            BOOLEAN NOT NULL,
+  result           TEXT NOT NULL DEFAULT '',
+  error            TEXT NOT NULL DEFAULT '',
+  evaluated_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_learning_evaluations_rule ON learning_evaluations(rule_id, evaluated_at DESC);
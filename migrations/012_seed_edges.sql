@@ -0,0 +1,13 @@
+-- Typed, directed, weighted relations between seeds
+CREATE TABLE IF NOT EXISTS seed_edges (
+  id          BIGSERIAL PRIMARY KEY,
+  source_id   BIGINT NOT NULL REFERENCES seeds(id) ON DELETE CASCADE,
+  target_id   BIGINT NOT NULL REFERENCES seeds(id) ON DELETE CASCADE,
+  type        TEXT NOT NULL CHECK (type IN ('derived_from', 'contradicts', 'supports', 'parent_of', 'same_entity')),
+  weight      DOUBLE PRECISION NOT NULL DEFAULT 1,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (source_id, target_id, type),
+  CHECK (source_id <> target_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_seed_edges_target ON seed_edges(target_id);
@@ -0,0 +1,21 @@
+-- Named entities (people, organisations, topics, ...) and their mentions in seeds
+CREATE TABLE IF NOT EXISTS entities (
+  id               BIGSERIAL PRIMARY KEY,
+  name             TEXT NOT NULL,
+  kind             TEXT NOT NULL DEFAULT 'other',
+  aliases          TEXT[] NOT NULL DEFAULT '{}',
+  app_id           TEXT NOT NULL DEFAULT '',
+  external_user_id TEXT NOT NULL DEFAULT '',
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_entities_name ON entities((lower(name)), app_id, external_user_id);
+
+CREATE TABLE IF NOT EXISTS seed_entities (
+  seed_id   BIGINT NOT NULL REFERENCES seeds(id) ON DELETE CASCADE,
+  entity_id BIGINT NOT NULL REFERENCES entities(id) ON DELETE CASCADE,
+  mention   TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY (seed_id, entity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_seed_entities_entity ON seed_entities(entity_id);
@@ -0,0 +1,21 @@
+-- Topic clusters of a tenant's seeds (k-means over embeddings) and persisted assignments
+CREATE TABLE IF NOT EXISTS clusters (
+  id                 BIGSERIAL PRIMARY KEY,
+  app_id             TEXT NOT NULL DEFAULT '',
+  external_user_id   TEXT NOT NULL DEFAULT '',
+  label              INTEGER NOT NULL,
+  centroid           vector(384) NOT NULL,
+  keywords           TEXT[] NOT NULL DEFAULT '{}',
+  representative_ids BIGINT[] NOT NULL DEFAULT '{}',
+  computed_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_clusters_tenant ON clusters(app_id, external_user_id);
+
+CREATE TABLE IF NOT EXISTS seed_clusters (
+  seed_id    BIGINT PRIMARY KEY REFERENCES seeds(id) ON DELETE CASCADE,
+  cluster_id BIGINT NOT NULL REFERENCES clusters(id) ON DELETE CASCADE,
+  similarity DOUBLE PRECISION NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_seed_clusters_cluster ON seed_clusters(cluster_id);
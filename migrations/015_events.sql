@@ -0,0 +1,67 @@
+-- Change log behind GET /events (Server-Sent Events). Triggers record seed and context changes and
+-- NOTIFY neural_brain_events with the event ID, so every server instance can fan out and clients can
+-- resume from Last-Event-ID.
+CREATE TABLE IF NOT EXISTS events (
+  id               BIGSERIAL PRIMARY KEY,
+  type             TEXT NOT NULL,
+  seed_id          BIGINT,
+  context_id       TEXT,
+  app_id           TEXT NOT NULL DEFAULT '',
+  external_user_id TEXT NOT NULL DEFAULT '',
+  payload          JSONB NOT NULL DEFAULT '{}',
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_created_at ON events(created_at);
+
+CREATE OR REPLACE FUNCTION record_seed_event() RETURNS trigger AS $$
+DECLARE
+  event_id BIGINT;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    INSERT INTO events (type, seed_id, app_id, external_user_id, payload)
+    VALUES ('seed.deleted', OLD.id, COALESCE(OLD.app_id, ''), COALESCE(OLD.external_user_id, ''),
+            jsonb_build_object('id', OLD.id))
+    RETURNING id INTO event_id;
+  ELSE
+    INSERT INTO events (type, seed_id, app_id, external_user_id, payload)
+    VALUES (CASE WHEN TG_OP = 'INSERT' THEN 'seed.created' ELSE 'seed.updated' END,
+            NEW.id, COALESCE(NEW.app_id, ''), COALESCE(NEW.external_user_id, ''),
+            jsonb_build_object('id', NEW.id, 'content', NEW.content, 'metadata', NEW.metadata))
+    RETURNING id INTO event_id;
+  END IF;
+  PERFORM pg_notify('neural_brain_events', event_id::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION record_context_event() RETURNS trigger AS $$
+DECLARE
+  event_id BIGINT;
+BEGIN
+  INSERT INTO events (type, context_id, app_id, external_user_id, payload)
+  VALUES ('context.created', NEW.id::text, COALESCE(NEW.app_id, ''), COALESCE(NEW.external_user_id, ''),
+          jsonb_build_object('id', NEW.id::text, 'agentId', NEW.agent_id, 'memoryType', NEW.memory_type, 'payload', NEW.payload))
+  RETURNING id INTO event_id;
+  PERFORM pg_notify('neural_brain_events', event_id::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS seeds_insert_event ON seeds;
+CREATE TRIGGER seeds_insert_event AFTER INSERT ON seeds
+  FOR EACH ROW EXECUTE FUNCTION record_seed_event();
+
+-- Access bookkeeping (access_count, last_accessed_at) is not a change worth streaming.
+DROP TRIGGER IF EXISTS seeds_update_event ON seeds;
+CREATE TRIGGER seeds_update_event AFTER UPDATE ON seeds
+  FOR EACH ROW WHEN (OLD.content IS DISTINCT FROM NEW.content OR OLD.metadata IS DISTINCT FROM NEW.metadata)
+  EXECUTE FUNCTION record_seed_event();
+
+DROP TRIGGER IF EXISTS seeds_delete_event ON seeds;
+CREATE TRIGGER seeds_delete_event AFTER DELETE ON seeds
+  FOR EACH ROW EXECUTE FUNCTION record_seed_event();
+
+DROP TRIGGER IF EXISTS agent_contexts_insert_event ON agent_contexts;
+CREATE TRIGGER agent_contexts_insert_event AFTER INSERT ON agent_contexts
+  FOR EACH ROW EXECUTE FUNCTION record_context_event();
@@ -0,0 +1,75 @@
+-- Outbound webhooks. Every new row in events is copied into webhook_deliveries for each matching
+-- webhook inside the same transaction, so deliveries are queued durably even if no server is running.
+CREATE TABLE IF NOT EXISTS webhooks (
+  id               BIGSERIAL PRIMARY KEY,
+  url              TEXT NOT NULL,
+  secret           TEXT NOT NULL,
+  event_types      TEXT[] NOT NULL DEFAULT '{}', -- empty: all event types
+  description      TEXT NOT NULL DEFAULT '',
+  active           BOOLEAN NOT NULL DEFAULT true,
+  app_id           TEXT NOT NULL DEFAULT '',
+  external_user_id TEXT NOT NULL DEFAULT '',
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_tenant ON webhooks(app_id, external_user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id               BIGSERIAL PRIMARY KEY,
+  webhook_id       BIGINT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  event_id         BIGINT NOT NULL,
+  event_type       TEXT NOT NULL,
+  payload          JSONB NOT NULL,
+  status           TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'dead')),
+  attempts         INT NOT NULL DEFAULT 0,
+  next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_status_code INT,
+  last_error       TEXT NOT NULL DEFAULT '',
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+  delivered_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id DESC);
+
+-- goal.completed, including goals completed by the cascade from a parent.
+CREATE OR REPLACE FUNCTION record_goal_event() RETURNS trigger AS $$
+DECLARE
+  event_id BIGINT;
+BEGIN
+  INSERT INTO events (type, app_id, external_user_id, payload)
+  VALUES ('goal.completed', COALESCE(NEW.app_id, ''), COALESCE(NEW.external_user_id, ''),
+          jsonb_build_object('id', NEW.id, 'parentId', NEW.parent_id, 'title', NEW.title, 'completedAt', NEW.completed_at))
+  RETURNING id INTO event_id;
+  PERFORM pg_notify('neural_brain_events', event_id::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS goals_completed_event ON goals;
+CREATE TRIGGER goals_completed_event AFTER UPDATE ON goals
+  FOR EACH ROW WHEN (NEW.status = 'completed' AND OLD.status IS DISTINCT FROM 'completed')
+  EXECUTE FUNCTION record_goal_event();
+
+-- An empty app_id or external_user_id matches any value. The API stores an empty app_id only for webhooks
+-- created with allTenants, which receive the events of every tenant; a webhook with an appId but no
+-- externalUserId receives the events of all users of that app.
+CREATE OR REPLACE FUNCTION enqueue_webhook_deliveries() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+  SELECT w.id, NEW.id, NEW.type,
+         jsonb_build_object('id', NEW.id, 'type', NEW.type, 'seedId', NEW.seed_id, 'contextId', NEW.context_id,
+                            'appId', NEW.app_id, 'externalUserId', NEW.external_user_id,
+                            'payload', NEW.payload, 'createdAt', NEW.created_at)
+  FROM webhooks w
+  WHERE w.active
+    AND (cardinality(w.event_types) = 0 OR NEW.type = ANY(w.event_types))
+    AND (w.app_id = '' OR w.app_id = NEW.app_id)
+    AND (w.external_user_id = '' OR w.external_user_id = NEW.external_user_id);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS events_enqueue_webhooks ON events;
+CREATE TRIGGER events_enqueue_webhooks AFTER INSERT ON events
+  FOR EACH ROW EXECUTE FUNCTION enqueue_webhook_deliveries();
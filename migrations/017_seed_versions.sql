@@ -0,0 +1,16 @@
+-- Optimistic concurrency for seeds: version is the ETag of GET /seeds/{id} and is checked against If-Match.
+-- The trigger bumps it on every content or metadata change, whichever code path writes; access bookkeeping
+-- does not count as a change.
+ALTER TABLE seeds ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION bump_seed_version() RETURNS trigger AS $$
+BEGIN
+  NEW.version := OLD.version + 1;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS seeds_bump_version ON seeds;
+CREATE TRIGGER seeds_bump_version BEFORE UPDATE ON seeds
+  FOR EACH ROW WHEN (OLD.content IS DISTINCT FROM NEW.content OR OLD.metadata IS DISTINCT FROM NEW.metadata)
+  EXECUTE FUNCTION bump_seed_version();
@@ -0,0 +1,18 @@
+-- Idempotency-Key support for POST /seeds, POST /seeds/batch and POST /agent-contexts. A key is scoped to
+-- its tenant and route; the first request claims it (status_code NULL while it runs), and its response is
+-- replayed for repeats until expires_at. The purge job deletes expired keys.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  app_id           TEXT NOT NULL DEFAULT '',
+  external_user_id TEXT NOT NULL DEFAULT '',
+  route            TEXT NOT NULL, -- method and path, e.g. "POST /seeds"
+  key              TEXT NOT NULL,
+  fingerprint      TEXT NOT NULL, -- SHA-256 of the request query and body
+  status_code      INT,
+  content_type     TEXT NOT NULL DEFAULT '',
+  response         BYTEA,
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at       TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (app_id, external_user_id, route, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON idempotency_keys(expires_at);
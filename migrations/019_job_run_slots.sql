@@ -0,0 +1,5 @@
+-- Scheduled runs record the activation they belong to. The unique index lets exactly one instance claim
+-- each slot of a job; manual runs have no slot.
+ALTER TABLE job_runs ADD COLUMN IF NOT EXISTS slot TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_runs_name_slot ON job_runs(name, slot) WHERE slot IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Learning rule names are unique per tenant, not globally
+ALTER TABLE learning_rules DROP CONSTRAINT IF EXISTS learning_rules_name_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_learning_rules_tenant_name ON learning_rules(app_id, external_user_id, name);
@@ -0,0 +1,6 @@
+-- Events carry the ID of the transaction that wrote them. Readers only take events of transactions
+-- older than the oldest one still running and order by (tx_id, id), so an event that commits after a
+-- higher ID was already streamed is not skipped. Existing rows all get the migration's transaction ID.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS tx_id BIGINT NOT NULL DEFAULT pg_current_xact_id()::text::bigint;
+
+CREATE INDEX IF NOT EXISTS idx_events_tx_id ON events(tx_id, id);
@@ -0,0 +1,6 @@
+-- Entities created through POST /entities are marked manual; only those are taught to the gazetteer at
+-- startup, not every name the extractor once found. Extracted entities never carry aliases, so existing
+-- rows with aliases were created by hand.
+ALTER TABLE entities ADD COLUMN IF NOT EXISTS manual BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE entities SET manual = true WHERE NOT manual AND cardinality(aliases) > 0;
@@ -0,0 +1,266 @@
+// Package client is the Go SDK for the neural-brain HTTP API: typed requests for seeds, search and
+// agent contexts, tenant scoping, retries with backoff and typed errors.
+//
+//	c := client.New("http://localhost:9124", client.WithTenant("my-agent", "1"))
+//	id, err := c.StoreSeed(ctx, "Carsten trinkt Kaffee schwarz", map[string]any{"tags": []string{"vorlieben"}})
+//	hits, err := c.Search(ctx, client.SearchRequest{Query: "Wie trinkt Carsten Kaffee?", Limit: 5})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetries = 2
+	defaultBackoff = 200 * time.Millisecond
+	maxBackoff     = 5 * time.Second
+)
+
+// Client talks to one neural-brain server. It is safe for concurrent use.
+type Client struct {
+	baseURL        string
+	httpClient     *http.Client
+	appID          string
+	externalUserID string
+	retries        int
+	backoff        time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying HTTP client (default: 30 s timeout).
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithTenant scopes every request to appId/externalUserId.
+func WithTenant(appID, externalUserID string) Option {
+	return func(c *Client) { c.appID, c.externalUserID = appID, externalUserID }
+}
+
+// WithRetries sets how often a failed request is retried (default 2, 0 disables retries).
+func WithRetries(n int) Option {
+	return func(c *Client) {
+		if n >= 0 {
+			c.retries = n
+		}
+	}
+}
+
+// WithBackoff sets the delay before the first retry; it doubles per attempt, with jitter, up to 5 s.
+func WithBackoff(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.backoff = d
+		}
+	}
+}
+
+// New returns a client for the server at baseURL, e.g. "http://localhost:9124".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		retries:    defaultRetries,
+		backoff:    defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ForTenant returns a copy of c scoped to another tenant.
+func (c *Client) ForTenant(appID, externalUserID string) *Client {
+	cp := *c
+	cp.appID, cp.externalUserID = appID, externalUserID
+	return &cp
+}
+
+// Error is a non-2xx response. Message is the "error" field of the server's JSON error body.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("neural-brain: %d %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 from the server.
+func IsNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+// IsPreconditionFailed reports whether err is a 412: the seed changed since the version sent in If-Match.
+func IsPreconditionFailed(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusPreconditionFailed
+}
+
+// IsBadRequest reports whether err is a 400 from the server (invalid input).
+func IsBadRequest(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusBadRequest
+}
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey returns a context that sends key as Idempotency-Key with the requests made with it.
+// The server (POST /seeds, /seeds/batch, /agent-contexts) replays the first response for repeats, so such
+// POSTs are retried like other requests. Use a fresh key per logical write, e.g. a UUID.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyCtx{}).(string)
+	return key
+}
+
+// IsReplayConflict reports whether an idempotency key was reused for a different request (422) or
+// its first request is still running (409).
+func IsReplayConflict(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && (e.StatusCode == http.StatusUnprocessableEntity || e.StatusCode == http.StatusConflict)
+}
+
+// retryable reports whether a response status may succeed on retry. POST is only retried when the
+// server certainly did not process the request (429).
+func retryable(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return method != http.MethodPost
+	}
+	return false
+}
+
+// do sends a request with the tenant query params and decodes a JSON response into out (if non-nil).
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	return c.doWithHeader(ctx, method, path, query, nil, body, out)
+}
+
+// doWithHeader is do with extra request headers, e.g. If-Match.
+func (c *Client) doWithHeader(ctx context.Context, method, path string, query url.Values, header http.Header, body, out interface{}) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.appID != "" {
+		query.Set("appId", c.appID)
+	}
+	if c.externalUserID != "" {
+		query.Set("externalUserId", c.externalUserID)
+	}
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	if key := idempotencyKey(ctx); key != "" {
+		header = header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		header.Set("Idempotency-Key", key)
+	}
+	// A keyed request is safe to repeat, so it retries like a PUT.
+	retryMethod := method
+	if header.Get("Idempotency-Key") != "" {
+		retryMethod = http.MethodPut
+	}
+
+	delay := c.backoff
+	for attempt := 0; ; attempt++ {
+		err := c.once(ctx, method, u, header, payload, out)
+		if err == nil || attempt >= c.retries || ctx.Err() != nil {
+			return err
+		}
+		var apiErr *Error
+		if errors.As(err, &apiErr) {
+			if !retryable(retryMethod, apiErr.StatusCode) {
+				return err
+			}
+		} else if retryMethod == http.MethodPost && !isConnectError(err) {
+			// The request may have reached the server; only retry if it was never sent.
+			return err
+		}
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay)))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		if delay *= 2; delay > maxBackoff {
+			delay = maxBackoff
+		}
+	}
+}
+
+func (c *Client) once(ctx context.Context, method, u string, header http.Header, payload []byte, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		msg := http.StatusText(resp.StatusCode)
+		if json.Unmarshal(data, &e) == nil && e.Error != "" {
+			msg = e.Error
+		}
+		return &Error{StatusCode: resp.StatusCode, Message: msg}
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// isConnectError reports whether the request failed while dialing, i.e. before anything was sent.
+func isConnectError(err error) bool {
+	var op *net.OpError
+	return errors.As(err, &op) && op.Op == "dial"
+}
@@ -0,0 +1,378 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apilib "github.com/cabroe/neural-brain/internal/api"
+	"github.com/cabroe/neural-brain/internal/api/handler"
+	"github.com/cabroe/neural-brain/internal/store"
+	"github.com/cabroe/neural-brain/internal/storetest"
+	"github.com/cabroe/neural-brain/pkg/client"
+)
+
+// newServer serves the handlers the client talks to under the patterns main.go registers them with.
+// wrap, if set, sits in front of the mux, e.g. to inject failures.
+func newServer(t *testing.T, s *store.Store, wrap func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /seeds", handler.Idempotent(s, time.Hour, handler.MaxSeedBodyBytes, handler.HandleStoreSeed(s)))
+	mux.HandleFunc("POST /seeds/batch", handler.Idempotent(s, time.Hour, handler.MaxSeedBodyBytes, handler.HandleStoreSeedsBatch(s)))
+	mux.HandleFunc("POST /seeds/query", handler.HandleSeedsQuery(s))
+	mux.HandleFunc("POST /seeds/{id}/tags", handler.HandleUpdateSeedTags(s))
+	mux.HandleFunc("GET /seeds/{id}", handler.HandleGetSeed(s))
+	mux.HandleFunc("PUT /seeds/{id}", handler.HandleUpdateSeed(s))
+	mux.HandleFunc("DELETE /seeds/{id}", handler.HandleDeleteSeed(s))
+	mux.HandleFunc("GET /seeds/recent", handler.HandleGetRecent(s))
+	mux.HandleFunc("POST /agent-contexts", handler.Idempotent(s, time.Hour, handler.MaxContextBodyBytes, handler.HandleCreateContext(s)))
+	mux.HandleFunc("GET /agent-contexts", handler.HandleListContexts(s))
+	mux.HandleFunc("GET /agent-contexts/{id}", handler.HandleGetContext(s))
+	mux.HandleFunc("GET /stats", handler.HandleGetStats(s))
+	var h http.Handler = mux
+	if wrap != nil {
+		h = wrap(mux)
+	}
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// flaky answers the first n requests with status (as a RespondError body) before passing requests on. It
+// counts every request and remembers the last one's query and Idempotency-Key.
+type flaky struct {
+	status int
+	n      int32
+
+	calls atomic.Int32
+	mu    sync.Mutex
+	query url.Values
+	key   string
+}
+
+func (f *flaky) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := f.calls.Add(1)
+		f.mu.Lock()
+		f.query, f.key = r.URL.Query(), r.Header.Get("Idempotency-Key")
+		f.mu.Unlock()
+		if call <= f.n {
+			apilib.RespondError(w, f.status, "try again")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *flaky) lastQuery() url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.query
+}
+
+func (f *flaky) lastKey() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.key
+}
+
+func fastRetries() client.Option { return client.WithBackoff(time.Millisecond) }
+
+func TestErrorFromResponseBody(t *testing.T) {
+	srv := newServer(t, storetest.Offline(t), nil)
+	c := client.New(srv.URL, fastRetries())
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		call    func() error
+		status  int
+		message string
+	}{
+		{"empty content", func() error { _, err := c.StoreSeed(ctx, "", nil); return err }, 400, "content required"},
+		{"empty query", func() error { _, err := c.Search(ctx, client.SearchRequest{}); return err }, 400, "query required"},
+		{"invalid id", func() error { _, err := c.GetSeed(ctx, 0); return err }, 400, "invalid id"},
+		{"unknown memory type", func() error { _, err := c.CreateContext(ctx, "agent", "longterm", nil); return err }, 400,
+			"memoryType must be one of: episodic, semantic, procedural, working"},
+		{"missing agent", func() error { _, err := c.CreateContext(ctx, " ", client.MemoryEpisodic, nil); return err }, 400, "agentId required"},
+		{"list filter", func() error { _, err := c.ListContexts(ctx, "", "longterm"); return err }, 400,
+			"memoryType must be one of: episodic, semantic, procedural, working"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var apiErr *client.Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want *client.Error", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Message != tt.message {
+				t.Errorf("got %d %q, want %d %q", apiErr.StatusCode, apiErr.Message, tt.status, tt.message)
+			}
+			if !client.IsBadRequest(err) || client.IsNotFound(err) || client.IsPreconditionFailed(err) {
+				t.Errorf("predicates disagree with status %d", apiErr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestErrorWithoutJSONBody(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	_, err := client.New(srv.URL).GetSeed(context.Background(), 1)
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.Message != "Not Found" || !client.IsNotFound(err) {
+		t.Fatalf("err = %#v, want 404 Not Found", err)
+	}
+}
+
+func TestTenantQueryParams(t *testing.T) {
+	f := &flaky{}
+	srv := newServer(t, storetest.Offline(t), f.wrap)
+	ctx := context.Background()
+
+	c := client.New(srv.URL, client.WithTenant("app", "user-1"))
+	c.ListContexts(ctx, "agent", "longterm")
+	q := f.lastQuery()
+	if q.Get("appId") != "app" || q.Get("externalUserId") != "user-1" || q.Get("agentId") != "agent" || q.Get("memoryType") != "longterm" {
+		t.Errorf("query = %v", q)
+	}
+
+	c.ForTenant("other", "user-2").StoreSeed(ctx, "", nil)
+	if q := f.lastQuery(); q.Get("appId") != "other" || q.Get("externalUserId") != "user-2" {
+		t.Errorf("ForTenant query = %v", q)
+	}
+
+	// ForTenant returns a copy; the original keeps its tenant.
+	c.StoreSeed(ctx, "", nil)
+	if q := f.lastQuery(); q.Get("appId") != "app" {
+		t.Errorf("original client query = %v", q)
+	}
+
+	client.New(srv.URL).StoreSeed(ctx, "", nil)
+	if q := f.lastQuery(); q.Has("appId") || q.Has("externalUserId") {
+		t.Errorf("untenanted query = %v", q)
+	}
+}
+
+func TestRetryStatus(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		status    int
+		failures  int32
+		opts      []client.Option
+		call      func(*client.Client) error
+		wantCalls int32
+		wantCode  int
+	}{
+		// The last attempt reaches the real handler, which rejects the request with 400.
+		{"GET retried on 503", 503, 2, nil,
+			func(c *client.Client) error { _, err := c.ListContexts(ctx, "", "longterm"); return err }, 3, 400},
+		{"GET retried on 502", 502, 1, nil,
+			func(c *client.Client) error { _, err := c.GetSeed(ctx, 0); return err }, 2, 400},
+		{"GET gives up after the retries", 504, 10, nil,
+			func(c *client.Client) error { _, err := c.GetSeed(ctx, 0); return err }, 3, 504},
+		{"WithRetries(0) disables retries", 503, 10, []client.Option{client.WithRetries(0)},
+			func(c *client.Client) error { _, err := c.GetSeed(ctx, 0); return err }, 1, 503},
+		{"POST retried on 429", 429, 1, nil,
+			func(c *client.Client) error { _, err := c.StoreSeed(ctx, "", nil); return err }, 2, 400},
+		{"POST not retried on 503", 503, 10, nil,
+			func(c *client.Client) error { _, err := c.StoreSeed(ctx, "", nil); return err }, 1, 503},
+		{"500 is not retried", 500, 10, nil,
+			func(c *client.Client) error { _, err := c.GetSeed(ctx, 0); return err }, 1, 500},
+		{"400 is not retried", 400, 10, nil,
+			func(c *client.Client) error { _, err := c.GetSeed(ctx, 0); return err }, 1, 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &flaky{status: tt.status, n: tt.failures}
+			srv := newServer(t, storetest.Offline(t), f.wrap)
+			err := tt.call(client.New(srv.URL, append([]client.Option{fastRetries()}, tt.opts...)...))
+			if got := f.calls.Load(); got != tt.wantCalls {
+				t.Errorf("%d requests, want %d", got, tt.wantCalls)
+			}
+			var apiErr *client.Error
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantCode {
+				t.Errorf("err = %v, want status %d", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRetryKeyedPOST(t *testing.T) {
+	f := &flaky{status: http.StatusServiceUnavailable, n: 2}
+	srv := newServer(t, storetest.Offline(t), f.wrap)
+	ctx := client.WithIdempotencyKey(context.Background(), "batch-1")
+
+	// The offline store fails the third attempt with 500; what matters is that the POST was repeated.
+	client.New(srv.URL, fastRetries()).StoreSeeds(ctx, []client.NewSeed{{Content: "a"}})
+	if got := f.calls.Load(); got != 3 {
+		t.Errorf("%d requests, want 3", got)
+	}
+	if key := f.lastKey(); key != "batch-1" {
+		t.Errorf("Idempotency-Key = %q", key)
+	}
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+	f := &flaky{status: http.StatusServiceUnavailable, n: 10}
+	srv := newServer(t, storetest.Offline(t), f.wrap)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.New(srv.URL, client.WithBackoff(time.Hour)).GetSeed(ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("backoff did not stop at the context deadline")
+	}
+	if got := f.calls.Load(); got != 1 {
+		t.Errorf("%d requests, want 1", got)
+	}
+}
+
+// TestRetryConnectionErrors checks that a request that never left the client (dial error) is retried for
+// every method, while a POST whose connection broke after sending is not: the server may have stored it.
+func TestRetryConnectionErrors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dial error", func(t *testing.T) {
+		var dials atomic.Int32
+		hc := &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dials.Add(1)
+				return nil, &net.OpError{Op: "dial", Net: network, Err: errors.New("connection refused")}
+			},
+		}}
+		c := client.New("http://neural-brain.invalid", client.WithHTTPClient(hc), fastRetries())
+		for _, call := range []func() error{
+			func() error { _, err := c.StoreSeed(ctx, "x", nil); return err },
+			func() error { _, err := c.GetSeed(ctx, 1); return err },
+		} {
+			dials.Store(0)
+			if err := call(); err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := dials.Load(); got != 3 {
+				t.Errorf("%d dials, want 3", got)
+			}
+		}
+	})
+
+	t.Run("connection dropped after the request", func(t *testing.T) {
+		var requests atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			conn, _, err := http.NewResponseController(w).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+		}))
+		defer srv.Close()
+		c := client.New(srv.URL, fastRetries())
+
+		if _, err := c.StoreSeed(ctx, "x", nil); err == nil {
+			t.Fatal("expected an error")
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("POST: %d requests, want 1", got)
+		}
+		requests.Store(0)
+		if _, err := c.GetSeed(ctx, 1); err == nil {
+			t.Fatal("expected an error")
+		}
+		if got := requests.Load(); got != 3 {
+			t.Errorf("GET: %d requests, want 3", got)
+		}
+	})
+}
+
+// TestRoundTrip exercises every typed method against the real handlers, a database and the model. It is
+// skipped unless TEST_DATABASE_URL and GTE_MODEL_PATH are set.
+func TestRoundTrip(t *testing.T) {
+	storetest.LoadModel(t)
+	srv := newServer(t, storetest.Open(t), nil)
+	c := client.New(srv.URL, client.WithTenant("sdk-test", "1"), fastRetries())
+	ctx := context.Background()
+
+	id, err := c.StoreSeed(ctx, "Carsten trinkt Kaffee schwarz", map[string]any{"tags": []string{"vorlieben"}})
+	if err != nil || id <= 0 {
+		t.Fatalf("StoreSeed = %d, %v", id, err)
+	}
+	seed, err := c.GetSeed(ctx, id)
+	if err != nil {
+		t.Fatalf("GetSeed: %v", err)
+	}
+	if seed.Content != "Carsten trinkt Kaffee schwarz" || seed.AppID != "sdk-test" || seed.ExternalUserID != "1" || seed.CreatedAt.IsZero() {
+		t.Errorf("GetSeed = %+v", seed)
+	}
+
+	keyed := client.WithIdempotencyKey(ctx, "round-trip-batch")
+	ids, err := c.StoreSeeds(keyed, []client.NewSeed{{Content: "Der Server läuft auf Port 9124"}, {Content: "Backups laufen nachts um drei"}})
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("StoreSeeds = %v, %v", ids, err)
+	}
+	again, err := c.StoreSeeds(keyed, []client.NewSeed{{Content: "Der Server läuft auf Port 9124"}, {Content: "Backups laufen nachts um drei"}})
+	if err != nil || len(again) != 2 || again[0] != ids[0] || again[1] != ids[1] {
+		t.Errorf("replayed StoreSeeds = %v, %v; want %v", again, err, ids)
+	}
+
+	hits, err := c.Search(ctx, client.SearchRequest{Query: "Wie trinkt Carsten Kaffee?", Limit: 1})
+	if err != nil || len(hits) != 1 || hits[0].SeedID != id || hits[0].Similarity <= 0 {
+		t.Errorf("Search = %+v, %v", hits, err)
+	}
+	recent, err := c.Recent(ctx, 10)
+	// The batch shares one transaction timestamp, so only the first seed's position is fixed.
+	if err != nil || len(recent) != 3 || recent[2].ID != id {
+		t.Errorf("Recent = %+v, %v", recent, err)
+	}
+
+	version, err := c.UpdateSeedIfMatch(ctx, id, seed.Version, "Carsten trinkt Kaffee mit Hafermilch", nil)
+	if err != nil || version <= seed.Version {
+		t.Fatalf("UpdateSeedIfMatch = %d, %v", version, err)
+	}
+	if _, err := c.UpdateSeedIfMatch(ctx, id, seed.Version, "stale", nil); !client.IsPreconditionFailed(err) {
+		t.Errorf("stale UpdateSeedIfMatch: %v, want 412", err)
+	}
+	if err := c.UpdateSeedTags(ctx, id, []string{"kaffee"}); err != nil {
+		t.Errorf("UpdateSeedTags: %v", err)
+	}
+	if err := c.DeleteSeedIfMatch(ctx, id, seed.Version); !client.IsPreconditionFailed(err) {
+		t.Errorf("stale DeleteSeedIfMatch: %v, want 412", err)
+	}
+	if err := c.DeleteSeed(ctx, id); err != nil {
+		t.Errorf("DeleteSeed: %v", err)
+	}
+	if _, err := c.GetSeed(ctx, id); !client.IsNotFound(err) {
+		t.Errorf("GetSeed after delete: %v, want 404", err)
+	}
+
+	ctxID, err := c.CreateContext(ctx, "agent-1", client.MemoryEpisodic, map[string]any{"summary": "SDK getestet"})
+	if err != nil || ctxID == "" {
+		t.Fatalf("CreateContext = %q, %v", ctxID, err)
+	}
+	ac, err := c.GetContext(ctx, ctxID)
+	if err != nil || ac.AgentID != "agent-1" || ac.MemoryType != client.MemoryEpisodic || ac.CreatedAt.IsZero() {
+		t.Errorf("GetContext = %+v, %v", ac, err)
+	}
+	list, err := c.ListContexts(ctx, "agent-1", client.MemoryEpisodic)
+	if err != nil || len(list) != 1 || list[0].ID != ctxID {
+		t.Errorf("ListContexts = %+v, %v", list, err)
+	}
+
+	stats, err := c.Stats(ctx)
+	if err != nil || stats.Seeds != 2 || stats.AgentContexts != 1 {
+		t.Errorf("Stats = %+v, %v", stats, err)
+	}
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Memory types accepted for agent contexts.
+const (
+	MemoryEpisodic   = "episodic"
+	MemorySemantic   = "semantic"
+	MemoryProcedural = "procedural"
+	MemoryWorking    = "working"
+)
+
+// AgentContext is a stored agent context.
+type AgentContext struct {
+	ID             string          `json:"id"`
+	AgentID        string          `json:"agentId"`
+	AppID          string          `json:"appId,omitempty"`
+	ExternalUserID string          `json:"externalUserId,omitempty"`
+	MemoryType     string          `json:"memoryType"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
+
+// CreateContext stores payload (any JSON-encodable object) for agentID and returns the context ID.
+func (c *Client) CreateContext(ctx context.Context, agentID, memoryType string, payload interface{}) (string, error) {
+	body := map[string]interface{}{"agentId": agentID, "memoryType": memoryType, "payload": payload}
+	var out struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/agent-contexts", nil, body, &out); err != nil {
+		return "", err
+	}
+	return out.ID, nil
+}
+
+// GetContext returns one agent context; a missing context yields an error for which IsNotFound is true.
+func (c *Client) GetContext(ctx context.Context, id string) (*AgentContext, error) {
+	var ac AgentContext
+	if err := c.do(ctx, http.MethodGet, "/agent-contexts/"+url.PathEscape(id), nil, nil, &ac); err != nil {
+		return nil, err
+	}
+	return &ac, nil
+}
+
+// ListContexts returns contexts oldest first; empty agentID or memoryType match all.
+func (c *Client) ListContexts(ctx context.Context, agentID, memoryType string) ([]AgentContext, error) {
+	q := url.Values{}
+	if agentID != "" {
+		q.Set("agentId", agentID)
+	}
+	if memoryType != "" {
+		q.Set("memoryType", memoryType)
+	}
+	var list []AgentContext
+	if err := c.do(ctx, http.MethodGet, "/agent-contexts", q, nil, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
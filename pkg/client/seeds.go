@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Seed is a stored memory.
+type Seed struct {
+	ID             int64           `json:"id"`
+	Content        string          `json:"content"`
+	Metadata       json.RawMessage `json:"metadata"`
+	AppID          string          `json:"appId,omitempty"`
+	ExternalUserID string          `json:"externalUserId,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	AccessCount    int64           `json:"accessCount"`
+	LastAccessedAt time.Time       `json:"lastAccessedAt"`
+	Version        int64           `json:"version"` // for UpdateSeedIfMatch and DeleteSeedIfMatch
+}
+
+// SearchRequest is a semantic search (POST /seeds/query).
+type SearchRequest struct {
+	Query     string  `json:"query"`
+	Limit     int     `json:"limit,omitempty"`     // server default 30, max 100
+	Threshold float64 `json:"threshold,omitempty"` // minimum similarity
+	SeedIDs   []int64 `json:"seedIds,omitempty"`   // restrict the search to these seeds
+	MMR       bool    `json:"mmr,omitempty"`       // diversify results
+	Expand    bool    `json:"expand,omitempty"`    // append related seeds from the relation graph
+	Entity    string  `json:"entity,omitempty"`    // only seeds mentioning this entity
+}
+
+// SearchResult is one hit of Search.
+type SearchResult struct {
+	SeedID     int64
+	Content    string
+	Similarity float64
+}
+
+// StoreSeed stores content with optional metadata (any JSON-encodable value, nil for none) and returns the
+// seed ID. With deduplication enabled on the server, a near-duplicate returns the existing seed's ID.
+func (c *Client) StoreSeed(ctx context.Context, content string, metadata interface{}) (int64, error) {
+	body := map[string]interface{}{"content": content}
+	if metadata != nil {
+		body["metadata"] = metadata
+	}
+	var out struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/seeds", nil, body, &out); err != nil {
+		return 0, err
+	}
+	return out.ID, nil
+}
+
+// NewSeed is one item of StoreSeeds.
+type NewSeed struct {
+	Content  string      `json:"content"`
+	Metadata interface{} `json:"metadata,omitempty"`
+}
+
+// StoreSeeds stores up to 100 seeds in one request and returns their IDs in order. Combine it with
+// WithIdempotencyKey to make retries safe.
+func (c *Client) StoreSeeds(ctx context.Context, seeds []NewSeed) ([]int64, error) {
+	var out struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/seeds/batch", nil, map[string]interface{}{"seeds": seeds}, &out); err != nil {
+		return nil, err
+	}
+	return out.IDs, nil
+}
+
+// GetSeed returns one seed; a missing seed yields an error for which IsNotFound is true.
+func (c *Client) GetSeed(ctx context.Context, id int64) (*Seed, error) {
+	var se Seed
+	if err := c.do(ctx, http.MethodGet, "/seeds/"+strconv.FormatInt(id, 10), nil, nil, &se); err != nil {
+		return nil, err
+	}
+	return &se, nil
+}
+
+// UpdateSeed overwrites a seed's content and metadata and re-embeds it.
+func (c *Client) UpdateSeed(ctx context.Context, id int64, content string, metadata interface{}) error {
+	body := map[string]interface{}{"content": content}
+	if metadata != nil {
+		body["metadata"] = metadata
+	}
+	return c.do(ctx, http.MethodPut, "/seeds/"+strconv.FormatInt(id, 10), nil, body, nil)
+}
+
+// UpdateSeedIfMatch is UpdateSeed that only applies if the seed is still at version; otherwise the error
+// satisfies IsPreconditionFailed. It returns the new version.
+func (c *Client) UpdateSeedIfMatch(ctx context.Context, id, version int64, content string, metadata interface{}) (int64, error) {
+	body := map[string]interface{}{"content": content}
+	if metadata != nil {
+		body["metadata"] = metadata
+	}
+	var out struct {
+		Version int64 `json:"version"`
+	}
+	err := c.doWithHeader(ctx, http.MethodPut, "/seeds/"+strconv.FormatInt(id, 10), nil, ifMatch(version), body, &out)
+	return out.Version, err
+}
+
+// DeleteSeed deletes a seed.
+func (c *Client) DeleteSeed(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, "/seeds/"+strconv.FormatInt(id, 10), nil, nil, nil)
+}
+
+// DeleteSeedIfMatch deletes a seed only if it is still at version; otherwise the error satisfies IsPreconditionFailed.
+func (c *Client) DeleteSeedIfMatch(ctx context.Context, id, version int64) error {
+	return c.doWithHeader(ctx, http.MethodDelete, "/seeds/"+strconv.FormatInt(id, 10), nil, ifMatch(version), nil, nil)
+}
+
+func ifMatch(version int64) http.Header {
+	return http.Header{"If-Match": {`"` + strconv.FormatInt(version, 10) + `"`}}
+}
+
+// UpdateSeedTags replaces a seed's metadata.tags.
+func (c *Client) UpdateSeedTags(ctx context.Context, id int64, tags []string) error {
+	return c.do(ctx, http.MethodPost, "/seeds/"+strconv.FormatInt(id, 10)+"/tags", nil, tags, nil)
+}
+
+// Search returns the seeds most similar to req.Query.
+func (c *Client) Search(ctx context.Context, req SearchRequest) ([]SearchResult, error) {
+	var out struct {
+		Results []struct {
+			SeedID     string  `json:"seedId"`
+			Content    string  `json:"content"`
+			Similarity float64 `json:"similarity"`
+		} `json:"results"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/seeds/query", nil, req, &out); err != nil {
+		return nil, err
+	}
+	results := make([]SearchResult, 0, len(out.Results))
+	for _, r := range out.Results {
+		id, _ := strconv.ParseInt(r.SeedID, 10, 64)
+		results = append(results, SearchResult{SeedID: id, Content: r.Content, Similarity: r.Similarity})
+	}
+	return results, nil
+}
+
+// Recent returns the newest seeds, newest first (limit 0: server default).
+func (c *Client) Recent(ctx context.Context, limit int) ([]Seed, error) {
+	q := url.Values{}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var seeds []Seed
+	if err := c.do(ctx, http.MethodGet, "/seeds/recent", q, nil, &seeds); err != nil {
+		return nil, err
+	}
+	return seeds, nil
+}
+
+// Stats are the server-wide counts of GET /stats.
+type Stats struct {
+	Seeds         int64 `json:"seeds"`
+	AgentContexts int64 `json:"agent_contexts"`
+}
+
+// Stats returns the total number of seeds and agent contexts.
+func (c *Client) Stats(ctx context.Context) (*Stats, error) {
+	var st Stats
+	if err := c.do(ctx, http.MethodGet, "/stats", nil, nil, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader carries the HMAC signature of a webhook delivery.
+const WebhookSignatureHeader = "X-Neural-Brain-Signature"
+
+// ErrInvalidSignature is returned by VerifyWebhook for a missing, stale or wrong signature.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// WebhookEvent is the JSON body of a webhook delivery.
+type WebhookEvent struct {
+	ID             int64           `json:"id"`
+	Type           string          `json:"type"`
+	SeedID         *int64          `json:"seedId"`
+	ContextID      *string         `json:"contextId"`
+	AppID          string          `json:"appId"`
+	ExternalUserID string          `json:"externalUserId"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
+
+// VerifyWebhook checks the signature header of a delivery against the raw request body and decodes it.
+// Signatures older than tolerance are rejected to limit replays (0: 5 minutes).
+func VerifyWebhook(secret, signature string, body []byte, tolerance time.Duration) (*WebhookEvent, error) {
+	if tolerance <= 0 {
+		tolerance = 5 * time.Minute
+	}
+	var ts, sig string
+	for _, part := range strings.Split(signature, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig = v
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return nil, ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return nil, ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return nil, ErrInvalidSignature
+	}
+	var ev WebhookEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
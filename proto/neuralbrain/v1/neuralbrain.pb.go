@@ -0,0 +1,1220 @@
+// gRPC API of neural-brain. It mirrors the HTTP JSON API for seeds, agent contexts and stats and is served
+// on GRPC_PORT by the same binary. Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: neuralbrain/v1/neuralbrain.proto
+
+package neuralbrainv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Tenant scopes a request like the appId/externalUserId query parameters of the HTTP API.
+type Tenant struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AppId          string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	ExternalUserId string                 `protobuf:"bytes,2,opt,name=external_user_id,json=externalUserId,proto3" json:"external_user_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Tenant) Reset() {
+	*x = Tenant{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tenant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tenant) ProtoMessage() {}
+
+func (x *Tenant) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tenant.ProtoReflect.Descriptor instead.
+func (*Tenant) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Tenant) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *Tenant) GetExternalUserId() string {
+	if x != nil {
+		return x.ExternalUserId
+	}
+	return ""
+}
+
+type Seed struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Content        string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Metadata       *structpb.Struct       `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	AppId          string                 `protobuf:"bytes,4,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	ExternalUserId string                 `protobuf:"bytes,5,opt,name=external_user_id,json=externalUserId,proto3" json:"external_user_id,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	AccessCount    int64                  `protobuf:"varint,7,opt,name=access_count,json=accessCount,proto3" json:"access_count,omitempty"`
+	LastAccessedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_accessed_at,json=lastAccessedAt,proto3" json:"last_accessed_at,omitempty"`
+	// Cosine similarity for search results, 0 otherwise.
+	Similarity    float64 `protobuf:"fixed64,9,opt,name=similarity,proto3" json:"similarity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Seed) Reset() {
+	*x = Seed{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Seed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Seed) ProtoMessage() {}
+
+func (x *Seed) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Seed.ProtoReflect.Descriptor instead.
+func (*Seed) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Seed) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Seed) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Seed) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Seed) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *Seed) GetExternalUserId() string {
+	if x != nil {
+		return x.ExternalUserId
+	}
+	return ""
+}
+
+func (x *Seed) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Seed) GetAccessCount() int64 {
+	if x != nil {
+		return x.AccessCount
+	}
+	return 0
+}
+
+func (x *Seed) GetLastAccessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAccessedAt
+	}
+	return nil
+}
+
+func (x *Seed) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+type SeedList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seeds         []*Seed                `protobuf:"bytes,1,rep,name=seeds,proto3" json:"seeds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeedList) Reset() {
+	*x = SeedList{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeedList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeedList) ProtoMessage() {}
+
+func (x *SeedList) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeedList.ProtoReflect.Descriptor instead.
+func (*SeedList) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SeedList) GetSeeds() []*Seed {
+	if x != nil {
+		return x.Seeds
+	}
+	return nil
+}
+
+type StoreSeedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenant        *Tenant                `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Metadata      *structpb.Struct       `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreSeedRequest) Reset() {
+	*x = StoreSeedRequest{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreSeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreSeedRequest) ProtoMessage() {}
+
+func (x *StoreSeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreSeedRequest.ProtoReflect.Descriptor instead.
+func (*StoreSeedRequest) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StoreSeedRequest) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+func (x *StoreSeedRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *StoreSeedRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type StoreSeedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreSeedResponse) Reset() {
+	*x = StoreSeedResponse{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreSeedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreSeedResponse) ProtoMessage() {}
+
+func (x *StoreSeedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreSeedResponse.ProtoReflect.Descriptor instead.
+func (*StoreSeedResponse) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StoreSeedResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type BulkInsertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []int64                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkInsertResponse) Reset() {
+	*x = BulkInsertResponse{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkInsertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkInsertResponse) ProtoMessage() {}
+
+func (x *BulkInsertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkInsertResponse.ProtoReflect.Descriptor instead.
+func (*BulkInsertResponse) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BulkInsertResponse) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type GetSeedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSeedRequest) Reset() {
+	*x = GetSeedRequest{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSeedRequest) ProtoMessage() {}
+
+func (x *GetSeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSeedRequest.ProtoReflect.Descriptor instead.
+func (*GetSeedRequest) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetSeedRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type UpdateSeedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenant        *Tenant                `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Metadata      *structpb.Struct       `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSeedRequest) Reset() {
+	*x = UpdateSeedRequest{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSeedRequest) ProtoMessage() {}
+
+func (x *UpdateSeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSeedRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSeedRequest) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateSeedRequest) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+func (x *UpdateSeedRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateSeedRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *UpdateSeedRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type SearchSeedsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Tenant *Tenant                `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Query  string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	// Default 10, at most 100.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Minimum similarity; unset returns all hits.
+	Threshold *float64 `protobuf:"fixed64,4,opt,name=threshold,proto3,oneof" json:"threshold,omitempty"`
+	// Restricts the search to these seeds.
+	SeedIds       []int64 `protobuf:"varint,5,rep,packed,name=seed_ids,json=seedIds,proto3" json:"seed_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchSeedsRequest) Reset() {
+	*x = SearchSeedsRequest{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchSeedsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchSeedsRequest) ProtoMessage() {}
+
+func (x *SearchSeedsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchSeedsRequest.ProtoReflect.Descriptor instead.
+func (*SearchSeedsRequest) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SearchSeedsRequest) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+func (x *SearchSeedsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchSeedsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchSeedsRequest) GetThreshold() float64 {
+	if x != nil && x.Threshold != nil {
+		return *x.Threshold
+	}
+	return 0
+}
+
+func (x *SearchSeedsRequest) GetSeedIds() []int64 {
+	if x != nil {
+		return x.SeedIds
+	}
+	return nil
+}
+
+type RecentSeedsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenant        *Tenant                `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecentSeedsRequest) Reset() {
+	*x = RecentSeedsRequest{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecentSeedsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecentSeedsRequest) ProtoMessage() {}
+
+func (x *RecentSeedsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecentSeedsRequest.ProtoReflect.Descriptor instead.
+func (*RecentSeedsRequest) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RecentSeedsRequest) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+func (x *RecentSeedsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type AgentContext struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentId        string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	MemoryType     string                 `protobuf:"bytes,3,opt,name=memory_type,json=memoryType,proto3" json:"memory_type,omitempty"`
+	Payload        *structpb.Struct       `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	AppId          string                 `protobuf:"bytes,5,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	ExternalUserId string                 `protobuf:"bytes,6,opt,name=external_user_id,json=externalUserId,proto3" json:"external_user_id,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AgentContext) Reset() {
+	*x = AgentContext{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentContext) ProtoMessage() {}
+
+func (x *AgentContext) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentContext.ProtoReflect.Descriptor instead.
+func (*AgentContext) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AgentContext) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AgentContext) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentContext) GetMemoryType() string {
+	if x != nil {
+		return x.MemoryType
+	}
+	return ""
+}
+
+func (x *AgentContext) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *AgentContext) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *AgentContext) GetExternalUserId() string {
+	if x != nil {
+		return x.ExternalUserId
+	}
+	return ""
+}
+
+func (x *AgentContext) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ContextList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Contexts      []*AgentContext        `protobuf:"bytes,1,rep,name=contexts,proto3" json:"contexts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContextList) Reset() {
+	*x = ContextList{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContextList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContextList) ProtoMessage() {}
+
+func (x *ContextList) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContextList.ProtoReflect.Descriptor instead.
+func (*ContextList) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ContextList) GetContexts() []*AgentContext {
+	if x != nil {
+		return x.Contexts
+	}
+	return nil
+}
+
+type CreateContextRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Tenant  *Tenant                `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	AgentId string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// episodic, semantic, procedural or working.
+	MemoryType    string           `protobuf:"bytes,3,opt,name=memory_type,json=memoryType,proto3" json:"memory_type,omitempty"`
+	Payload       *structpb.Struct `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateContextRequest) Reset() {
+	*x = CreateContextRequest{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateContextRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateContextRequest) ProtoMessage() {}
+
+func (x *CreateContextRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateContextRequest.ProtoReflect.Descriptor instead.
+func (*CreateContextRequest) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreateContextRequest) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+func (x *CreateContextRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *CreateContextRequest) GetMemoryType() string {
+	if x != nil {
+		return x.MemoryType
+	}
+	return ""
+}
+
+func (x *CreateContextRequest) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type GetContextRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetContextRequest) Reset() {
+	*x = GetContextRequest{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetContextRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetContextRequest) ProtoMessage() {}
+
+func (x *GetContextRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetContextRequest.ProtoReflect.Descriptor instead.
+func (*GetContextRequest) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetContextRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListContextsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenant        *Tenant                `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	MemoryType    string                 `protobuf:"bytes,3,opt,name=memory_type,json=memoryType,proto3" json:"memory_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListContextsRequest) Reset() {
+	*x = ListContextsRequest{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListContextsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListContextsRequest) ProtoMessage() {}
+
+func (x *ListContextsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListContextsRequest.ProtoReflect.Descriptor instead.
+func (*ListContextsRequest) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListContextsRequest) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+func (x *ListContextsRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ListContextsRequest) GetMemoryType() string {
+	if x != nil {
+		return x.MemoryType
+	}
+	return ""
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{15}
+}
+
+type Stats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seeds         int64                  `protobuf:"varint,1,opt,name=seeds,proto3" json:"seeds,omitempty"`
+	AgentContexts int64                  `protobuf:"varint,2,opt,name=agent_contexts,json=agentContexts,proto3" json:"agent_contexts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_neuralbrain_v1_neuralbrain_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Stats) GetSeeds() int64 {
+	if x != nil {
+		return x.Seeds
+	}
+	return 0
+}
+
+func (x *Stats) GetAgentContexts() int64 {
+	if x != nil {
+		return x.AgentContexts
+	}
+	return 0
+}
+
+var File_neuralbrain_v1_neuralbrain_proto protoreflect.FileDescriptor
+
+const file_neuralbrain_v1_neuralbrain_proto_rawDesc = "" +
+	"\n" +
+	" neuralbrain/v1/neuralbrain.proto\x12\x0eneuralbrain.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"I\n" +
+	"\x06Tenant\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12(\n" +
+	"\x10external_user_id\x18\x02 \x01(\tR\x0eexternalUserId\"\xea\x02\n" +
+	"\x04Seed\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x123\n" +
+	"\bmetadata\x18\x03 \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12\x15\n" +
+	"\x06app_id\x18\x04 \x01(\tR\x05appId\x12(\n" +
+	"\x10external_user_id\x18\x05 \x01(\tR\x0eexternalUserId\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12!\n" +
+	"\faccess_count\x18\a \x01(\x03R\vaccessCount\x12D\n" +
+	"\x10last_accessed_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x0elastAccessedAt\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\t \x01(\x01R\n" +
+	"similarity\"6\n" +
+	"\bSeedList\x12*\n" +
+	"\x05seeds\x18\x01 \x03(\v2\x14.neuralbrain.v1.SeedR\x05seeds\"\x91\x01\n" +
+	"\x10StoreSeedRequest\x12.\n" +
+	"\x06tenant\x18\x01 \x01(\v2\x16.neuralbrain.v1.TenantR\x06tenant\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x123\n" +
+	"\bmetadata\x18\x03 \x01(\v2\x17.google.protobuf.StructR\bmetadata\"#\n" +
+	"\x11StoreSeedResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"&\n" +
+	"\x12BulkInsertResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x03R\x03ids\" \n" +
+	"\x0eGetSeedRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\xa2\x01\n" +
+	"\x11UpdateSeedRequest\x12.\n" +
+	"\x06tenant\x18\x01 \x01(\v2\x16.neuralbrain.v1.TenantR\x06tenant\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x123\n" +
+	"\bmetadata\x18\x04 \x01(\v2\x17.google.protobuf.StructR\bmetadata\"\xbc\x01\n" +
+	"\x12SearchSeedsRequest\x12.\n" +
+	"\x06tenant\x18\x01 \x01(\v2\x16.neuralbrain.v1.TenantR\x06tenant\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12!\n" +
+	"\tthreshold\x18\x04 \x01(\x01H\x00R\tthreshold\x88\x01\x01\x12\x19\n" +
+	"\bseed_ids\x18\x05 \x03(\x03R\aseedIdsB\f\n" +
+	"\n" +
+	"_threshold\"Z\n" +
+	"\x12RecentSeedsRequest\x12.\n" +
+	"\x06tenant\x18\x01 \x01(\v2\x16.neuralbrain.v1.TenantR\x06tenant\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"\x89\x02\n" +
+	"\fAgentContext\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x1f\n" +
+	"\vmemory_type\x18\x03 \x01(\tR\n" +
+	"memoryType\x121\n" +
+	"\apayload\x18\x04 \x01(\v2\x17.google.protobuf.StructR\apayload\x12\x15\n" +
+	"\x06app_id\x18\x05 \x01(\tR\x05appId\x12(\n" +
+	"\x10external_user_id\x18\x06 \x01(\tR\x0eexternalUserId\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"G\n" +
+	"\vContextList\x128\n" +
+	"\bcontexts\x18\x01 \x03(\v2\x1c.neuralbrain.v1.AgentContextR\bcontexts\"\xb5\x01\n" +
+	"\x14CreateContextRequest\x12.\n" +
+	"\x06tenant\x18\x01 \x01(\v2\x16.neuralbrain.v1.TenantR\x06tenant\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x1f\n" +
+	"\vmemory_type\x18\x03 \x01(\tR\n" +
+	"memoryType\x121\n" +
+	"\apayload\x18\x04 \x01(\v2\x17.google.protobuf.StructR\apayload\"#\n" +
+	"\x11GetContextRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x81\x01\n" +
+	"\x13ListContextsRequest\x12.\n" +
+	"\x06tenant\x18\x01 \x01(\v2\x16.neuralbrain.v1.TenantR\x06tenant\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x1f\n" +
+	"\vmemory_type\x18\x03 \x01(\tR\n" +
+	"memoryType\"\x11\n" +
+	"\x0fGetStatsRequest\"D\n" +
+	"\x05Stats\x12\x14\n" +
+	"\x05seeds\x18\x01 \x01(\x03R\x05seeds\x12%\n" +
+	"\x0eagent_contexts\x18\x02 \x01(\x03R\ragentContexts2\xdd\x06\n" +
+	"\vNeuralBrain\x12P\n" +
+	"\tStoreSeed\x12 .neuralbrain.v1.StoreSeedRequest\x1a!.neuralbrain.v1.StoreSeedResponse\x12T\n" +
+	"\n" +
+	"BulkInsert\x12 .neuralbrain.v1.StoreSeedRequest\x1a\".neuralbrain.v1.BulkInsertResponse(\x01\x12?\n" +
+	"\aGetSeed\x12\x1e.neuralbrain.v1.GetSeedRequest\x1a\x14.neuralbrain.v1.Seed\x12E\n" +
+	"\n" +
+	"UpdateSeed\x12!.neuralbrain.v1.UpdateSeedRequest\x1a\x14.neuralbrain.v1.Seed\x12K\n" +
+	"\vSearchSeeds\x12\".neuralbrain.v1.SearchSeedsRequest\x1a\x18.neuralbrain.v1.SeedList\x12J\n" +
+	"\fSearchStream\x12\".neuralbrain.v1.SearchSeedsRequest\x1a\x14.neuralbrain.v1.Seed0\x01\x12K\n" +
+	"\vRecentSeeds\x12\".neuralbrain.v1.RecentSeedsRequest\x1a\x18.neuralbrain.v1.SeedList\x12S\n" +
+	"\rCreateContext\x12$.neuralbrain.v1.CreateContextRequest\x1a\x1c.neuralbrain.v1.AgentContext\x12M\n" +
+	"\n" +
+	"GetContext\x12!.neuralbrain.v1.GetContextRequest\x1a\x1c.neuralbrain.v1.AgentContext\x12P\n" +
+	"\fListContexts\x12#.neuralbrain.v1.ListContextsRequest\x1a\x1b.neuralbrain.v1.ContextList\x12B\n" +
+	"\bGetStats\x12\x1f.neuralbrain.v1.GetStatsRequest\x1a\x15.neuralbrain.v1.StatsBCZAgithub.com/cabroe/neural-brain/proto/neuralbrain/v1;neuralbrainv1b\x06proto3"
+
+var (
+	file_neuralbrain_v1_neuralbrain_proto_rawDescOnce sync.Once
+	file_neuralbrain_v1_neuralbrain_proto_rawDescData []byte
+)
+
+func file_neuralbrain_v1_neuralbrain_proto_rawDescGZIP() []byte {
+	file_neuralbrain_v1_neuralbrain_proto_rawDescOnce.Do(func() {
+		file_neuralbrain_v1_neuralbrain_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_neuralbrain_v1_neuralbrain_proto_rawDesc), len(file_neuralbrain_v1_neuralbrain_proto_rawDesc)))
+	})
+	return file_neuralbrain_v1_neuralbrain_proto_rawDescData
+}
+
+var file_neuralbrain_v1_neuralbrain_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_neuralbrain_v1_neuralbrain_proto_goTypes = []any{
+	(*Tenant)(nil),                // 0: neuralbrain.v1.Tenant
+	(*Seed)(nil),                  // 1: neuralbrain.v1.Seed
+	(*SeedList)(nil),              // 2: neuralbrain.v1.SeedList
+	(*StoreSeedRequest)(nil),      // 3: neuralbrain.v1.StoreSeedRequest
+	(*StoreSeedResponse)(nil),     // 4: neuralbrain.v1.StoreSeedResponse
+	(*BulkInsertResponse)(nil),    // 5: neuralbrain.v1.BulkInsertResponse
+	(*GetSeedRequest)(nil),        // 6: neuralbrain.v1.GetSeedRequest
+	(*UpdateSeedRequest)(nil),     // 7: neuralbrain.v1.UpdateSeedRequest
+	(*SearchSeedsRequest)(nil),    // 8: neuralbrain.v1.SearchSeedsRequest
+	(*RecentSeedsRequest)(nil),    // 9: neuralbrain.v1.RecentSeedsRequest
+	(*AgentContext)(nil),          // 10: neuralbrain.v1.AgentContext
+	(*ContextList)(nil),           // 11: neuralbrain.v1.ContextList
+	(*CreateContextRequest)(nil),  // 12: neuralbrain.v1.CreateContextRequest
+	(*GetContextRequest)(nil),     // 13: neuralbrain.v1.GetContextRequest
+	(*ListContextsRequest)(nil),   // 14: neuralbrain.v1.ListContextsRequest
+	(*GetStatsRequest)(nil),       // 15: neuralbrain.v1.GetStatsRequest
+	(*Stats)(nil),                 // 16: neuralbrain.v1.Stats
+	(*structpb.Struct)(nil),       // 17: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil), // 18: google.protobuf.Timestamp
+}
+var file_neuralbrain_v1_neuralbrain_proto_depIdxs = []int32{
+	17, // 0: neuralbrain.v1.Seed.metadata:type_name -> google.protobuf.Struct
+	18, // 1: neuralbrain.v1.Seed.created_at:type_name -> google.protobuf.Timestamp
+	18, // 2: neuralbrain.v1.Seed.last_accessed_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: neuralbrain.v1.SeedList.seeds:type_name -> neuralbrain.v1.Seed
+	0,  // 4: neuralbrain.v1.StoreSeedRequest.tenant:type_name -> neuralbrain.v1.Tenant
+	17, // 5: neuralbrain.v1.StoreSeedRequest.metadata:type_name -> google.protobuf.Struct
+	0,  // 6: neuralbrain.v1.UpdateSeedRequest.tenant:type_name -> neuralbrain.v1.Tenant
+	17, // 7: neuralbrain.v1.UpdateSeedRequest.metadata:type_name -> google.protobuf.Struct
+	0,  // 8: neuralbrain.v1.SearchSeedsRequest.tenant:type_name -> neuralbrain.v1.Tenant
+	0,  // 9: neuralbrain.v1.RecentSeedsRequest.tenant:type_name -> neuralbrain.v1.Tenant
+	17, // 10: neuralbrain.v1.AgentContext.payload:type_name -> google.protobuf.Struct
+	18, // 11: neuralbrain.v1.AgentContext.created_at:type_name -> google.protobuf.Timestamp
+	10, // 12: neuralbrain.v1.ContextList.contexts:type_name -> neuralbrain.v1.AgentContext
+	0,  // 13: neuralbrain.v1.CreateContextRequest.tenant:type_name -> neuralbrain.v1.Tenant
+	17, // 14: neuralbrain.v1.CreateContextRequest.payload:type_name -> google.protobuf.Struct
+	0,  // 15: neuralbrain.v1.ListContextsRequest.tenant:type_name -> neuralbrain.v1.Tenant
+	3,  // 16: neuralbrain.v1.NeuralBrain.StoreSeed:input_type -> neuralbrain.v1.StoreSeedRequest
+	3,  // 17: neuralbrain.v1.NeuralBrain.BulkInsert:input_type -> neuralbrain.v1.StoreSeedRequest
+	6,  // 18: neuralbrain.v1.NeuralBrain.GetSeed:input_type -> neuralbrain.v1.GetSeedRequest
+	7,  // 19: neuralbrain.v1.NeuralBrain.UpdateSeed:input_type -> neuralbrain.v1.UpdateSeedRequest
+	8,  // 20: neuralbrain.v1.NeuralBrain.SearchSeeds:input_type -> neuralbrain.v1.SearchSeedsRequest
+	8,  // 21: neuralbrain.v1.NeuralBrain.SearchStream:input_type -> neuralbrain.v1.SearchSeedsRequest
+	9,  // 22: neuralbrain.v1.NeuralBrain.RecentSeeds:input_type -> neuralbrain.v1.RecentSeedsRequest
+	12, // 23: neuralbrain.v1.NeuralBrain.CreateContext:input_type -> neuralbrain.v1.CreateContextRequest
+	13, // 24: neuralbrain.v1.NeuralBrain.GetContext:input_type -> neuralbrain.v1.GetContextRequest
+	14, // 25: neuralbrain.v1.NeuralBrain.ListContexts:input_type -> neuralbrain.v1.ListContextsRequest
+	15, // 26: neuralbrain.v1.NeuralBrain.GetStats:input_type -> neuralbrain.v1.GetStatsRequest
+	4,  // 27: neuralbrain.v1.NeuralBrain.StoreSeed:output_type -> neuralbrain.v1.StoreSeedResponse
+	5,  // 28: neuralbrain.v1.NeuralBrain.BulkInsert:output_type -> neuralbrain.v1.BulkInsertResponse
+	1,  // 29: neuralbrain.v1.NeuralBrain.GetSeed:output_type -> neuralbrain.v1.Seed
+	1,  // 30: neuralbrain.v1.NeuralBrain.UpdateSeed:output_type -> neuralbrain.v1.Seed
+	2,  // 31: neuralbrain.v1.NeuralBrain.SearchSeeds:output_type -> neuralbrain.v1.SeedList
+	1,  // 32: neuralbrain.v1.NeuralBrain.SearchStream:output_type -> neuralbrain.v1.Seed
+	2,  // 33: neuralbrain.v1.NeuralBrain.RecentSeeds:output_type -> neuralbrain.v1.SeedList
+	10, // 34: neuralbrain.v1.NeuralBrain.CreateContext:output_type -> neuralbrain.v1.AgentContext
+	10, // 35: neuralbrain.v1.NeuralBrain.GetContext:output_type -> neuralbrain.v1.AgentContext
+	11, // 36: neuralbrain.v1.NeuralBrain.ListContexts:output_type -> neuralbrain.v1.ContextList
+	16, // 37: neuralbrain.v1.NeuralBrain.GetStats:output_type -> neuralbrain.v1.Stats
+	27, // [27:38] is the sub-list for method output_type
+	16, // [16:27] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_neuralbrain_v1_neuralbrain_proto_init() }
+func file_neuralbrain_v1_neuralbrain_proto_init() {
+	if File_neuralbrain_v1_neuralbrain_proto != nil {
+		return
+	}
+	file_neuralbrain_v1_neuralbrain_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_neuralbrain_v1_neuralbrain_proto_rawDesc), len(file_neuralbrain_v1_neuralbrain_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_neuralbrain_v1_neuralbrain_proto_goTypes,
+		DependencyIndexes: file_neuralbrain_v1_neuralbrain_proto_depIdxs,
+		MessageInfos:      file_neuralbrain_v1_neuralbrain_proto_msgTypes,
+	}.Build()
+	File_neuralbrain_v1_neuralbrain_proto = out.File
+	file_neuralbrain_v1_neuralbrain_proto_goTypes = nil
+	file_neuralbrain_v1_neuralbrain_proto_depIdxs = nil
+}
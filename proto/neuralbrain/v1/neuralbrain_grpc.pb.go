@@ -0,0 +1,513 @@
+// gRPC API of neural-brain. It mirrors the HTTP JSON API for seeds, agent contexts and stats and is served
+// on GRPC_PORT by the same binary. Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: neuralbrain/v1/neuralbrain.proto
+
+package neuralbrainv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NeuralBrain_StoreSeed_FullMethodName     = "/neuralbrain.v1.NeuralBrain/StoreSeed"
+	NeuralBrain_BulkInsert_FullMethodName    = "/neuralbrain.v1.NeuralBrain/BulkInsert"
+	NeuralBrain_GetSeed_FullMethodName       = "/neuralbrain.v1.NeuralBrain/GetSeed"
+	NeuralBrain_UpdateSeed_FullMethodName    = "/neuralbrain.v1.NeuralBrain/UpdateSeed"
+	NeuralBrain_SearchSeeds_FullMethodName   = "/neuralbrain.v1.NeuralBrain/SearchSeeds"
+	NeuralBrain_SearchStream_FullMethodName  = "/neuralbrain.v1.NeuralBrain/SearchStream"
+	NeuralBrain_RecentSeeds_FullMethodName   = "/neuralbrain.v1.NeuralBrain/RecentSeeds"
+	NeuralBrain_CreateContext_FullMethodName = "/neuralbrain.v1.NeuralBrain/CreateContext"
+	NeuralBrain_GetContext_FullMethodName    = "/neuralbrain.v1.NeuralBrain/GetContext"
+	NeuralBrain_ListContexts_FullMethodName  = "/neuralbrain.v1.NeuralBrain/ListContexts"
+	NeuralBrain_GetStats_FullMethodName      = "/neuralbrain.v1.NeuralBrain/GetStats"
+)
+
+// NeuralBrainClient is the client API for NeuralBrain service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type NeuralBrainClient interface {
+	// StoreSeed embeds and stores a seed. With deduplication enabled, a near-duplicate updates the
+	// existing seed and its ID is returned.
+	StoreSeed(ctx context.Context, in *StoreSeedRequest, opts ...grpc.CallOption) (*StoreSeedResponse, error)
+	// BulkInsert stores every streamed seed and returns the IDs in order once the client closes the stream.
+	// The seeds are stored in one transaction: on error none of them is kept.
+	BulkInsert(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StoreSeedRequest, BulkInsertResponse], error)
+	GetSeed(ctx context.Context, in *GetSeedRequest, opts ...grpc.CallOption) (*Seed, error)
+	// UpdateSeed overwrites content and metadata and re-embeds the seed.
+	UpdateSeed(ctx context.Context, in *UpdateSeedRequest, opts ...grpc.CallOption) (*Seed, error)
+	SearchSeeds(ctx context.Context, in *SearchSeedsRequest, opts ...grpc.CallOption) (*SeedList, error)
+	// SearchStream sends the search hits one by one, most similar first.
+	SearchStream(ctx context.Context, in *SearchSeedsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Seed], error)
+	RecentSeeds(ctx context.Context, in *RecentSeedsRequest, opts ...grpc.CallOption) (*SeedList, error)
+	CreateContext(ctx context.Context, in *CreateContextRequest, opts ...grpc.CallOption) (*AgentContext, error)
+	GetContext(ctx context.Context, in *GetContextRequest, opts ...grpc.CallOption) (*AgentContext, error)
+	ListContexts(ctx context.Context, in *ListContextsRequest, opts ...grpc.CallOption) (*ContextList, error)
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*Stats, error)
+}
+
+type neuralBrainClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNeuralBrainClient(cc grpc.ClientConnInterface) NeuralBrainClient {
+	return &neuralBrainClient{cc}
+}
+
+func (c *neuralBrainClient) StoreSeed(ctx context.Context, in *StoreSeedRequest, opts ...grpc.CallOption) (*StoreSeedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StoreSeedResponse)
+	err := c.cc.Invoke(ctx, NeuralBrain_StoreSeed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *neuralBrainClient) BulkInsert(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StoreSeedRequest, BulkInsertResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NeuralBrain_ServiceDesc.Streams[0], NeuralBrain_BulkInsert_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StoreSeedRequest, BulkInsertResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NeuralBrain_BulkInsertClient = grpc.ClientStreamingClient[StoreSeedRequest, BulkInsertResponse]
+
+func (c *neuralBrainClient) GetSeed(ctx context.Context, in *GetSeedRequest, opts ...grpc.CallOption) (*Seed, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Seed)
+	err := c.cc.Invoke(ctx, NeuralBrain_GetSeed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *neuralBrainClient) UpdateSeed(ctx context.Context, in *UpdateSeedRequest, opts ...grpc.CallOption) (*Seed, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Seed)
+	err := c.cc.Invoke(ctx, NeuralBrain_UpdateSeed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *neuralBrainClient) SearchSeeds(ctx context.Context, in *SearchSeedsRequest, opts ...grpc.CallOption) (*SeedList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SeedList)
+	err := c.cc.Invoke(ctx, NeuralBrain_SearchSeeds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *neuralBrainClient) SearchStream(ctx context.Context, in *SearchSeedsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Seed], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NeuralBrain_ServiceDesc.Streams[1], NeuralBrain_SearchStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SearchSeedsRequest, Seed]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NeuralBrain_SearchStreamClient = grpc.ServerStreamingClient[Seed]
+
+func (c *neuralBrainClient) RecentSeeds(ctx context.Context, in *RecentSeedsRequest, opts ...grpc.CallOption) (*SeedList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SeedList)
+	err := c.cc.Invoke(ctx, NeuralBrain_RecentSeeds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *neuralBrainClient) CreateContext(ctx context.Context, in *CreateContextRequest, opts ...grpc.CallOption) (*AgentContext, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentContext)
+	err := c.cc.Invoke(ctx, NeuralBrain_CreateContext_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *neuralBrainClient) GetContext(ctx context.Context, in *GetContextRequest, opts ...grpc.CallOption) (*AgentContext, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentContext)
+	err := c.cc.Invoke(ctx, NeuralBrain_GetContext_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *neuralBrainClient) ListContexts(ctx context.Context, in *ListContextsRequest, opts ...grpc.CallOption) (*ContextList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ContextList)
+	err := c.cc.Invoke(ctx, NeuralBrain_ListContexts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *neuralBrainClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*Stats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Stats)
+	err := c.cc.Invoke(ctx, NeuralBrain_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NeuralBrainServer is the server API for NeuralBrain service.
+// All implementations must embed UnimplementedNeuralBrainServer
+// for forward compatibility.
+type NeuralBrainServer interface {
+	// StoreSeed embeds and stores a seed. With deduplication enabled, a near-duplicate updates the
+	// existing seed and its ID is returned.
+	StoreSeed(context.Context, *StoreSeedRequest) (*StoreSeedResponse, error)
+	// BulkInsert stores every streamed seed and returns the IDs in order once the client closes the stream.
+	// The seeds are stored in one transaction: on error none of them is kept.
+	BulkInsert(grpc.ClientStreamingServer[StoreSeedRequest, BulkInsertResponse]) error
+	GetSeed(context.Context, *GetSeedRequest) (*Seed, error)
+	// UpdateSeed overwrites content and metadata and re-embeds the seed.
+	UpdateSeed(context.Context, *UpdateSeedRequest) (*Seed, error)
+	SearchSeeds(context.Context, *SearchSeedsRequest) (*SeedList, error)
+	// SearchStream sends the search hits one by one, most similar first.
+	SearchStream(*SearchSeedsRequest, grpc.ServerStreamingServer[Seed]) error
+	RecentSeeds(context.Context, *RecentSeedsRequest) (*SeedList, error)
+	CreateContext(context.Context, *CreateContextRequest) (*AgentContext, error)
+	GetContext(context.Context, *GetContextRequest) (*AgentContext, error)
+	ListContexts(context.Context, *ListContextsRequest) (*ContextList, error)
+	GetStats(context.Context, *GetStatsRequest) (*Stats, error)
+	mustEmbedUnimplementedNeuralBrainServer()
+}
+
+// UnimplementedNeuralBrainServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNeuralBrainServer struct{}
+
+func (UnimplementedNeuralBrainServer) StoreSeed(context.Context, *StoreSeedRequest) (*StoreSeedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StoreSeed not implemented")
+}
+func (UnimplementedNeuralBrainServer) BulkInsert(grpc.ClientStreamingServer[StoreSeedRequest, BulkInsertResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method BulkInsert not implemented")
+}
+func (UnimplementedNeuralBrainServer) GetSeed(context.Context, *GetSeedRequest) (*Seed, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSeed not implemented")
+}
+func (UnimplementedNeuralBrainServer) UpdateSeed(context.Context, *UpdateSeedRequest) (*Seed, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSeed not implemented")
+}
+func (UnimplementedNeuralBrainServer) SearchSeeds(context.Context, *SearchSeedsRequest) (*SeedList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchSeeds not implemented")
+}
+func (UnimplementedNeuralBrainServer) SearchStream(*SearchSeedsRequest, grpc.ServerStreamingServer[Seed]) error {
+	return status.Errorf(codes.Unimplemented, "method SearchStream not implemented")
+}
+func (UnimplementedNeuralBrainServer) RecentSeeds(context.Context, *RecentSeedsRequest) (*SeedList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecentSeeds not implemented")
+}
+func (UnimplementedNeuralBrainServer) CreateContext(context.Context, *CreateContextRequest) (*AgentContext, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateContext not implemented")
+}
+func (UnimplementedNeuralBrainServer) GetContext(context.Context, *GetContextRequest) (*AgentContext, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetContext not implemented")
+}
+func (UnimplementedNeuralBrainServer) ListContexts(context.Context, *ListContextsRequest) (*ContextList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListContexts not implemented")
+}
+func (UnimplementedNeuralBrainServer) GetStats(context.Context, *GetStatsRequest) (*Stats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedNeuralBrainServer) mustEmbedUnimplementedNeuralBrainServer() {}
+func (UnimplementedNeuralBrainServer) testEmbeddedByValue()                     {}
+
+// UnsafeNeuralBrainServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NeuralBrainServer will
+// result in compilation errors.
+type UnsafeNeuralBrainServer interface {
+	mustEmbedUnimplementedNeuralBrainServer()
+}
+
+func RegisterNeuralBrainServer(s grpc.ServiceRegistrar, srv NeuralBrainServer) {
+	// If the following call pancis, it indicates UnimplementedNeuralBrainServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NeuralBrain_ServiceDesc, srv)
+}
+
+func _NeuralBrain_StoreSeed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreSeedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeuralBrainServer).StoreSeed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NeuralBrain_StoreSeed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeuralBrainServer).StoreSeed(ctx, req.(*StoreSeedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NeuralBrain_BulkInsert_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NeuralBrainServer).BulkInsert(&grpc.GenericServerStream[StoreSeedRequest, BulkInsertResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NeuralBrain_BulkInsertServer = grpc.ClientStreamingServer[StoreSeedRequest, BulkInsertResponse]
+
+func _NeuralBrain_GetSeed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSeedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeuralBrainServer).GetSeed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NeuralBrain_GetSeed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeuralBrainServer).GetSeed(ctx, req.(*GetSeedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NeuralBrain_UpdateSeed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSeedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeuralBrainServer).UpdateSeed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NeuralBrain_UpdateSeed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeuralBrainServer).UpdateSeed(ctx, req.(*UpdateSeedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NeuralBrain_SearchSeeds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchSeedsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeuralBrainServer).SearchSeeds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NeuralBrain_SearchSeeds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeuralBrainServer).SearchSeeds(ctx, req.(*SearchSeedsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NeuralBrain_SearchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchSeedsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NeuralBrainServer).SearchStream(m, &grpc.GenericServerStream[SearchSeedsRequest, Seed]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NeuralBrain_SearchStreamServer = grpc.ServerStreamingServer[Seed]
+
+func _NeuralBrain_RecentSeeds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecentSeedsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeuralBrainServer).RecentSeeds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NeuralBrain_RecentSeeds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeuralBrainServer).RecentSeeds(ctx, req.(*RecentSeedsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NeuralBrain_CreateContext_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateContextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeuralBrainServer).CreateContext(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NeuralBrain_CreateContext_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeuralBrainServer).CreateContext(ctx, req.(*CreateContextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NeuralBrain_GetContext_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetContextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeuralBrainServer).GetContext(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NeuralBrain_GetContext_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeuralBrainServer).GetContext(ctx, req.(*GetContextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NeuralBrain_ListContexts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListContextsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeuralBrainServer).ListContexts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NeuralBrain_ListContexts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeuralBrainServer).ListContexts(ctx, req.(*ListContextsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NeuralBrain_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeuralBrainServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NeuralBrain_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeuralBrainServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NeuralBrain_ServiceDesc is the grpc.ServiceDesc for NeuralBrain service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NeuralBrain_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "neuralbrain.v1.NeuralBrain",
+	HandlerType: (*NeuralBrainServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StoreSeed",
+			Handler:    _NeuralBrain_StoreSeed_Handler,
+		},
+		{
+			MethodName: "GetSeed",
+			Handler:    _NeuralBrain_GetSeed_Handler,
+		},
+		{
+			MethodName: "UpdateSeed",
+			Handler:    _NeuralBrain_UpdateSeed_Handler,
+		},
+		{
+			MethodName: "SearchSeeds",
+			Handler:    _NeuralBrain_SearchSeeds_Handler,
+		},
+		{
+			MethodName: "RecentSeeds",
+			Handler:    _NeuralBrain_RecentSeeds_Handler,
+		},
+		{
+			MethodName: "CreateContext",
+			Handler:    _NeuralBrain_CreateContext_Handler,
+		},
+		{
+			MethodName: "GetContext",
+			Handler:    _NeuralBrain_GetContext_Handler,
+		},
+		{
+			MethodName: "ListContexts",
+			Handler:    _NeuralBrain_ListContexts_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _NeuralBrain_GetStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BulkInsert",
+			Handler:       _NeuralBrain_BulkInsert_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SearchStream",
+			Handler:       _NeuralBrain_SearchStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "neuralbrain/v1/neuralbrain.proto",
+}
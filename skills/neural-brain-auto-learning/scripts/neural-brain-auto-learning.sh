@@ -1,39 +1,50 @@
 #!/bin/bash
-# Auto-Learning - Lernt aus Fehlern
+# Auto-Learning - Lernt aus Fehlern (Regel-Engine im Server, /learning/*)
+# Usage: ./neural-brain-auto-learning.sh [run|dry-run|init|rules|log]
 
 BASE_URL="${NEURAL_BRAIN_URL:-http://localhost:9124}"
 
-echo "=== Auto-Learning ==="
-
-# 1. Neueste Seeds abrufen
-SEEDS=$(curl -s "${BASE_URL}/seeds/recent?limit=100")
-
-# Prüfen ob Antwort leer ist
-if [ -z "$SEEDS" ] || [ "$SEEDS" = "null" ]; then
-  echo "Fehler: Keine Antwort von Neural Brain"
-  exit 1
-fi
-
-# 2. Muster erkennen (mit Fallback auf 0 wenn leer)
-# WICHTIG: Filtere Seeds heraus, die die KI selbst generiert hat (Feedback-Loop-Vermeidung!)
-CARSTEN=$(echo "$SEEDS" | jq '[.[]? | select(.metadata.type != "metrik" and .metadata.type != "learning") | select(.content? | contains("Carsten"))] | length' 2>/dev/null || echo "0")
-JARVIS=$(echo "$SEEDS" | jq '[.[]? | select(.metadata.type != "metrik" and .metadata.type != "learning") | select(.content? | contains("JARVIS") or .content? | contains("ich bin"))] | length' 2>/dev/null || echo "0")
-ERRORS=$(echo "$SEEDS" | jq '[.[]? | select(.metadata.type != "metrik" and .metadata.type != "learning") | select(.content? | contains("falsch") or .content? | contains("nicht") or .content? | contains("Error"))] | length' 2>/dev/null || echo "0")
-
-echo "Carsten: $CARSTEN"
-echo "JARVIS: $JARVIS"
-echo "Fehler: $ERRORS"
-
-# 3. Lernregel erstellen wenn genug Fehler
-if [ -n "$ERRORS" ] && [ "$ERRORS" -gt 5 ] 2>/dev/null; then
-  echo "Mehr Fehler erkannt - speichere Lernregel"
-  PAYLOAD=$(jq -n --arg content "Auto-Learning: $ERRORS Fehler gefunden. Mehr darauf achten." \
-    --argjson meta "{\"type\":\"learning\",\"fehler\":$ERRORS}" \
-    '{content: $content, metadata: $meta}')
-  curl -s -X POST "${BASE_URL}/seeds" \
-    -H "Content-Type: application/json" \
-    -d "$PAYLOAD" \
-    > /dev/null 2>&1
-fi
-
-echo "Learning abgeschlossen."
+case "${1:-run}" in
+    init)
+        # Legt die früher fest verdrahteten Muster als Regeln an (409 = existiert bereits)
+        for rule in \
+            '{"name":"fehler","kind":"lexical","pattern":"(?i)falsch|nicht|error","windowHours":24,"minCount":6,"action":"learning_seed","actionParams":{"content":"Auto-Learning: {count} Fehler gefunden. Mehr darauf achten.","importance":6}}' \
+            '{"name":"carsten","kind":"lexical","pattern":"Carsten","windowHours":24,"minCount":1,"action":"tag","actionParams":{"tag":"carsten"}}' \
+            '{"name":"identitaet","kind":"semantic","pattern":"Ich bin JARVIS","threshold":0.85,"windowHours":24,"minCount":1,"action":"tag","actionParams":{"tag":"identity"}}'
+        do
+            echo "$rule" | curl -s -X POST "${BASE_URL}/learning/rules" \
+                -H "Content-Type: application/json" -d @- | jq -c .
+        done
+        ;;
+
+    rules)
+        curl -s "${BASE_URL}/learning/rules" | jq -r '
+            .[] | "[\(if .enabled then "on" else "off" end)] \(.id) \(.name) (\(.kind): \(.pattern)) ≥\(.minCount)/\(.windowHours)h → \(.action)"'
+        ;;
+
+    log)
+        curl -s "${BASE_URL}/learning/evaluations?limit=${2:-20}" | jq -r '
+            .[] | "\(.evaluatedAt) rule \(.ruleId): \(.matchCount) Treffer\(if .fired then " → " + (.result // "") else "" end)\(if .error then " FEHLER: " + .error else "" end)"'
+        ;;
+
+    run|dry-run)
+        echo "=== Auto-Learning ==="
+        dry=false
+        [ "$1" = "dry-run" ] && dry=true
+
+        RESULT=$(jq -n --argjson dry "$dry" '{dryRun: $dry}' | curl -s -X POST "${BASE_URL}/learning/evaluate" \
+            -H "Content-Type: application/json" -d @-)
+        if [ -z "$RESULT" ] || ! echo "$RESULT" | jq -e 'type == "array"' > /dev/null 2>&1; then
+            echo "Fehler: Keine Antwort von Neural Brain: $RESULT"
+            exit 1
+        fi
+
+        echo "$RESULT" | jq -r '.[] | "\(.ruleName): \(.matchCount)\(if .fired then " → " + (.result // "") else "" end)\(if .error then " FEHLER: " + .error else "" end)"'
+        echo "Learning abgeschlossen."
+        ;;
+
+    *)
+        echo "Usage: neural-brain-auto-learning.sh [run|dry-run|init|rules|log [LIMIT]]"
+        exit 1
+        ;;
+esac
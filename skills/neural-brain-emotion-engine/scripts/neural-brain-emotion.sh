@@ -13,12 +13,6 @@ format_json() {
     fi
 }
 
-clamp() {
-    local val="$1"
-    # bc is needed for float math
-    echo "$val" | bc -l | awk '{if ($1 < 0.0) print "0.0"; else if ($1 > 10.0) print "10.0"; else printf "%.1f", $1}'
-}
-
 # Commands
 case "${1:-}" in
     get)
@@ -28,17 +22,8 @@ case "${1:-}" in
             exit 1
         fi
         
-        # Get the latest "working" memory context for emotions
-        result=$(curl -s -X GET "${BASE_URL}/agent-contexts?agentId=${agent_id}-emotion")
-        
-        # Parse out the latest payload
-        latest=$(echo "$result" | jq -r 'map(select(.memoryType == "working")) | sort_by(.createdAt) | last')
-        
-        if [[ "$latest" == "null" || -z "$latest" ]]; then
-            echo '{"valence": 5.0, "arousal": 5.0, "dominance": 5.0, "reason": "baseline"}'
-        else
-            echo "$latest" | jq -r '.payload'
-        fi
+        # Current state with decay toward the baseline applied server-side
+        curl -s -X GET "${BASE_URL}/agents/${agent_id}/emotion" | jq -c '{valence, arousal, dominance, reason}'
         ;;
         
     set)
@@ -53,23 +38,18 @@ case "${1:-}" in
             exit 1
         fi
         
-        # Force clamp between 0.0 and 10.0
-        cv=$(clamp "$v")
-        ca=$(clamp "$a")
-        cd=$(clamp "$d")
-        
-        payload="{\"valence\": ${cv}, \"arousal\": ${ca}, \"dominance\": ${cd}, \"reason\": \"${reason}\"}"
-        
-        response=$(curl -s -X POST "${BASE_URL}/agent-contexts" \
+        payload=$(jq -n --argjson v "$v" --argjson a "$a" --argjson d "$d" --arg r "$reason" \
+            '{valence: $v, arousal: $a, dominance: $d, reason: $r}')
+        response=$(curl -s -X PUT "${BASE_URL}/agents/${agent_id}/emotion" \
             -H "Content-Type: application/json" \
-            -d "{\"agentId\":\"${agent_id}-emotion\",\"memoryType\":\"working\",\"data\":${payload}}")
+            -d "$payload")
             
-        # Optional: Print response for debugging if it fails
-        if [[ "$response" != *"id"* ]]; then
-            echo "Error saving context: $response" >&2
+        if [[ "$response" != *"valence"* ]]; then
+            echo "Error saving emotion: $response" >&2
+            exit 1
         fi
             
-        echo "$payload"
+        echo "$response" | jq -c '{valence, arousal, dominance, reason}'
         ;;
         
     shift)
@@ -84,21 +64,30 @@ case "${1:-}" in
             exit 1
         fi
         
-        # Fetch current state
-        current=$( "$0" get "$agent_id" )
-        
-        # Extract individual floats
-        cv=$(echo "$current" | jq -r '.valence')
-        ca=$(echo "$current" | jq -r '.arousal')
-        cd=$(echo "$current" | jq -r '.dominance')
-        
-        # Add delta
-        nv=$(echo "$cv + $dv" | bc -l)
-        na=$(echo "$ca + $da" | bc -l)
-        nd=$(echo "$cd + $dd" | bc -l)
-        
-        # Clamp and Set
-        "$0" set "$agent_id" "$nv" "$na" "$nd" "$reason"
+        # Deltas are applied to the decayed current state and clamped to 0-10 server-side
+        payload=$(jq -n --argjson v "$dv" --argjson a "$da" --argjson d "$dd" --arg r "$reason" \
+            '{valence: $v, arousal: $a, dominance: $d, reason: $r}')
+        response=$(curl -s -X POST "${BASE_URL}/agents/${agent_id}/emotion/events" \
+            -H "Content-Type: application/json" \
+            -d "$payload")
+
+        if [[ "$response" != *"valence"* ]]; then
+            echo "Error applying emotion event: $response" >&2
+            exit 1
+        fi
+
+        echo "$response" | jq -c '{valence, arousal, dominance, reason}'
+        ;;
+
+    history)
+        agent_id="$2"
+        limit="${3:-50}"
+        if [[ -z "$agent_id" ]]; then
+            echo "Usage: emotion.sh history AGENT_ID [LIMIT]"
+            exit 1
+        fi
+
+        curl -s -X GET "${BASE_URL}/agents/${agent_id}/emotion/history?limit=${limit}" | format_json
         ;;
         
     *)
@@ -108,5 +97,6 @@ case "${1:-}" in
         echo "  get AGENT_ID                       Fetch current state"
         echo "  set AGENT_ID V A D [REASON]        Set absolute state (0.0 to 10.0)"
         echo "  shift AGENT_ID dV dA dD [REASON]   Apply delta shift to state"
+        echo "  history AGENT_ID [LIMIT]           Show recent state changes"
         ;;
 esac
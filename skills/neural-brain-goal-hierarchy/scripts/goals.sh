@@ -9,75 +9,65 @@ case "${1:-}" in
         title="$2"
         description="$3"
         parent_id="${4:-none}"
+        priority="${5:-0}"
         
         if [[ -z "$description" ]]; then
-            echo "Usage: goals.sh add \"<title>\" \"<description>\" [parent_id]"
+            echo "Usage: goals.sh add \"<title>\" \"<description>\" [parent_id] [priority]"
             exit 1
         fi
         
-        # 1. Create the Seed with both Title and Description
-        content="Goal: ${title}\nDescription: ${description}"
-        create_res=$(curl -s -X POST "${BASE_URL}/seeds" \
+        payload=$(jq -n --arg t "$title" --arg d "$description" --arg p "$parent_id" --argjson prio "$priority" \
+            '{title: $t, description: $d, priority: $prio} + (if $p == "none" then {} else {parentId: ($p | tonumber)} end)')
+        create_res=$(curl -s -X POST "${BASE_URL}/goals" \
             -H "Content-Type: application/json" \
-            -d "{\"content\":\"${content}\"}")
+            -d "$payload")
             
-        seed_id=$(echo "$create_res" | jq -r '.id')
+        goal_id=$(echo "$create_res" | jq -r '.id')
         
-        if [[ -z "$seed_id" || "$seed_id" == "null" ]]; then
-            echo "Error creating goal seed: $create_res" >&2
+        if [[ -z "$goal_id" || "$goal_id" == "null" ]]; then
+            echo "Error creating goal: $create_res" >&2
             exit 1
         fi
-        
-        # 2. Attach metadata (status, parent_id)
-        if [[ "$parent_id" == "none" ]]; then
-            meta_payload="{\"status\": \"active\"}"
-        else
-            meta_payload="{\"status\": \"active\", \"parent_id\": \"${parent_id}\"}"
-        fi
-        
-        curl -s -X PATCH "${BASE_URL}/seeds/${seed_id}/metadata" \
-            -H "Content-Type: application/json" \
-            -d "$meta_payload" > /dev/null
-            
-        # 3. Apply goal tags
-        curl -s -X POST "${BASE_URL}/seeds/${seed_id}/tags" \
-            -H "Content-Type: application/json" \
-            -d '["goal"]' > /dev/null
             
-        echo "Successfully created Goal ID: ${seed_id}"
+        echo "Successfully created Goal ID: ${goal_id}"
         echo "Title: ${title}"
         ;;
         
     list)
         status="${2:-all}"
         
-        # Get all seeds. This relies on the endpoint fetching all metadata.
-        # We fetch 100 which should cover immediate active goals
-        result=$(curl -s "${BASE_URL}/search?q=Goal&limit=100")
-        
-        # Cleanly format output using jq
-        # We filter for anything with the 'goal' tag.
-        # Then we filter by status if provided.
-        echo "$result" | jq -r --arg filter_status "$status" '
-            map(select(.metadata.tags != null and (.metadata.tags | index("goal")) != null)) |
-            if $filter_status != "all" then map(select(.metadata.status == $filter_status)) else . end |
+        curl -s "${BASE_URL}/goals?status=${status}" | jq -r '
             .[] |
-            "[\(.metadata.status)] ID: \(.id) | Parent: \(.metadata.parent_id // "None") | \(.content | gsub("\n"; " - "))"
+            "[\(.status)] ID: \(.id) | Parent: \(.parentId // "None") | P\(.priority) | Goal: \(.title) - \(.description)"
+        '
+        ;;
+
+    tree)
+        status="${2:-all}"
+
+        curl -s "${BASE_URL}/goals/tree?status=${status}" | jq -r '
+            def walk_tree($depth): ("  " * $depth) + "[\(.status)] \(.id): \(.title)", (.children // [] | .[] | walk_tree($depth + 1));
+            .[] | walk_tree(0)
         '
         ;;
         
     complete)
-        seed_id="$2"
-        if [[ -z "$seed_id" ]]; then
-            echo "Usage: goals.sh complete <seed_id>"
+        goal_id="$2"
+        if [[ -z "$goal_id" ]]; then
+            echo "Usage: goals.sh complete <goal_id>"
             exit 1
         fi
         
-        curl -s -X PATCH "${BASE_URL}/seeds/${seed_id}/metadata" \
+        result=$(curl -s -X PATCH "${BASE_URL}/goals/${goal_id}" \
             -H "Content-Type: application/json" \
-            -d '{"status": "completed"}' > /dev/null
+            -d '{"status": "completed"}')
+
+        if [[ "$(echo "$result" | jq -r '.status // empty')" != "ok" ]]; then
+            echo "Error completing goal: $result" >&2
+            exit 1
+        fi
             
-        echo "Goal $seed_id marked as completed."
+        echo "Goal $goal_id marked as completed (cascaded: $(echo "$result" | jq -c '.completed'))."
         ;;
         
     evaluate)
@@ -87,34 +77,16 @@ case "${1:-}" in
             exit 1
         fi
         
-        # Get all active goals
-        goals_json=$(curl -s "${BASE_URL}/search?q=*&limit=100" 2>/dev/null | jq -r '
-            map(select(.metadata != null and .metadata.tags != null and (.metadata.tags | index("goal")) != null and .metadata.status == "active"))
-        ' 2>/dev/null)
-        
-        if [[ -z "$goals_json" || "$goals_json" == "[]" || "$goals_json" == "null" ]]; then
+        # One request scores the action against every active goal's embedding
+        result=$(jq -n --arg a "$action" '{action: $a}' | curl -s -X POST "${BASE_URL}/goals/evaluate" \
+            -H "Content-Type: application/json" -d @- 2>/dev/null)
+
+        if [[ "$(echo "$result" | jq '.goals | length' 2>/dev/null)" == "0" || -z "$result" ]]; then
             echo "0.5"
             exit 0
         fi
-        
-        # Count goals and calculate max score
-        goal_count=$(echo "$goals_json" | jq 'length')
-        max_score=0
-        
-        for i in $(seq 0 $((goal_count - 1))); do
-            goal_content=$(echo "$goals_json" | jq -r ".[$i].content // \"\"")
-            goal_id=$(echo "$goals_json" | jq -r ".[$i].id // \"\"")
-            
-            # Query against goal content
-            score=$(curl -s -X POST "${BASE_URL}/seeds/query" \
-                -H "Content-Type: application/json" \
-                -d "{\"query\":\"${action}\",\"limit\":5,\"threshold\":0.0}" 2>/dev/null | \
-                jq -r '.results[0].similarity // 0')
-            
-            if [ -n "$score" ]; then
-                [ $(echo "$score > $max_score" | bc -l) -eq 1 ] && max_score=$score
-            fi
-        done
+
+        max_score=$(echo "$result" | jq -r '.score // 0')
         
         # Keyword boosting
         action_lower=$(echo "$action" | tr '[:upper:]' '[:lower:]')
@@ -132,9 +104,10 @@ case "${1:-}" in
     *)
         echo "Intrinsic Motivation (Goal Hierarchy Engine)"
         echo "Commands:"
-        echo "  add \"TITLE\" \"DESC\" [PARENT_ID]        Create a new Goal"
-        echo "  list [STATUS]                         List goals (status: all|active|completed)"
-        echo "  complete SEED_ID                      Mark a generic Goal as completed"
-        echo "  evaluate \"ACTION\"                     Use Vector Search to score (0.0=bad, 1.0=good) an action against active goals"
+        echo "  add \"TITLE\" \"DESC\" [PARENT_ID] [PRIORITY]  Create a new Goal"
+        echo "  list [STATUS]                         List goals (status: all|active|paused|completed|abandoned)"
+        echo "  tree [STATUS]                         Show the goal hierarchy"
+        echo "  complete GOAL_ID                      Mark a Goal (and its sub-goals) as completed"
+        echo "  evaluate \"ACTION\"                     Score (0.0=bad, 1.0=good) an action against active goals"
         ;;
 esac
@@ -33,16 +33,12 @@ AI_RESP="${OPENCLAW_AI_RESPONSE:-}"
 
 [[ -z "$USER_MSG" && -z "$AI_RESP" ]] && exit 0
 
-TS=$(date -u +"%Y-%m-%dT%H:%M:%SZ")
-TITLE="Conversation - ${TS}"
-CONTENT="User: ${USER_MSG}
-Assistant: ${AI_RESP}"
-
 QUERY_PARAMS="appId=${APP_ID}&externalUserId=${EXTERNAL_USER_ID}"
-
-# Save as seed in background
-curl -s -X POST "${BASE_URL}/seeds?${QUERY_PARAMS}" \
-    -F "text=[\"${CONTENT}\"]" \
-    -F 'textTypes=["text"]' \
-    -F 'textSources=["auto_capture"]' \
-    -F "textTitles=[\"${TITLE}\"]" > /dev/null 2>&1 &
+TURN_ID="turn-$(date -u +%Y%m%dT%H%M%SZ)-$$"
+
+# POST /capture extracts memory statements server-side, filters chatter and
+# skips facts the tenant already knows. jq builds the body so text is JSON-escaped.
+jq -n --arg u "$USER_MSG" --arg a "$AI_RESP" --arg t "$TURN_ID" \
+    '{turnId: $t, messages: [{role: "user", text: $u}, {role: "assistant", text: $a}]}' \
+    | curl -s -X POST "${BASE_URL}/capture?${QUERY_PARAMS}" \
+        -H "Content-Type: application/json" -d @- > /dev/null 2>&1 &
@@ -33,17 +33,14 @@ USER_MESSAGE="${OPENCLAW_USER_MESSAGE:-}"
 
 QUERY_PARAMS="appId=${APP_ID}&externalUserId=${EXTERNAL_USER_ID}"
 
-# Query for relevant memories
-# POST /seeds/query with JSON body; response has .results[].content
-response=$(curl -s -X POST "${BASE_URL}/seeds/query?${QUERY_PARAMS}" \
-    -H "Content-Type: application/json" \
-    -d "{\"query\":\"${USER_MESSAGE}\",\"limit\":5,\"threshold\":0.5}" 2>/dev/null || echo "{\"results\":[]}")
-
-memories=$(echo "$response" | jq -r '.results[]?.content // empty' 2>/dev/null | head -500)
+# POST /recall does search (threshold, MMR, recency), dedupe and budgeting server-side.
+# jq builds the body so the user message is JSON-escaped properly.
+memories=$(jq -n --arg m "$USER_MESSAGE" '{message: $m, limit: 5, threshold: 0.5, maxTokens: 500, format: "plain"}' \
+    | curl -s -X POST "${BASE_URL}/recall?${QUERY_PARAMS}" -H "Content-Type: application/json" -d @- 2>/dev/null \
+    | jq -r '.block // empty' 2>/dev/null)
 
 if [[ -n "$memories" ]]; then
     echo "---"
-    echo "RECALLED MEMORIES:"
-    echo "$memories"
+    printf "%s" "$memories"
     echo "---"
 fi
@@ -44,13 +44,20 @@ CRON_COUNT=$(ls -1 $LOG_DIR/cron-*.log 2>/dev/null | wc -l)
 CRON_ERRORS=$(grep -rci "error\|fail" $LOG_DIR/ 2>/dev/null | cut -d: -f2 | paste -sd+ | bc 2>/dev/null || echo "0")
 echo "Cron-Jobs: $CRON_COUNT | Fehler: $CRON_ERRORS"
 
-# 6. Speichern als Seed
-TIMESTAMP=$(date "+%Y-%m-%d %H:%M")
-PAYLOAD=$(jq -n --arg content "Metriken ${TIMESTAMP}: Seeds=$SEEDS, Contexts=$CONTEXTS, Goals=$ACTIVE_GOALS, Emotion=V${VALENCE}A${AROUSAL}D${DOMINANCE}" \
-  --argjson meta "{\"type\":\"metrik\",\"seeds\":${SEEDS},\"contexts\":${CONTEXTS},\"goals\":${ACTIVE_GOALS},\"valence\":${VALENCE},\"arousal\":${AROUSAL},\"dominance\":${DOMINANCE}}" \
-  '{content: $content, metadata: $meta}')
+# 6. Speichern als Metrik-Punkte (Zeitreihe statt Freitext-Seed)
+PAYLOAD=$(jq -n --argjson seeds "$SEEDS" --argjson contexts "$CONTEXTS" --argjson goals "$ACTIVE_GOALS" \
+  --argjson v "$VALENCE" --argjson a "$AROUSAL" --argjson d "$DOMINANCE" --argjson cron_errors "${CRON_ERRORS:-0}" \
+  '{points: [
+    {name: "skill_seeds", value: $seeds},
+    {name: "skill_contexts", value: $contexts},
+    {name: "active_goals", value: $goals},
+    {name: "emotion_valence", value: $v, labels: {agent: "jarvis"}},
+    {name: "emotion_arousal", value: $a, labels: {agent: "jarvis"}},
+    {name: "emotion_dominance", value: $d, labels: {agent: "jarvis"}},
+    {name: "cron_errors", value: $cron_errors}
+  ]}')
 
-curl -s -X POST "${BASE_URL}/seeds" \
+curl -s -X POST "${BASE_URL}/metrics/points" \
     -H "Content-Type: application/json" \
     -d "$PAYLOAD" \
     > /dev/null 2>&1
@@ -19,67 +19,52 @@ case "${1:-}" in
         limit=50
         echo "Gathering recent memories from the last $hours hours..." >&2
         
-        # Calculate timestamp X hours ago
-        cutoff=$(date -d "-${hours} hours" -u +"%Y-%m-%dT%H:%M:%SZ" 2>/dev/null || date -v-${hours}H -u +"%Y-%m-%dT%H:%M:%SZ")
-        
-        # Using GET /seeds/recent is more efficient than a full vector search for gathering chronological history
-        result=$(curl -s "${BASE_URL}/seeds/recent?limit=${limit}")
-            
-        # Filter and cleanly format the output for an LLM prompt
-        # Ignore system-generated seeds (metrik, learning) to prevent AI feedback loops
-        echo "$result" | jq -r --arg cutoff "$cutoff" '
-            .[] 
-            | select(.created_at >= $cutoff)
-            | select(.metadata.type != "metrik" and .metadata.type != "learning")
-            | "[\(.created_at)] (ID: \(.id)): \(.content) | Tags: \(.metadata.tags // [])"
-        '
+        # The digest is windowed, filtered (no metrik/learning/belief seeds, to prevent AI feedback loops)
+        # and de-duplicated server-side; .text is already formatted for an LLM prompt.
+        curl -s "${BASE_URL}/reflection/digest?hours=${hours}&limit=${limit}" | jq -r '.text'
         ;;
         
     commit)
         insight="$2"
         importance="${3:-8}"
         confidence="${4:-0.8}"
+        sources="${5:-}"
         
         if [[ -z "$insight" ]]; then
-            echo "Usage: reflect.sh commit \"<insight text>\" [importance] [confidence]"
+            echo "Usage: reflect.sh commit \"<insight text>\" [importance] [confidence] [source_seed_ids]"
             exit 1
         fi
         
         echo "Committing Deep Belief..." >&2
         
-        # 1. Create the base Seed
-        create_res=$(curl -s -X POST "${BASE_URL}/seeds" \
+        payload=$(jq -n --arg c "$insight" --argjson i "$importance" --argjson conf "$confidence" --arg s "$sources" \
+            '{content: $c, importance: $i, confidence: $conf, sourceSeedIds: ($s | split(",") | map(select(. != "") | tonumber))}')
+        result=$(curl -s -X POST "${BASE_URL}/beliefs" \
             -H "Content-Type: application/json" \
-            -d "{\"content\":\"${insight}\"}")
+            -d "$payload")
             
-        seed_id=$(echo "$create_res" | jq -r '.id')
+        belief_id=$(echo "$result" | jq -r '.beliefId // empty')
         
-        if [[ -z "$seed_id" || "$seed_id" == "null" ]]; then
-            echo "Error creating seed: $create_res" >&2
+        if [[ -z "$belief_id" ]]; then
+            echo "Error committing belief: $result" >&2
             exit 1
         fi
-        
-        # 2. Attach metadata (importance, confidence, source)
-        meta_payload="{\"importance\": ${importance}, \"confidence\": ${confidence}, \"source\": \"self_reflection\"}"
-        curl -s -X PATCH "${BASE_URL}/seeds/${seed_id}/metadata" \
-            -H "Content-Type: application/json" \
-            -d "$meta_payload" > /dev/null
             
-        # 3. Apply specific core-belief tags
-        curl -s -X POST "${BASE_URL}/seeds/${seed_id}/tags" \
-            -H "Content-Type: application/json" \
-            -d '["reflection", "core-belief"]' > /dev/null
-            
-        echo "Successfully committed insight as Seed ID: ${seed_id}"
+        echo "Successfully committed insight as Belief ID: ${belief_id} ($(echo "$result" | jq -r '.action'))"
         echo "Insight: \"${insight}\""
         echo "Importance: ${importance} | Confidence: ${confidence}"
         ;;
+
+    beliefs)
+        curl -s "${BASE_URL}/beliefs?status=active" | format_json
+        ;;
         
     *)
         echo "Self-Reflection (Meta-Cognition Engine)"
         echo ""
         echo "Commands:"
-        echo "  gather [HOURS]                                     Fetch raw short-term memory logs"
-        echo "  commit \"<INSIGHT>\" [IMPORTANCE] [CONFIDENCE]       Condense an insight into a Core Belief"
+        echo "  gather [HOURS]                                                 Fetch raw short-term memory logs"
+        echo "  commit \"<INSIGHT>\" [IMPORTANCE] [CONFIDENCE] [SOURCE_IDS]      Condense an insight into a Core Belief"
+        echo "  beliefs                                                        List active beliefs"
         ;;
 esac